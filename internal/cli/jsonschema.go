@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool"
+	"github.com/spf13/cobra"
+)
+
+// jsonSchemaOptions holds the flags accepted by the jsonschema command.
+var jsonSchemaOptions struct {
+	definition    string
+	schemaVersion string
+	output        string
+}
+
+func init() {
+	flags := jsonSchemaCmd.Flags()
+	addNetworkFlags(flags)
+	addSchemaFlags(flags)
+	flags.StringVar(&jsonSchemaOptions.definition, "definition", "", "Definition to convert (e.g., '#ControlCatalog'); all definitions are bundled under $defs when omitted")
+	flags.StringVar(&jsonSchemaOptions.schemaVersion, "schema-version", "latest", "Version of the Gemara module")
+	flags.StringVarP(&jsonSchemaOptions.output, "output", "o", "", "File to write the schema to (default: stdout)")
+}
+
+var jsonSchemaCmd = &cobra.Command{
+	Use:   "jsonschema",
+	Short: "Export Gemara definitions as JSON Schema (draft 2020-12)",
+	Example: `  gemara-mcp jsonschema --definition '#ControlCatalog' -o control-catalog.schema.json
+  gemara-mcp jsonschema -o gemara.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newHTTPClient()
+		if err != nil {
+			return err
+		}
+		loader, err := newSchemaLoader(client)
+		if err != nil {
+			return err
+		}
+
+		schema, err := loader.Load(cmd.Context(), jsonSchemaOptions.schemaVersion, false)
+		if err != nil {
+			return err
+		}
+
+		var doc map[string]any
+		if jsonSchemaOptions.definition == "" {
+			doc, err = tool.JSONSchemaBundle(schema)
+		} else {
+			_, doc, err = tool.DefinitionJSONSchema(schema, jsonSchemaOptions.definition)
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON Schema: %w", err)
+		}
+		data = append(data, '\n')
+
+		if jsonSchemaOptions.output == "" {
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		}
+		if err := os.WriteFile(jsonSchemaOptions.output, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write JSON Schema: %w", err)
+		}
+		return nil
+	},
+}
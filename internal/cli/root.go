@@ -3,16 +3,173 @@
 package cli
 
 import (
+	"context"
+	"crypto/ed25519"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"cuelang.org/go/mod/modconfig"
 	"github.com/gemaraproj/gemara-mcp/internal/tool"
 	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
-const defaultCacheTTL = 24 * time.Hour
+const (
+	defaultCacheTTL        = 24 * time.Hour
+	defaultHTTPTimeout     = 30 * time.Second
+	defaultMaxResponseSize = 10 << 20 // 10 MiB
+)
+
+// networkOptions holds the flags that configure outbound requests, shared by all
+// commands that reach the network.
+var networkOptions struct {
+	caFile          string
+	proxyURL        string
+	httpTimeout     time.Duration
+	maxResponseSize int64
+}
+
+// schemaOptions holds the flags that declare how Gemara module versions are verified,
+// shared by all commands that load the schema.
+var schemaOptions struct {
+	sha256       map[string]string
+	checksumsURL string
+	signatureURL string
+	publicKey    string
+}
+
+// serveOptions holds the flags accepted by the serve command.
+var serveOptions struct {
+	lexiconURL           string
+	lexiconSHA256        string
+	lexiconChecksumsURL  string
+	lexiconSignatureURL  string
+	lexiconPublicKey     string
+	artifactSHA256       map[string]string
+	artifactChecksumsURL string
+	artifactSignatureURL string
+	artifactPublicKey    string
+	logLevel             string
+}
+
+// logLevels are the MCP log levels in increasing severity.
+var logLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+func init() {
+	flags := serveCmd.Flags()
+	addNetworkFlags(flags)
+	addSchemaFlags(flags)
+	flags.StringVar(&serveOptions.lexiconURL, "lexicon-url", tool.DefaultLexiconURL, "URL of the Gemara Lexicon")
+	flags.StringVar(&serveOptions.lexiconSHA256, "lexicon-sha256", "", "Expected SHA-256 digest of the lexicon")
+	flags.StringVar(&serveOptions.lexiconChecksumsURL, "lexicon-checksums-url", "", "URL of a sha256sum-formatted checksum file listing the lexicon")
+	flags.StringVar(&serveOptions.lexiconSignatureURL, "lexicon-signature-url", "", "URL of an Ed25519 signature over the lexicon checksum file")
+	flags.StringVar(&serveOptions.lexiconPublicKey, "lexicon-public-key", "", "PEM file with the Ed25519 public key that signs the lexicon checksum file")
+	flags.StringToStringVar(&serveOptions.artifactSHA256, "artifact-sha256", nil, "Expected SHA-256 digest of an artifact fetched from a source, as SOURCE=DIGEST (repeatable)")
+	flags.StringVar(&serveOptions.artifactChecksumsURL, "artifact-checksums-url", "", "URL of a sha256sum-formatted checksum file that must list every artifact fetched from a source")
+	flags.StringVar(&serveOptions.artifactSignatureURL, "artifact-signature-url", "", "URL of an Ed25519 signature over the artifact checksum file")
+	flags.StringVar(&serveOptions.artifactPublicKey, "artifact-public-key", "", "PEM file with the Ed25519 public key that signs the artifact checksum file")
+	flags.StringVar(&serveOptions.logLevel, "log-level", "", "Level of the log messages sent to clients before they set one with logging/setLevel: one of "+strings.Join(logLevels, ", ")+" (default: none until they do)")
+}
+
+// addSchemaFlags registers the flags that declare how Gemara module versions are verified.
+func addSchemaFlags(flags *pflag.FlagSet) {
+	flags.StringToStringVar(&schemaOptions.sha256, "schema-sha256", nil, "Expected digest of a Gemara module version, as VERSION=DIGEST (repeatable)")
+	flags.StringVar(&schemaOptions.checksumsURL, "schema-checksums-url", "", "URL of a sha256sum-formatted checksum file that must list every Gemara module version loaded")
+	flags.StringVar(&schemaOptions.signatureURL, "schema-signature-url", "", "URL of an Ed25519 signature over the schema checksum file")
+	flags.StringVar(&schemaOptions.publicKey, "schema-public-key", "", "PEM file with the Ed25519 public key that signs the schema checksum file")
+}
+
+// addNetworkFlags registers the flags that configure outbound requests.
+func addNetworkFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&networkOptions.caFile, "ca-file", "", "PEM bundle of additional CA certificates to trust for outbound HTTPS")
+	flags.StringVar(&networkOptions.proxyURL, "proxy", "", "Proxy URL for outbound requests (default: taken from HTTPS_PROXY/HTTP_PROXY/NO_PROXY)")
+	flags.DurationVar(&networkOptions.httpTimeout, "http-timeout", defaultHTTPTimeout, "Timeout for outbound HTTP requests")
+	flags.Int64Var(&networkOptions.maxResponseSize, "max-response-size", defaultMaxResponseSize, "Maximum size in bytes of a fetched response body, OCI blob or git file (0 disables the limit)")
+}
+
+// newHTTPClient creates the shared HTTP client from the network flags.
+func newHTTPClient() (*fetcher.Client, error) {
+	client, err := fetcher.NewClient(fetcher.ClientOptions{
+		Timeout:     networkOptions.httpTimeout,
+		UserAgent:   "gemara-mcp/" + GetVersion(),
+		CAFile:      networkOptions.caFile,
+		ProxyURL:    networkOptions.proxyURL,
+		MaxBodySize: networkOptions.maxResponseSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP client: %w", err)
+	}
+	return client, nil
+}
+
+// newSchemaLoader creates the loader of the Gemara module from the schema flags, sharing the
+// proxy and TLS configuration of client.
+func newSchemaLoader(client *fetcher.Client) (*tool.SchemaLoader, error) {
+	digests := make(map[string]string, len(schemaOptions.sha256))
+	for version, digest := range schemaOptions.sha256 {
+		digests[tool.SchemaModuleReference(version)] = digest
+	}
+	checks, err := sourceChecks(digests, schemaOptions.checksumsURL, schemaOptions.signatureURL, schemaOptions.publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema verification: %w", err)
+	}
+	loader := tool.NewSchemaLoader(&modconfig.Config{Transport: client.Transport()})
+	return loader.WithVerification(checks, client), nil
+}
+
+// lexiconSource builds the lexicon source from the serve flags.
+func lexiconSource() (tool.Source, error) {
+	publicKey, err := readPublicKey(serveOptions.lexiconPublicKey)
+	if err != nil {
+		return tool.Source{}, err
+	}
+	source := tool.Source{
+		URL:          serveOptions.lexiconURL,
+		SHA256:       serveOptions.lexiconSHA256,
+		ChecksumsURL: serveOptions.lexiconChecksumsURL,
+		SignatureURL: serveOptions.lexiconSignatureURL,
+		PublicKey:    publicKey,
+	}
+	if err := source.Validate(); err != nil {
+		return tool.Source{}, err
+	}
+	return source, nil
+}
+
+// sourceChecks builds the verification of a kind of source from its flags.
+func sourceChecks(digests map[string]string, checksumsURL, signatureURL, publicKeyFile string) (tool.SourceChecks, error) {
+	publicKey, err := readPublicKey(publicKeyFile)
+	if err != nil {
+		return tool.SourceChecks{}, err
+	}
+	checks := tool.SourceChecks{
+		SHA256:       digests,
+		ChecksumsURL: checksumsURL,
+		SignatureURL: signatureURL,
+		PublicKey:    publicKey,
+	}
+	if err := checks.Validate(); err != nil {
+		return tool.SourceChecks{}, err
+	}
+	return checks, nil
+}
+
+// readPublicKey reads a PEM-encoded Ed25519 public key, or returns nil if file is empty.
+func readPublicKey(file string) (ed25519.PublicKey, error) {
+	if file == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+	return fetcher.ParsePublicKey(data)
+}
 
 // New creates the root command
 func New() *cobra.Command {
@@ -23,6 +180,8 @@ func New() *cobra.Command {
 	cmd.AddCommand(
 		serveCmd,
 		versionCmd,
+		jsonSchemaCmd,
+		threatCoverageCmd,
 	)
 	return cmd
 }
@@ -40,19 +199,56 @@ var serveCmd = &cobra.Command{
 	Short:   "Start the Gemara MCP server",
 	Example: "gemara-mcp serve",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newHTTPClient()
+		if err != nil {
+			return err
+		}
+
+		lexicon, err := lexiconSource()
+		if err != nil {
+			return fmt.Errorf("invalid lexicon source: %w", err)
+		}
+
+		artifacts, err := sourceChecks(serveOptions.artifactSHA256, serveOptions.artifactChecksumsURL, serveOptions.artifactSignatureURL, serveOptions.artifactPublicKey)
+		if err != nil {
+			return fmt.Errorf("invalid artifact verification: %w", err)
+		}
+		schemas, err := newSchemaLoader(client)
+		if err != nil {
+			return err
+		}
+		if serveOptions.logLevel != "" && !slices.Contains(logLevels, serveOptions.logLevel) {
+			return fmt.Errorf("unsupported log level %q: use one of %s", serveOptions.logLevel, strings.Join(logLevels, ", "))
+		}
+
 		cache := fetcher.NewCache(defaultCacheTTL)
-		advisory := tool.NewAdvisoryMode(cache)
+		advisory := tool.NewAdvisoryMode(cache, client,
+			tool.WithLexiconSource(lexicon),
+			tool.WithArtifactChecks(artifacts),
+			tool.WithSchemaLoader(schemas),
+			tool.WithLogLevel(mcp.LoggingLevel(serveOptions.logLevel)),
+		)
 
 		server := mcp.NewServer(&mcp.Implementation{
 			Name:    "gemara-mcp",
 			Title:   "Gemara MCP",
 			Version: GetVersion(),
-		}, &mcp.ServerOptions{
-			Instructions: advisory.Description(),
-		})
+		}, advisory.ServerOptions())
 
 		advisory.Register(server)
 
-		return server.Run(cmd.Context(), &mcp.StdioTransport{})
+		// Connect instead of Run so sessions start at the default log level.
+		ctx := cmd.Context()
+		session, err := server.Connect(ctx, &mcp.StdioTransport{}, advisory.SessionOptions())
+		if err != nil {
+			return err
+		}
+		stop := context.AfterFunc(ctx, func() { _ = session.Close() })
+		defer stop()
+		err = session.Wait()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
 	},
 }
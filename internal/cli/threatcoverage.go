@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool"
+	"github.com/spf13/cobra"
+)
+
+// threatCoverageOptions holds the flags accepted by the threat-coverage command.
+var threatCoverageOptions struct {
+	threatCatalog string
+	format        string
+	failOnGaps    bool
+}
+
+func init() {
+	flags := threatCoverageCmd.Flags()
+	addNetworkFlags(flags)
+	addSchemaFlags(flags)
+	flags.StringVar(&threatCoverageOptions.threatCatalog, "threat-catalog", "", "Metadata ID of a threat catalog to report on (default: all threat catalogs found)")
+	flags.StringVar(&threatCoverageOptions.format, "format", "markdown", "Output format: 'markdown' or 'json'")
+	flags.BoolVar(&threatCoverageOptions.failOnGaps, "fail-on-gaps", false, "Exit with an error when a threat is unmitigated or a control maps to an unknown threat")
+}
+
+var threatCoverageCmd = &cobra.Command{
+	Use:   "threat-coverage [dir...]",
+	Short: "Report threats no control mitigates and threat mappings to unknown threats",
+	Example: `  gemara-mcp threat-coverage
+  gemara-mcp threat-coverage catalogs threats --threat-catalog CCC --format json --fail-on-gaps`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := threatCoverageOptions.format
+		if format != "markdown" && format != "json" {
+			return fmt.Errorf("unsupported format %q: use \"markdown\" or \"json\"", format)
+		}
+		dirs := args
+		if len(dirs) == 0 {
+			dirs = []string{"."}
+		}
+
+		client, err := newHTTPClient()
+		if err != nil {
+			return err
+		}
+		loader, err := newSchemaLoader(client)
+		if err != nil {
+			return err
+		}
+		index := tool.NewWorkspaceIndex(loader)
+		if _, err := index.Scan(cmd.Context(), dirs); err != nil {
+			return err
+		}
+
+		report, err := tool.ThreatCoverageOf(index.Artifacts(), threatCoverageOptions.threatCatalog)
+		if err != nil {
+			return err
+		}
+		if err := writeThreatCoverage(cmd.OutOrStdout(), report, format); err != nil {
+			return err
+		}
+		if threatCoverageOptions.failOnGaps && (len(report.Unmitigated) > 0 || len(report.Dangling) > 0) {
+			return fmt.Errorf("%d unmitigated threats and %d mappings to unknown threats", len(report.Unmitigated), len(report.Dangling))
+		}
+		return nil
+	},
+}
+
+// writeThreatCoverage writes a threat coverage report in the given format.
+func writeThreatCoverage(w io.Writer, report tool.OutputAnalyzeThreatCoverage, format string) error {
+	if format == "markdown" {
+		_, err := io.WriteString(w, tool.RenderThreatCoverage(report))
+		return err
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode threat coverage: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,367 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// entryKeys are the fields that identify the entries of a list, such as controls, families,
+// assessment requirements and mappings, in order of preference.
+var entryKeys = []string{"id", "reference-id", "requirement-id", "control-id"}
+
+// MetadataDiffArtifacts describes the DiffArtifacts tool.
+var MetadataDiffArtifacts = &mcp.Tool{
+	Name:         "diff_artifacts",
+	Description:  "Compare two versions of a Gemara artifact of the same definition, matching controls, families, assessment requirements, mappings and other entries by their IDs rather than by line, and report the entries added and removed and the fields changed, as JSON or a markdown changelog.",
+	InputSchema:  withEnum(schemaFor[InputDiffArtifacts](), "format", formatJSON, formatMarkdown),
+	OutputSchema: schemaFor[OutputDiffArtifacts](),
+	Annotations:  readOnlyAnnotations(true),
+}
+
+// InputDiffArtifacts is the input for the DiffArtifacts tool.
+type InputDiffArtifacts struct {
+	FromContent string `json:"from_content,omitempty" jsonschema:"YAML or JSON content of the earlier version of the artifact"`
+	FromSource  string `json:"from_source,omitempty" jsonschema:"Location to load the earlier version from instead of from_content (e.g., 'oci://ghcr.io/org/catalogs:v1#catalog.yaml')"`
+	FromID      string `json:"from_id,omitempty" jsonschema:"Metadata ID of an artifact in the client's workspace to use as the earlier version instead of from_content"`
+	ToContent   string `json:"to_content,omitempty" jsonschema:"YAML or JSON content of the later version of the artifact"`
+	ToSource    string `json:"to_source,omitempty" jsonschema:"Location to load the later version from instead of to_content"`
+	ToID        string `json:"to_id,omitempty" jsonschema:"Metadata ID of an artifact in the client's workspace to use as the later version instead of to_content"`
+	Format      string `json:"format,omitempty" jsonschema:"Output format; 'markdown' renders a changelog (default: 'json')"`
+}
+
+// OutputDiffArtifacts is the output for the DiffArtifacts tool.
+type OutputDiffArtifacts struct {
+	Definition string `json:"definition"`
+	// From and To are the metadata IDs of the artifacts, with their versions when declared.
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Added   []EntryChange `json:"added"`
+	Removed []EntryChange `json:"removed"`
+	Changed []FieldChange `json:"changed"`
+	// Document holds the markdown changelog in that format.
+	Document   string `json:"document,omitempty"`
+	FromSource string `json:"from_source,omitempty"`
+	ToSource   string `json:"to_source,omitempty"`
+}
+
+// EntryChange is an entry, such as a control or mapping, that only one version contains.
+type EntryChange struct {
+	// Path locates the entry by the IDs of it and its parents (e.g., 'controls[CCC.C01].assessment-requirements[CCC.C01.TR01]').
+	Path  string `json:"path"`
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+// FieldChange is a field whose value differs between the versions.
+type FieldChange struct {
+	// Entry is the path of the entry holding the field, empty for fields of the artifact itself.
+	Entry string `json:"entry,omitempty"`
+	// Field is the path of the field within the entry (e.g., 'title' or 'metadata.version').
+	Field string `json:"field"`
+	// Before and After are the values in each version, empty when the field is absent from it.
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// DiffArtifacts compares two versions of an artifact, detecting the definition they implement
+// with the schema loaded by the specified loader.
+func DiffArtifacts(ctx context.Context, _ *mcp.CallToolRequest, input InputDiffArtifacts, loader *SchemaLoader) (*mcp.CallToolResult, OutputDiffArtifacts, error) {
+	format := input.Format
+	if format == "" {
+		format = formatJSON
+	}
+	if format != formatJSON && format != formatMarkdown {
+		return nil, OutputDiffArtifacts{}, fmt.Errorf("unsupported format %q: use %q or %q", format, formatJSON, formatMarkdown)
+	}
+	if input.FromContent == "" {
+		return nil, OutputDiffArtifacts{}, fmt.Errorf("from_content is required")
+	}
+	if input.ToContent == "" {
+		return nil, OutputDiffArtifacts{}, fmt.Errorf("to_content is required")
+	}
+
+	schema, err := loader.Load(ctx, defaultSchemaVersion, false)
+	if err != nil {
+		return nil, OutputDiffArtifacts{}, err
+	}
+	defs, err := artifactDefinitions(schema.Value)
+	if err != nil {
+		return nil, OutputDiffArtifacts{}, err
+	}
+	from, ok := detectArtifact([]byte(input.FromContent), defs)
+	if !ok {
+		return nil, OutputDiffArtifacts{}, fmt.Errorf("from: content is not a Gemara artifact")
+	}
+	to, ok := detectArtifact([]byte(input.ToContent), defs)
+	if !ok {
+		return nil, OutputDiffArtifacts{}, fmt.Errorf("to: content is not a Gemara artifact")
+	}
+	if from.Definition != to.Definition {
+		return nil, OutputDiffArtifacts{}, fmt.Errorf("the artifacts implement different definitions: %s and %s", from.Definition, to.Definition)
+	}
+
+	var before, after yaml.MapSlice
+	if err := yaml.UnmarshalWithOptions([]byte(input.FromContent), &before, yaml.UseOrderedMap()); err != nil {
+		return nil, OutputDiffArtifacts{}, fmt.Errorf("from: failed to parse artifact: %w", err)
+	}
+	if err := yaml.UnmarshalWithOptions([]byte(input.ToContent), &after, yaml.UseOrderedMap()); err != nil {
+		return nil, OutputDiffArtifacts{}, fmt.Errorf("to: failed to parse artifact: %w", err)
+	}
+
+	output := OutputDiffArtifacts{
+		Definition: from.Definition,
+		From:       artifactLabel(before),
+		To:         artifactLabel(after),
+		Added:      []EntryChange{},
+		Removed:    []EntryChange{},
+		Changed:    []FieldChange{},
+	}
+	output.diffMaps("", "", before, after)
+
+	if format == formatMarkdown {
+		output.Document = renderArtifactChangelog(output, to.Title)
+	}
+	return nil, output, nil
+}
+
+// diffMaps compares the fields of two mappings within an entry, in the order of the later version.
+func (o *OutputDiffArtifacts) diffMaps(entry, field string, before, after yaml.MapSlice) {
+	previous := before.ToMap()
+	current := after.ToMap()
+	for _, item := range after {
+		key := fmt.Sprint(item.Key)
+		value, ok := previous[item.Key]
+		if !ok {
+			o.changeField(entry, joinField(field, key), nil, item.Value)
+			continue
+		}
+		o.diffValues(entry, joinField(field, key), value, item.Value)
+	}
+	for _, item := range before {
+		if _, ok := current[item.Key]; !ok {
+			o.changeField(entry, joinField(field, fmt.Sprint(item.Key)), item.Value, nil)
+		}
+	}
+}
+
+// diffValues compares a field of an entry in both versions, descending into mappings and
+// into lists whose items carry IDs.
+func (o *OutputDiffArtifacts) diffValues(entry, field string, before, after any) {
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	switch after := after.(type) {
+	case yaml.MapSlice:
+		if before, ok := before.(yaml.MapSlice); ok {
+			o.diffMaps(entry, field, before, after)
+			return
+		}
+	case []any:
+		if before, ok := before.([]any); ok {
+			if key := entryKey(before, after); key != "" {
+				o.diffEntries(joinField(entry, field), key, before, after)
+				return
+			}
+		}
+	}
+	o.changeField(entry, field, before, after)
+}
+
+// diffEntries matches the items of a list by their key field and compares the matched entries.
+func (o *OutputDiffArtifacts) diffEntries(list, key string, before, after []any) {
+	previous := make(map[string]yaml.MapSlice, len(before))
+	for _, item := range before {
+		entry := item.(yaml.MapSlice)
+		previous[entryValue(entry, key)] = entry
+	}
+	current := make(map[string]bool, len(after))
+	for _, item := range after {
+		entry := item.(yaml.MapSlice)
+		id := entryValue(entry, key)
+		current[id] = true
+		path := fmt.Sprintf("%s[%s]", list, id)
+		if old, ok := previous[id]; ok {
+			o.diffMaps(path, "", old, entry)
+			continue
+		}
+		o.Added = append(o.Added, EntryChange{Path: path, ID: id, Title: entryValue(entry, "title")})
+	}
+	for _, item := range before {
+		entry := item.(yaml.MapSlice)
+		if id := entryValue(entry, key); !current[id] {
+			o.Removed = append(o.Removed, EntryChange{Path: fmt.Sprintf("%s[%s]", list, id), ID: id, Title: entryValue(entry, "title")})
+		}
+	}
+}
+
+// changeField records a changed field; a nil value is a field absent from that version.
+func (o *OutputDiffArtifacts) changeField(entry, field string, before, after any) {
+	o.Changed = append(o.Changed, FieldChange{
+		Entry:  entry,
+		Field:  field,
+		Before: fieldValue(before),
+		After:  fieldValue(after),
+	})
+}
+
+// entryKey returns the field that identifies the items of two versions of a list, or an
+// empty string when the items are not mappings that all carry a distinct value of one key.
+func entryKey(before, after []any) string {
+	for _, key := range entryKeys {
+		if identifies(before, key) && identifies(after, key) {
+			return key
+		}
+	}
+	return ""
+}
+
+// identifies reports whether every item of a list is a mapping with a distinct value for key.
+func identifies(items []any, key string) bool {
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		entry, ok := item.(yaml.MapSlice)
+		if !ok {
+			return false
+		}
+		id := entryValue(entry, key)
+		if id == "" || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
+
+// entryValue returns the scalar value of a field of an entry, or an empty string.
+func entryValue(entry yaml.MapSlice, key string) string {
+	for _, item := range entry {
+		if item.Key == key {
+			switch value := item.Value.(type) {
+			case yaml.MapSlice, []any, nil:
+				return ""
+			default:
+				return compactText(fmt.Sprint(value))
+			}
+		}
+	}
+	return ""
+}
+
+// fieldValue renders a field value on a single line: scalars as text, lists of scalars
+// joined by commas and other values in YAML flow style.
+func fieldValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case yaml.MapSlice:
+		return flowValue(value)
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			switch item.(type) {
+			case yaml.MapSlice, []any:
+				return flowValue(value)
+			}
+			items = append(items, compactText(fmt.Sprint(item)))
+		}
+		return strings.Join(items, ", ")
+	default:
+		return compactText(fmt.Sprint(value))
+	}
+}
+
+// flowValue renders a value in YAML flow style.
+func flowValue(value any) string {
+	out, err := yaml.MarshalWithOptions(value, yaml.Flow(true))
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return compactText(string(out))
+}
+
+// joinField appends a field name to a dotted field path.
+func joinField(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// artifactLabel identifies a version of an artifact by its metadata ID and version.
+func artifactLabel(artifact yaml.MapSlice) string {
+	metadata, _ := mapField(artifact, "metadata").(yaml.MapSlice)
+	label := entryValue(metadata, "id")
+	if version := entryValue(metadata, "version"); version != "" {
+		label += " " + version
+	}
+	return label
+}
+
+// renderArtifactChangelog renders the differences between two versions of an artifact as a
+// markdown changelog, grouping changed fields by entry.
+func renderArtifactChangelog(output OutputDiffArtifacts, title string) string {
+	var b strings.Builder
+	if title == "" {
+		title = output.To
+	}
+	fmt.Fprintf(&b, "# Changes to %s\n\n", title)
+	fmt.Fprintf(&b, "From %s to %s: %d added and %d removed entries, %d changed fields.\n",
+		output.From, output.To, len(output.Added), len(output.Removed), len(output.Changed))
+
+	for _, section := range []struct {
+		heading string
+		entries []EntryChange
+	}{
+		{"Added", output.Added},
+		{"Removed", output.Removed},
+	} {
+		if len(section.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", section.heading)
+		for _, entry := range section.entries {
+			fmt.Fprintf(&b, "- `%s`", entry.Path)
+			if entry.Title != "" {
+				fmt.Fprintf(&b, ": %s", entry.Title)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(output.Changed) > 0 {
+		b.WriteString("\n## Changed\n")
+		var entries []string
+		changes := make(map[string][]FieldChange)
+		for _, change := range output.Changed {
+			if _, ok := changes[change.Entry]; !ok {
+				entries = append(entries, change.Entry)
+			}
+			changes[change.Entry] = append(changes[change.Entry], change)
+		}
+		for _, entry := range entries {
+			if entry == "" {
+				b.WriteString("\n### Artifact\n\n")
+			} else {
+				fmt.Fprintf(&b, "\n### `%s`\n\n", entry)
+			}
+			for _, change := range changes[entry] {
+				switch {
+				case change.Before == "":
+					fmt.Fprintf(&b, "- `%s` added: %s\n", change.Field, change.After)
+				case change.After == "":
+					fmt.Fprintf(&b, "- `%s` removed (was: %s)\n", change.Field, change.Before)
+				default:
+					fmt.Fprintf(&b, "- `%s` changed from \"%s\" to \"%s\"\n", change.Field, change.Before, change.After)
+				}
+			}
+		}
+	}
+	return b.String()
+}
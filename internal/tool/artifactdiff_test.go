@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reorderedThreatCatalog is threatCatalogV010 as JSON with its fields in another order.
+const reorderedThreatCatalog = `{
+  "threats": [{
+    "external-mappings": [{"entries": [{"strength": 0, "reference-id": "T1530"}], "reference-id": "MITRE"}],
+    "description": "Data is read by an unauthorized party.",
+    "title": "Data Exfiltration",
+    "id": "OS.TH01"
+  }],
+  "metadata": {
+    "author": {"uri": "https://finos.org", "name": "FINOS", "id": "finos"},
+    "description": "Threats to object storage services.",
+    "id": "OS-THREATS"
+  },
+  "title": "Object Storage Threats"
+}`
+
+func TestDiffArtifacts(t *testing.T) {
+	loader := newTestSchemaLoader(t)
+	catalog := readTestCatalog(t)
+	revised := strings.NewReplacer(
+		"  id: FINOS-CCC\n", "  id: FINOS-CCC\n  version: 2026.02\n",
+		"title: Prevent Deployment in Restricted Regions", "title: Restrict Deployment Regions",
+		"strength: 7\n            remarks: Data is Intercepted in Transit", "strength: 9\n            remarks: Data is Intercepted in Transit",
+		"CCC.C06.TR02", "CCC.C06.TR03",
+	).Replace(catalog)
+	threats, err := os.ReadFile(filepath.Join("testdata", "ccc-threats.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		input          InputDiffArtifacts
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputDiffArtifacts)
+	}{
+		{
+			name:  "revised catalog",
+			input: InputDiffArtifacts{FromContent: catalog, ToContent: revised},
+			validateOutput: func(t *testing.T, output OutputDiffArtifacts) {
+				assert.Equal(t, "#ControlCatalog", output.Definition)
+				assert.Equal(t, "FINOS-CCC", output.From)
+				assert.Equal(t, "FINOS-CCC 2026.02", output.To)
+				assert.Equal(t, []EntryChange{{Path: "controls[CCC.C06].assessment-requirements[CCC.C06.TR03]", ID: "CCC.C06.TR03"}}, output.Added)
+				assert.Equal(t, []EntryChange{{Path: "controls[CCC.C06].assessment-requirements[CCC.C06.TR02]", ID: "CCC.C06.TR02"}}, output.Removed)
+				assert.Equal(t, []FieldChange{
+					{Field: "metadata.version", After: "2026.02"},
+					{Entry: "controls[CCC.C01].threat-mappings[CCC].entries[CCC.TH02]", Field: "strength", Before: "7", After: "9"},
+					{Entry: "controls[CCC.C06]", Field: "title", Before: "Prevent Deployment in Restricted Regions", After: "Restrict Deployment Regions"},
+				}, output.Changed)
+				assert.Empty(t, output.Document)
+			},
+		},
+		{
+			name:  "same artifact as JSON in another order",
+			input: InputDiffArtifacts{FromContent: threatCatalogV010, ToContent: reorderedThreatCatalog},
+			validateOutput: func(t *testing.T, output OutputDiffArtifacts) {
+				assert.Equal(t, "#ThreatCatalog", output.Definition)
+				assert.Empty(t, output.Added)
+				assert.Empty(t, output.Removed)
+				assert.Empty(t, output.Changed)
+			},
+		},
+		{
+			name:  "markdown changelog",
+			input: InputDiffArtifacts{FromContent: catalog, ToContent: revised, Format: formatMarkdown},
+			validateOutput: func(t *testing.T, output OutputDiffArtifacts) {
+				assert.Equal(t, "# Changes to FINOS Cloud Control Catalog\n\n"+
+					"From FINOS-CCC to FINOS-CCC 2026.02: 1 added and 1 removed entries, 3 changed fields.\n\n"+
+					"## Added\n\n- `controls[CCC.C06].assessment-requirements[CCC.C06.TR03]`\n\n"+
+					"## Removed\n\n- `controls[CCC.C06].assessment-requirements[CCC.C06.TR02]`\n\n"+
+					"## Changed\n\n"+
+					"### Artifact\n\n- `metadata.version` added: 2026.02\n\n"+
+					"### `controls[CCC.C01].threat-mappings[CCC].entries[CCC.TH02]`\n\n- `strength` changed from \"7\" to \"9\"\n\n"+
+					"### `controls[CCC.C06]`\n\n- `title` changed from \"Prevent Deployment in Restricted Regions\" to \"Restrict Deployment Regions\"\n",
+					output.Document)
+			},
+		},
+		{
+			name:        "different definitions",
+			input:       InputDiffArtifacts{FromContent: catalog, ToContent: string(threats)},
+			wantErr:     true,
+			errContains: "the artifacts implement different definitions: #ControlCatalog and #ThreatCatalog",
+		},
+		{
+			name:        "not an artifact",
+			input:       InputDiffArtifacts{FromContent: "owner: security-team\n", ToContent: catalog},
+			wantErr:     true,
+			errContains: "from: content is not a Gemara artifact",
+		},
+		{
+			name:        "missing later version",
+			input:       InputDiffArtifacts{FromContent: catalog},
+			wantErr:     true,
+			errContains: "to_content is required",
+		},
+		{
+			name:        "unsupported format",
+			input:       InputDiffArtifacts{FromContent: catalog, ToContent: revised, Format: "html"},
+			wantErr:     true,
+			errContains: `unsupported format "html"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := DiffArtifacts(context.Background(), nil, tt.input, loader)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			tt.validateOutput(t, output)
+		})
+	}
+}
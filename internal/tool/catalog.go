@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Metadata describes the origin and context of a Gemara artifact.
+type Metadata struct {
+	ID                      string             `json:"id" yaml:"id"`
+	Title                   string             `json:"title,omitempty" yaml:"title,omitempty"`
+	Description             string             `json:"description" yaml:"description"`
+	Version                 string             `json:"version,omitempty" yaml:"version,omitempty"`
+	LastModified            string             `json:"last-modified,omitempty" yaml:"last-modified,omitempty"`
+	Author                  Actor              `json:"author" yaml:"author"`
+	MappingReferences       []MappingReference `json:"mapping-references,omitempty" yaml:"mapping-references,omitempty"`
+	ApplicabilityCategories []Category         `json:"applicability-categories,omitempty" yaml:"applicability-categories,omitempty"`
+	Lexicon                 string             `json:"lexicon,omitempty" yaml:"lexicon,omitempty"`
+}
+
+// Actor is a person, organization or tool responsible for an artifact.
+type Actor struct {
+	ID   string `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+// Category groups requirements by the contexts in which they apply.
+type Category struct {
+	ID          string `json:"id" yaml:"id"`
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// MappingReference identifies an external document referenced by mappings.
+type MappingReference struct {
+	ID          string `json:"id" yaml:"id"`
+	Title       string `json:"title" yaml:"title"`
+	Version     string `json:"version,omitempty" yaml:"version,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	URL         string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// MultiMapping maps to entries within a single referenced document.
+type MultiMapping struct {
+	ReferenceID string         `json:"reference-id" yaml:"reference-id"`
+	Entries     []MappingEntry `json:"entries" yaml:"entries"`
+	Remarks     string         `json:"remarks,omitempty" yaml:"remarks,omitempty"`
+}
+
+// MappingEntry is a single mapped item with the strength of the relationship,
+// from 1 (weak) to 10 (identical).
+type MappingEntry struct {
+	ReferenceID string `json:"reference-id" yaml:"reference-id"`
+	Strength    int    `json:"strength,omitempty" yaml:"strength,omitempty"`
+	Remarks     string `json:"remarks,omitempty" yaml:"remarks,omitempty"`
+}
+
+// Family groups related items within a catalog.
+type Family struct {
+	ID          string `json:"id" yaml:"id"`
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// ControlCatalog is a set of technology-specific controls organized into families.
+type ControlCatalog struct {
+	Title    string    `json:"title" yaml:"title"`
+	Metadata Metadata  `json:"metadata" yaml:"metadata"`
+	Families []Family  `json:"families,omitempty" yaml:"families,omitempty"`
+	Controls []Control `json:"controls,omitempty" yaml:"controls,omitempty"`
+}
+
+// Control is a safeguard or countermeasure with testable assessment requirements.
+type Control struct {
+	ID                     string                  `json:"id" yaml:"id"`
+	Title                  string                  `json:"title" yaml:"title"`
+	Objective              string                  `json:"objective" yaml:"objective"`
+	Family                 string                  `json:"family" yaml:"family"`
+	ThreatMappings         []MultiMapping          `json:"threat-mappings,omitempty" yaml:"threat-mappings,omitempty"`
+	GuidelineMappings      []MultiMapping          `json:"guideline-mappings,omitempty" yaml:"guideline-mappings,omitempty"`
+	AssessmentRequirements []AssessmentRequirement `json:"assessment-requirements" yaml:"assessment-requirements"`
+}
+
+// AssessmentRequirement is a tightly scoped, verifiable condition of a control.
+type AssessmentRequirement struct {
+	ID             string   `json:"id" yaml:"id"`
+	Text           string   `json:"text" yaml:"text"`
+	Applicability  []string `json:"applicability" yaml:"applicability"`
+	Recommendation string   `json:"recommendation,omitempty" yaml:"recommendation,omitempty"`
+}
+
+// ThreatCatalog describes threats to a technology and the capabilities they exploit.
+type ThreatCatalog struct {
+	Title        string    `json:"title" yaml:"title"`
+	Metadata     Metadata  `json:"metadata" yaml:"metadata"`
+	Capabilities []Feature `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	Threats      []Threat  `json:"threats,omitempty" yaml:"threats,omitempty"`
+}
+
+// Feature is a capability of a technology that may be exploited.
+type Feature struct {
+	ID          string `json:"id" yaml:"id"`
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// Threat is a potential for harm to a technology.
+type Threat struct {
+	ID          string `json:"id" yaml:"id"`
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description" yaml:"description"`
+	// Capabilities map the threat to the features it exploits.
+	Capabilities     []MultiMapping `json:"capabilities" yaml:"capabilities"`
+	ExternalMappings []MultiMapping `json:"external-mappings,omitempty" yaml:"external-mappings,omitempty"`
+}
+
+// ParseControlCatalog decodes a control catalog from YAML or JSON content. The content is
+// not validated against the schema; fields the model does not know are ignored.
+func ParseControlCatalog(content []byte) (*ControlCatalog, error) {
+	return parseArtifact(content, "control catalog", "controls", func(c *ControlCatalog) bool {
+		return c.Metadata.ID != "" || len(c.Controls) > 0
+	})
+}
+
+// ParseThreatCatalog decodes a threat catalog from YAML or JSON content without validating it.
+func ParseThreatCatalog(content []byte) (*ThreatCatalog, error) {
+	return parseArtifact(content, "threat catalog", "threats", func(c *ThreatCatalog) bool {
+		return c.Metadata.ID != "" || len(c.Threats) > 0
+	})
+}
+
+// parseArtifact decodes content into the model of an artifact kind, failing for content
+// that does not look like such an artifact.
+func parseArtifact[T any](content []byte, kind, entries string, found func(*T) bool) (*T, error) {
+	var artifact T
+	if err := yaml.Unmarshal(content, &artifact); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", kind, err)
+	}
+	if !found(&artifact) {
+		return nil, fmt.Errorf("failed to parse %s: no metadata or %s found", kind, entries)
+	}
+	return &artifact, nil
+}
+
+// Family returns the family with the given ID.
+func (c *ControlCatalog) Family(id string) (Family, bool) {
+	for _, family := range c.Families {
+		if family.ID == id {
+			return family, true
+		}
+	}
+	return Family{}, false
+}
+
+// Control returns the control with the given ID.
+func (c *ControlCatalog) Control(id string) (Control, bool) {
+	for _, control := range c.Controls {
+		if control.ID == id {
+			return control, true
+		}
+	}
+	return Control{}, false
+}
+
+// Applicability returns the applicability categories the requirements of the control list,
+// in order of first appearance.
+func (c Control) Applicability() []string {
+	var categories []string
+	seen := make(map[string]bool)
+	for _, requirement := range c.AssessmentRequirements {
+		for _, category := range requirement.Applicability {
+			if !seen[category] {
+				seen[category] = true
+				categories = append(categories, category)
+			}
+		}
+	}
+	return categories
+}
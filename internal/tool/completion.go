@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
+)
+
+// maxCompletions is the largest number of values a completion result may hold.
+const maxCompletions = 100
+
+// Complete suggests values for prompt and resource template arguments: Gemara definition
+// names for "definition", schema versions for "version", "from" and "to", lexicon terms
+// for "term", artifact IDs for "id" and control IDs for "control_id". Candidates come from
+// the lexicon cache of the specified cached fetcher, the module versions and definitions
+// known to the specified schema loader and the artifacts of the specified workspace index.
+// Completion never reaches the network: until the lexicon is cached or the module versions
+// are listed or loaded by a tool, their arguments have no suggestions, as do other arguments.
+func Complete(_ context.Context, req *mcp.CompleteRequest, lexicon *fetcher.CachedFetcher, loader *SchemaLoader, workspace *WorkspaceIndex) (*mcp.CompleteResult, error) {
+	var resolved map[string]string
+	if req.Params.Context != nil {
+		resolved = req.Params.Context.Arguments
+	}
+
+	var candidates []string
+	switch arg := req.Params.Argument; arg.Name {
+	case "definition":
+		candidates = loader.CachedDefinitionNames(resolved["version"])
+		// A '#' starts the fragment of a URI, so resource templates take names without it.
+		if req.Params.Ref != nil && req.Params.Ref.Type == "ref/resource" {
+			for i, name := range candidates {
+				candidates[i] = strings.TrimPrefix(name, "#")
+			}
+		}
+	case "version", "from", "to":
+		candidates = loader.CachedVersions()
+		slices.Reverse(candidates)
+		if arg.Name != "from" && len(candidates) > 0 {
+			candidates = append([]string{defaultSchemaVersion}, candidates...)
+		}
+	case "term":
+		data, _, ok := lexicon.Cached()
+		if !ok {
+			break
+		}
+		entries, err := parseLexicon(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			candidates = append(candidates, entry.Term)
+		}
+	case "id":
+		for _, artifact := range workspace.Artifacts() {
+			candidates = append(candidates, artifact.ID)
+		}
+	case "control_id":
+		// Without a resolved artifact, suggest the controls of all indexed artifacts.
+		for _, artifact := range workspace.Artifacts() {
+			if id := resolved["id"]; id == "" || id == artifact.ID {
+				candidates = append(candidates, artifact.ControlIDs...)
+			}
+		}
+	}
+
+	return &mcp.CompleteResult{Completion: completion(candidates, req.Params.Argument.Value)}, nil
+}
+
+// completion selects the candidates that start with value, ignoring case and a leading '#'.
+func completion(candidates []string, value string) mcp.CompletionResultDetails {
+	prefix := strings.ToLower(strings.TrimPrefix(value, "#"))
+	values := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(strings.TrimPrefix(candidate, "#")), prefix) {
+			values = append(values, candidate)
+		}
+	}
+
+	details := mcp.CompletionResultDetails{Values: values, Total: len(values)}
+	if len(values) > maxCompletions {
+		details.Values = values[:maxCompletions]
+		details.HasMore = true
+	}
+	return details
+}
@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
+)
+
+func TestComplete(t *testing.T) {
+	loader := newTestSchemaLoader(t)
+	workspace := NewWorkspaceIndex(loader)
+	// Scanning the workspace resolves and loads the latest version; v0.1.0 is never loaded.
+	_, err := workspace.Scan(context.Background(), []string{newTestWorkspace(t)})
+	require.NoError(t, err)
+	_, err = loader.Load(context.Background(), "v0.0.1", false)
+	require.NoError(t, err)
+	controlRef := &mcp.CompleteReference{Type: "ref/resource", URI: ResourceWorkspaceControl.URITemplate}
+	promptRef := &mcp.CompleteReference{Type: "ref/prompt", Name: "draft_control_catalog"}
+	resourceRef := &mcp.CompleteReference{Type: "ref/resource", URI: ResourceSchemaDefinition.URITemplate}
+
+	tests := []struct {
+		name       string
+		params     *mcp.CompleteParams
+		uncached   bool
+		wantValues []string
+	}{
+		{
+			name: "definition names of the latest version",
+			params: &mcp.CompleteParams{
+				Ref:      promptRef,
+				Argument: mcp.CompleteParamsArgument{Name: "definition", Value: "#ControlC"},
+			},
+			wantValues: []string{"#ControlCatalog"},
+		},
+		{
+			name: "definition names of the resolved version",
+			params: &mcp.CompleteParams{
+				Ref:      promptRef,
+				Argument: mcp.CompleteParamsArgument{Name: "definition", Value: "con"},
+				Context:  &mcp.CompleteContext{Arguments: map[string]string{"version": "v0.0.1"}},
+			},
+			wantValues: []string{"#Control", "#ControlCatalog"},
+		},
+		{
+			name: "definition names without hash for resource templates",
+			params: &mcp.CompleteParams{
+				Ref:      resourceRef,
+				Argument: mcp.CompleteParamsArgument{Name: "definition", Value: "Met"},
+				Context:  &mcp.CompleteContext{Arguments: map[string]string{"version": "v0.0.1"}},
+			},
+			wantValues: []string{"Metadata"},
+		},
+		{
+			name: "versions newest first with latest",
+			params: &mcp.CompleteParams{
+				Ref:      resourceRef,
+				Argument: mcp.CompleteParamsArgument{Name: "version"},
+			},
+			wantValues: []string{"latest", "v0.2.0", "v0.1.0", "v0.0.1"},
+		},
+		{
+			name: "from versions by prefix",
+			params: &mcp.CompleteParams{
+				Ref:      promptRef,
+				Argument: mcp.CompleteParamsArgument{Name: "from", Value: "v0.1"},
+			},
+			wantValues: []string{"v0.1.0"},
+		},
+		{
+			name: "lexicon terms ignoring case",
+			params: &mcp.CompleteParams{
+				Ref:      &mcp.CompleteReference{Type: "ref/resource", URI: ResourceLexiconTerm.URITemplate},
+				Argument: mcp.CompleteParamsArgument{Name: "term", Value: "c"},
+			},
+			wantValues: []string{"Control"},
+		},
+		{
+			name: "artifact IDs",
+			params: &mcp.CompleteParams{
+				Ref:      controlRef,
+				Argument: mcp.CompleteParamsArgument{Name: "id"},
+			},
+			wantValues: []string{"FINOS-CCC", "OS-THREATS", "nightly-2026-01-01"},
+		},
+		{
+			name: "control IDs of the resolved artifact",
+			params: &mcp.CompleteParams{
+				Ref:      controlRef,
+				Argument: mcp.CompleteParamsArgument{Name: "control_id", Value: "ccc.c0"},
+				Context:  &mcp.CompleteContext{Arguments: map[string]string{"id": "FINOS-CCC"}},
+			},
+			wantValues: []string{"CCC.C01", "CCC.C06", "CCC.C08", "CCC.C09"},
+		},
+		{
+			name: "control IDs of an unknown artifact",
+			params: &mcp.CompleteParams{
+				Ref:      controlRef,
+				Argument: mcp.CompleteParamsArgument{Name: "control_id"},
+				Context:  &mcp.CompleteContext{Arguments: map[string]string{"id": "OS-THREATS"}},
+			},
+			wantValues: []string{},
+		},
+		{
+			name: "argument without suggestions",
+			params: &mcp.CompleteParams{
+				Ref:      promptRef,
+				Argument: mcp.CompleteParamsArgument{Name: "guidance", Value: "title"},
+			},
+			wantValues: []string{},
+		},
+		{
+			name: "definition names of a partially typed version",
+			params: &mcp.CompleteParams{
+				Ref:      promptRef,
+				Argument: mcp.CompleteParamsArgument{Name: "definition"},
+				Context:  &mcp.CompleteContext{Arguments: map[string]string{"version": "v0."}},
+			},
+			wantValues: []string{},
+		},
+		{
+			name: "definition names of a version not loaded",
+			params: &mcp.CompleteParams{
+				Ref:      promptRef,
+				Argument: mcp.CompleteParamsArgument{Name: "definition"},
+				Context:  &mcp.CompleteContext{Arguments: map[string]string{"version": "v0.1.0"}},
+			},
+			wantValues: []string{},
+		},
+		{
+			name: "lexicon not cached",
+			params: &mcp.CompleteParams{
+				Ref:      promptRef,
+				Argument: mcp.CompleteParamsArgument{Name: "term"},
+			},
+			uncached:   true,
+			wantValues: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockFetcher{data: []byte(sampleLexicon), source: "mock://lexicon.yaml"}
+			lexicon := fetcher.NewCachedFetcher(mock, fetcher.NewCache(time.Hour), "mock://lexicon.yaml")
+			if !tt.uncached {
+				_, _, err := lexicon.Fetch(context.Background(), false)
+				require.NoError(t, err)
+			}
+			calls := mock.callCount
+
+			result, err := Complete(context.Background(), &mcp.CompleteRequest{Params: tt.params}, lexicon, loader, workspace)
+			require.NoError(t, err, "should not return error")
+			assert.Equal(t, tt.wantValues, result.Completion.Values)
+			assert.Equal(t, calls, mock.callCount, "completion should not fetch the lexicon")
+			assert.Equal(t, len(tt.wantValues), result.Completion.Total)
+			assert.False(t, result.Completion.HasMore)
+		})
+	}
+}
+
+func TestCompletionLimit(t *testing.T) {
+	var candidates []string
+	for i := range maxCompletions + 20 {
+		candidates = append(candidates, fmt.Sprintf("CCC.C%03d", i))
+	}
+
+	details := completion(candidates, "ccc.")
+	assert.Len(t, details.Values, maxCompletions)
+	assert.Equal(t, maxCompletions+20, details.Total)
+	assert.True(t, details.HasMore)
+}
+
+func TestCompleteOffline(t *testing.T) {
+	loader := newTestSchemaLoader(t)
+	mock := &mockFetcher{data: []byte(sampleLexicon), source: "mock://lexicon.yaml"}
+	lexicon := fetcher.NewCachedFetcher(mock, fetcher.NewCache(time.Hour), "mock://lexicon.yaml")
+
+	for _, name := range []string{"definition", "version", "from", "term"} {
+		result, err := Complete(context.Background(), &mcp.CompleteRequest{Params: &mcp.CompleteParams{
+			Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "draft_control_catalog"},
+			Argument: mcp.CompleteParamsArgument{Name: name},
+		}}, lexicon, loader, NewWorkspaceIndex(loader))
+		require.NoError(t, err)
+		assert.Empty(t, result.Completion.Values, "%s should have no suggestions before anything is cached", name)
+	}
+	assert.Zero(t, mock.callCount, "completion should not fetch the lexicon")
+	assert.Empty(t, loader.CachedVersions(), "completion should not list module versions")
+}
+
+func TestCompleteDuringLoad(t *testing.T) {
+	loader := newTestSchemaLoader(t)
+	_, err := loader.Load(context.Background(), "v0.0.1", false)
+	require.NoError(t, err)
+	lexicon := fetcher.NewCachedFetcher(&mockFetcher{}, fetcher.NewCache(time.Hour), "mock://lexicon.yaml")
+
+	// Hold the loader as a load waiting on the registry would.
+	loader.mu.Lock()
+	defer loader.mu.Unlock()
+
+	done := make(chan *mcp.CompleteResult)
+	go func() {
+		result, _ := Complete(context.Background(), &mcp.CompleteRequest{Params: &mcp.CompleteParams{
+			Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "draft_control_catalog"},
+			Argument: mcp.CompleteParamsArgument{Name: "definition", Value: "#Met"},
+			Context:  &mcp.CompleteContext{Arguments: map[string]string{"version": "v0.0.1"}},
+		}}, lexicon, loader, NewWorkspaceIndex(loader))
+		done <- result
+	}()
+	select {
+	case result := <-done:
+		assert.Equal(t, []string{"#Metadata"}, result.Completion.Values)
+	case <-time.After(time.Second):
+		t.Fatal("completion should not wait for loads in progress")
+	}
+}
+
+func TestCompleteOverSession(t *testing.T) {
+	session := newTestSession(t, newTestAdvisoryMode(t))
+	ctx := context.Background()
+
+	_, err := session.CallTool(ctx, &mcp.CallToolParams{Name: MetadataGetLexicon.Name, Arguments: map[string]any{}})
+	require.NoError(t, err)
+	result, err := session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/resource", URI: ResourceLexiconTerm.URITemplate},
+		Argument: mcp.CompleteParamsArgument{Name: "term", Value: "ass"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Assessment"}, result.Completion.Values)
+}
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MetadataQueryControls describes the QueryControls tool.
+var MetadataQueryControls = &mcp.Tool{
+	Name:         "query_controls",
+	Description:  "Find controls in a Gemara control catalog by family, ID prefix, applicability category, mapped guideline or threat, and text in their title or objective, returning compact summaries instead of the raw YAML.",
+	InputSchema:  schemaFor[InputQueryControls](),
+	OutputSchema: schemaFor[OutputQueryControls](),
+	Annotations:  readOnlyAnnotations(true),
+}
+
+// InputQueryControls is the input for the QueryControls tool.
+type InputQueryControls struct {
+	ArtifactContent string `json:"artifact_content,omitempty" jsonschema:"YAML content of the control catalog"`
+	ArtifactSource  string `json:"artifact_source,omitempty" jsonschema:"Location to load the control catalog from instead of artifact_content (e.g., 'oci://ghcr.io/org/catalogs:v1#ccc.yaml', 'https://...')"`
+	ArtifactID      string `json:"artifact_id,omitempty" jsonschema:"Metadata ID of a control catalog in the client's workspace instead of artifact_content (e.g., 'FINOS-CCC')"`
+	Family          string `json:"family,omitempty" jsonschema:"Only controls of this family ID (e.g., 'data-protection')"`
+	IDPrefix        string `json:"id_prefix,omitempty" jsonschema:"Only controls whose ID starts with this prefix (e.g., 'CCC.C0')"`
+	Applicability   string `json:"applicability,omitempty" jsonschema:"Only controls with an assessment requirement in this applicability category ID (e.g., 'tlp_amber')"`
+	Guideline       string `json:"guideline,omitempty" jsonschema:"Only controls mapped to this guideline, given as a document ID, an entry ID or 'document:entry' (e.g., 'NIST-800-53', 'SC-8', 'NIST-800-53:SC-8')"`
+	Threat          string `json:"threat,omitempty" jsonschema:"Only controls mapped to this threat, given as a catalog ID, a threat ID or 'catalog:threat' (e.g., 'CCC.TH02')"`
+	Text            string `json:"text,omitempty" jsonschema:"Only controls whose title or objective contains this text, ignoring case"`
+}
+
+// OutputQueryControls is the output for the QueryControls tool.
+type OutputQueryControls struct {
+	CatalogID string `json:"catalog_id"`
+	// Total is the number of controls in the catalog before filtering.
+	Total    int              `json:"total"`
+	Controls []ControlSummary `json:"controls"`
+	Source   string           `json:"source,omitempty"`
+}
+
+// ControlSummary is a compact description of a control.
+type ControlSummary struct {
+	ID        string `json:"id"`
+	Family    string `json:"family"`
+	Title     string `json:"title"`
+	Objective string `json:"objective"`
+	// Requirements is the number of assessment requirements of the control.
+	Requirements  int      `json:"requirements"`
+	Applicability []string `json:"applicability,omitempty"`
+	// Threats and Guidelines are the mapped entries as 'document:entry'.
+	Threats    []string `json:"threats,omitempty"`
+	Guidelines []string `json:"guidelines,omitempty"`
+}
+
+// QueryControls filters the controls of a control catalog. All given filters must match.
+func QueryControls(_ context.Context, _ *mcp.CallToolRequest, input InputQueryControls) (*mcp.CallToolResult, OutputQueryControls, error) {
+	if input.ArtifactContent == "" {
+		return nil, OutputQueryControls{}, fmt.Errorf("artifact_content is required")
+	}
+	catalog, err := ParseControlCatalog([]byte(input.ArtifactContent))
+	if err != nil {
+		return nil, OutputQueryControls{}, err
+	}
+
+	output := OutputQueryControls{
+		CatalogID: catalog.Metadata.ID,
+		Total:     len(catalog.Controls),
+		Controls:  []ControlSummary{},
+	}
+	for _, control := range catalog.Controls {
+		if !matchesControl(control, input) {
+			continue
+		}
+		output.Controls = append(output.Controls, summarizeControl(control))
+	}
+	return nil, output, nil
+}
+
+// matchesControl reports whether control passes all filters of input.
+func matchesControl(control Control, input InputQueryControls) bool {
+	if input.Family != "" && !strings.EqualFold(control.Family, input.Family) {
+		return false
+	}
+	if input.IDPrefix != "" && !strings.HasPrefix(strings.ToLower(control.ID), strings.ToLower(input.IDPrefix)) {
+		return false
+	}
+	if input.Applicability != "" && !containsFold(control.Applicability(), input.Applicability) {
+		return false
+	}
+	if input.Guideline != "" && !matchesMapping(control.GuidelineMappings, input.Guideline) {
+		return false
+	}
+	if input.Threat != "" && !matchesMapping(control.ThreatMappings, input.Threat) {
+		return false
+	}
+	if input.Text != "" {
+		text := strings.ToLower(input.Text)
+		if !strings.Contains(strings.ToLower(control.Title), text) && !strings.Contains(strings.ToLower(control.Objective), text) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesMapping reports whether mappings reference ref, which is a document ID, an entry
+// ID or both separated by a colon.
+func matchesMapping(mappings []MultiMapping, ref string) bool {
+	document, entry, qualified := strings.Cut(ref, ":")
+	if !qualified {
+		document, entry = ref, ref
+	}
+	for _, mapping := range mappings {
+		documentMatches := strings.EqualFold(mapping.ReferenceID, document)
+		if documentMatches && !qualified {
+			return true
+		}
+		if qualified && !documentMatches {
+			continue
+		}
+		for _, e := range mapping.Entries {
+			if strings.EqualFold(e.ReferenceID, entry) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mappingRefs lists the entries of mappings as 'document:entry'.
+func mappingRefs(mappings []MultiMapping) []string {
+	var refs []string
+	for _, mapping := range mappings {
+		for _, entry := range mapping.Entries {
+			refs = append(refs, mapping.ReferenceID+":"+entry.ReferenceID)
+		}
+	}
+	return refs
+}
+
+func summarizeControl(control Control) ControlSummary {
+	return ControlSummary{
+		ID:            control.ID,
+		Family:        control.Family,
+		Title:         compactText(control.Title),
+		Objective:     compactText(control.Objective),
+		Requirements:  len(control.AssessmentRequirements),
+		Applicability: control.Applicability(),
+		Threats:       mappingRefs(control.ThreatMappings),
+		Guidelines:    mappingRefs(control.GuidelineMappings),
+	}
+}
+
+// compactText joins the lines of YAML block text into a single line.
+func compactText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readTestCatalog returns the content of the FINOS CCC test catalog.
+func readTestCatalog(t *testing.T) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join("testdata", "good-ccc.yaml"))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestParseControlCatalog(t *testing.T) {
+	catalog, err := ParseControlCatalog([]byte(readTestCatalog(t)))
+	require.NoError(t, err)
+
+	assert.Equal(t, "FINOS-CCC", catalog.Metadata.ID)
+	assert.Equal(t, "FINOS", catalog.Metadata.Author.Name)
+	require.Len(t, catalog.Metadata.ApplicabilityCategories, 4)
+	assert.Equal(t, "TLP:Amber", catalog.Metadata.ApplicabilityCategories[2].Title)
+
+	family, ok := catalog.Family("data-protection")
+	require.True(t, ok)
+	assert.Equal(t, "Data Protection", family.Title)
+
+	control, ok := catalog.Control("CCC.C08")
+	require.True(t, ok)
+	assert.Equal(t, []string{"tlp_green", "tlp_amber", "tlp_red"}, control.Applicability())
+	require.Len(t, control.GuidelineMappings, 3)
+	assert.Equal(t, MappingEntry{ReferenceID: "CP-10", Strength: 7, Remarks: "Information system recovery and reconstitution"}, control.GuidelineMappings[2].Entries[1])
+
+	_, ok = catalog.Control("CCC.C99")
+	assert.False(t, ok)
+
+	_, err = ParseControlCatalog([]byte("owner: security-team\n"))
+	assert.ErrorContains(t, err, "no metadata or controls found")
+	_, err = ParseControlCatalog([]byte("controls: [\n"))
+	assert.ErrorContains(t, err, "failed to parse control catalog")
+}
+
+func TestQueryControls(t *testing.T) {
+	catalog := readTestCatalog(t)
+
+	tests := []struct {
+		name        string
+		input       InputQueryControls
+		wantIDs     []string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "all controls",
+			input:   InputQueryControls{},
+			wantIDs: []string{"CCC.C01", "CCC.C06", "CCC.C08", "CCC.C09", "CCC.C10"},
+		},
+		{
+			name:    "family ignoring case",
+			input:   InputQueryControls{Family: "Data-Protection"},
+			wantIDs: []string{"CCC.C01", "CCC.C06", "CCC.C08", "CCC.C09", "CCC.C10"},
+		},
+		{
+			name:    "unknown family",
+			input:   InputQueryControls{Family: "identity"},
+			wantIDs: []string{},
+		},
+		{
+			name:    "ID prefix",
+			input:   InputQueryControls{IDPrefix: "ccc.c0"},
+			wantIDs: []string{"CCC.C01", "CCC.C06", "CCC.C08", "CCC.C09"},
+		},
+		{
+			name:    "applicability category",
+			input:   InputQueryControls{Applicability: "tlp_clear"},
+			wantIDs: []string{"CCC.C01", "CCC.C06", "CCC.C09"},
+		},
+		{
+			name:    "guideline document",
+			input:   InputQueryControls{Guideline: "ISO-27001"},
+			wantIDs: []string{"CCC.C01", "CCC.C06"},
+		},
+		{
+			name:    "guideline entry",
+			input:   InputQueryControls{Guideline: "PR.DS-5"},
+			wantIDs: []string{"CCC.C08", "CCC.C10"},
+		},
+		{
+			name:    "qualified guideline entry",
+			input:   InputQueryControls{Guideline: "NIST-800-53:SC-8"},
+			wantIDs: []string{"CCC.C01"},
+		},
+		{
+			name:    "guideline entry of another document",
+			input:   InputQueryControls{Guideline: "CCM:SC-8"},
+			wantIDs: []string{},
+		},
+		{
+			name:    "threat",
+			input:   InputQueryControls{Threat: "CCC.TH04"},
+			wantIDs: []string{"CCC.C09", "CCC.C10"},
+		},
+		{
+			name:    "text in title or objective",
+			input:   InputQueryControls{Text: "REPLICAT"},
+			wantIDs: []string{"CCC.C08", "CCC.C10"},
+		},
+		{
+			name:    "combined filters",
+			input:   InputQueryControls{Text: "data", Applicability: "tlp_clear", Guideline: "NIST-800-53"},
+			wantIDs: []string{"CCC.C01"},
+		},
+		{
+			name:        "missing catalog",
+			input:       InputQueryControls{ArtifactContent: ""},
+			wantErr:     true,
+			errContains: "artifact_content is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			if !tt.wantErr {
+				input.ArtifactContent = catalog
+			}
+			_, output, err := QueryControls(context.Background(), nil, input)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			assert.Equal(t, "FINOS-CCC", output.CatalogID)
+			assert.Equal(t, 5, output.Total)
+			ids := []string{}
+			for _, control := range output.Controls {
+				ids = append(ids, control.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestQueryControlsSummary(t *testing.T) {
+	_, output, err := QueryControls(context.Background(), nil, InputQueryControls{
+		ArtifactContent: readTestCatalog(t),
+		IDPrefix:        "CCC.C10",
+	})
+	require.NoError(t, err)
+	require.Len(t, output.Controls, 1)
+	assert.Equal(t, ControlSummary{
+		ID:            "CCC.C10",
+		Family:        "data-protection",
+		Title:         "Prevent Data Replication to Destinations Outside of Defined Trust Perimeter",
+		Objective:     "Prevent replication of data to untrusted destinations outside of defined trust perimeter. An untrusted destination is defined as a resource that exists outside of a specified trusted identity or network or data perimeter.",
+		Requirements:  1,
+		Applicability: []string{"tlp_green", "tlp_amber", "tlp_red"},
+		Threats:       []string{"CCC:CCC.TH04"},
+		Guidelines:    []string{"CSF:PR.DS-5", "CCM:DSP-10", "CCM:DSP-19", "NIST-800-53:AC-4"},
+	}, output.Controls[0])
+}
+
+func TestQueryControlsWorkspaceArtifact(t *testing.T) {
+	dir := newTestWorkspace(t)
+	root := &mcp.Root{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String()}
+	session := newTestClientSession(t, newTestAdvisoryMode(t), nil, root)
+	ctx := context.Background()
+
+	require.Eventually(t, func() bool { return len(resourceURIs(t, session)) > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "query_controls",
+		Arguments: map[string]any{"artifact_id": "FINOS-CCC", "threat": "CCC.TH02"},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "%v", result.Content)
+	output := result.StructuredContent.(map[string]any)
+	assert.Equal(t, filepath.Join(dir, "catalogs", "ccc.yaml"), output["source"])
+	assert.Len(t, output["controls"], 1)
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "query_controls",
+		Arguments: map[string]any{"artifact_id": "FINOS-CCC", "artifact_content": "controls: []\n"},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "cannot be combined")
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "query_controls",
+		Arguments: map[string]any{"artifact_id": "ACME"},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "not in the workspace index")
+}
@@ -0,0 +1,346 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Coverage statuses of framework entries.
+const (
+	coverageCovered   = "covered"
+	coverageWeak      = "weak"
+	coverageUncovered = "uncovered"
+)
+
+// defaultWeakBelow is the strength below which mappings only weakly cover an entry.
+const defaultWeakBelow = 5
+
+// MetadataAnalyzeCoverage describes the AnalyzeCoverage tool.
+var MetadataAnalyzeCoverage = &mcp.Tool{
+	Name:         "analyze_coverage",
+	Description:  "Measure how much of a framework such as NIST-800-53 or ISO-27001 a Gemara control catalog covers through its guideline mappings. The framework's entries come from a guidance document or a list of entry IDs; each is reported as covered, weakly covered (only low-strength mappings) or uncovered, with a per-family breakdown, as JSON or a markdown report for auditors.",
+	InputSchema:  withEnum(schemaFor[InputAnalyzeCoverage](), "format", formatJSON, formatMarkdown),
+	OutputSchema: schemaFor[OutputAnalyzeCoverage](),
+	Annotations:  readOnlyAnnotations(true),
+}
+
+// InputAnalyzeCoverage is the input for the AnalyzeCoverage tool.
+type InputAnalyzeCoverage struct {
+	ArtifactContent string   `json:"artifact_content,omitempty" jsonschema:"YAML content of the control catalog"`
+	ArtifactSource  string   `json:"artifact_source,omitempty" jsonschema:"Location to load the control catalog from instead of artifact_content (e.g., 'oci://ghcr.io/org/catalogs:v1#ccc.yaml', 'https://...')"`
+	ArtifactID      string   `json:"artifact_id,omitempty" jsonschema:"Metadata ID of a control catalog in the client's workspace instead of artifact_content (e.g., 'FINOS-CCC')"`
+	GuidanceContent string   `json:"guidance_content,omitempty" jsonschema:"YAML content of the guidance document listing the framework's guidelines"`
+	GuidanceSource  string   `json:"guidance_source,omitempty" jsonschema:"Location to load the guidance document from instead of guidance_content"`
+	GuidanceID      string   `json:"guidance_id,omitempty" jsonschema:"Metadata ID of a guidance document in the client's workspace instead of guidance_content (e.g., 'NIST-800-53')"`
+	Framework       string   `json:"framework,omitempty" jsonschema:"Reference ID the catalog's guideline mappings use for the framework (default: the guidance document's metadata.id; required with guidelines)"`
+	Guidelines      []string `json:"guidelines,omitempty" jsonschema:"IDs of the framework's entries, instead of a guidance document (e.g., ['SC-8', 'SC-13', 'SC-28'])"`
+	WeakBelow       int      `json:"weak_below,omitempty" jsonschema:"Entries whose strongest mapping is below this strength are only weakly covered (default: 5)"`
+	Format          string   `json:"format,omitempty" jsonschema:"Output format (default: 'json')"`
+}
+
+// OutputAnalyzeCoverage is the output for the AnalyzeCoverage tool.
+type OutputAnalyzeCoverage struct {
+	CatalogID string `json:"catalog_id"`
+	Framework string `json:"framework"`
+	// WeakBelow is the strength below which mappings only weakly cover an entry.
+	WeakBelow int                 `json:"weak_below"`
+	Summary   CoverageSummary     `json:"summary"`
+	Families  []FamilyCoverage    `json:"families"`
+	Entries   []GuidelineCoverage `json:"entries,omitempty"`
+	// Unlisted are the framework entries the catalog maps to that the framework does not list.
+	Unlisted []string `json:"unlisted,omitempty"`
+	// Document holds the markdown report in that format.
+	Document       string `json:"document,omitempty"`
+	Source         string `json:"source,omitempty"`
+	GuidanceSource string `json:"guidance_source,omitempty"`
+}
+
+// CoverageSummary counts framework entries by coverage status.
+type CoverageSummary struct {
+	Total     int `json:"total"`
+	Covered   int `json:"covered"`
+	Weak      int `json:"weak"`
+	Uncovered int `json:"uncovered"`
+	// Percent is the share of entries that are covered, not counting weakly covered ones.
+	Percent float64 `json:"percent"`
+}
+
+// FamilyCoverage is the coverage of the entries of a framework family.
+type FamilyCoverage struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+	CoverageSummary
+}
+
+// GuidelineCoverage is the coverage of a single framework entry.
+type GuidelineCoverage struct {
+	ID     string `json:"id"`
+	Title  string `json:"title,omitempty"`
+	Family string `json:"family,omitempty"`
+	Status string `json:"status"`
+	// Strength is the strongest mapping to the entry.
+	Strength int               `json:"strength,omitempty"`
+	Controls []CoveringControl `json:"controls,omitempty"`
+}
+
+// CoveringControl is a control mapped to a framework entry.
+type CoveringControl struct {
+	ID       string `json:"id"`
+	Strength int    `json:"strength"`
+}
+
+// AnalyzeCoverage compares the guideline mappings of a control catalog with the entries of a
+// framework.
+func AnalyzeCoverage(_ context.Context, _ *mcp.CallToolRequest, input InputAnalyzeCoverage) (*mcp.CallToolResult, OutputAnalyzeCoverage, error) {
+	format := input.Format
+	if format == "" {
+		format = formatJSON
+	}
+	if format != formatJSON && format != formatMarkdown {
+		return nil, OutputAnalyzeCoverage{}, fmt.Errorf("unsupported format %q: use %q or %q", format, formatJSON, formatMarkdown)
+	}
+	if input.ArtifactContent == "" {
+		return nil, OutputAnalyzeCoverage{}, fmt.Errorf("artifact_content is required")
+	}
+	weakBelow := input.WeakBelow
+	if weakBelow == 0 {
+		weakBelow = defaultWeakBelow
+	}
+	if weakBelow < 1 || weakBelow > maxMappingStrength {
+		return nil, OutputAnalyzeCoverage{}, fmt.Errorf("weak_below must be between 1 and %d", maxMappingStrength)
+	}
+
+	catalog, err := ParseControlCatalog([]byte(input.ArtifactContent))
+	if err != nil {
+		return nil, OutputAnalyzeCoverage{}, err
+	}
+	framework, err := coverageFramework(input)
+	if err != nil {
+		return nil, OutputAnalyzeCoverage{}, err
+	}
+
+	output := OutputAnalyzeCoverage{
+		CatalogID: catalog.Metadata.ID,
+		Framework: framework.Metadata.ID,
+		WeakBelow: weakBelow,
+	}
+	entries, unlisted := guidelineCoverage(catalog, framework, weakBelow)
+	output.Unlisted = unlisted
+	output.Summary, output.Families = summarizeCoverage(framework, entries)
+
+	switch format {
+	case formatJSON:
+		output.Entries = entries
+	case formatMarkdown:
+		output.Document = renderCoverageReport(catalog, framework, output, entries)
+	}
+	return nil, output, nil
+}
+
+// coverageFramework returns the framework to measure coverage of as a guidance document,
+// built from the listed entry IDs when no document is given.
+func coverageFramework(input InputAnalyzeCoverage) (*GuidanceDocument, error) {
+	if input.GuidanceContent != "" {
+		if len(input.Guidelines) > 0 {
+			return nil, fmt.Errorf("guidelines cannot be combined with a guidance document")
+		}
+		document, err := ParseGuidanceDocument([]byte(input.GuidanceContent))
+		if err != nil {
+			return nil, err
+		}
+		if input.Framework != "" {
+			document.Metadata.ID = input.Framework
+		}
+		if document.Metadata.ID == "" {
+			return nil, fmt.Errorf("framework is required when the guidance document has no metadata.id")
+		}
+		return document, nil
+	}
+
+	if len(input.Guidelines) == 0 {
+		return nil, fmt.Errorf("guidance_content or guidelines is required")
+	}
+	if input.Framework == "" {
+		return nil, fmt.Errorf("framework is required with guidelines")
+	}
+	document := &GuidanceDocument{Metadata: Metadata{ID: input.Framework}}
+	for _, id := range input.Guidelines {
+		document.Guidelines = append(document.Guidelines, Guideline{ID: id})
+	}
+	return document, nil
+}
+
+// guidelineCoverage determines the coverage of each framework entry from the catalog's
+// mappings, in framework order, and lists mapped entries the framework does not list.
+func guidelineCoverage(catalog *ControlCatalog, framework *GuidanceDocument, weakBelow int) ([]GuidelineCoverage, []string) {
+	mapped := make(map[string][]CoveringControl)
+	var order []string
+	for _, control := range catalog.Controls {
+		for _, mapping := range control.GuidelineMappings {
+			if !strings.EqualFold(mapping.ReferenceID, framework.Metadata.ID) {
+				continue
+			}
+			for _, entry := range mapping.Entries {
+				strength := entry.Strength
+				if strength == 0 {
+					strength = defaultMappingStrength
+				}
+				key := strings.ToLower(entry.ReferenceID)
+				if _, ok := mapped[key]; !ok {
+					order = append(order, entry.ReferenceID)
+				}
+				mapped[key] = append(mapped[key], CoveringControl{ID: control.ID, Strength: strength})
+			}
+		}
+	}
+
+	entries := make([]GuidelineCoverage, 0, len(framework.Guidelines))
+	listed := make(map[string]bool)
+	for _, guideline := range framework.Guidelines {
+		key := strings.ToLower(guideline.ID)
+		listed[key] = true
+		entry := GuidelineCoverage{
+			ID:       guideline.ID,
+			Title:    compactText(guideline.Title),
+			Family:   guideline.Family,
+			Status:   coverageUncovered,
+			Controls: mapped[key],
+		}
+		for _, control := range entry.Controls {
+			entry.Strength = max(entry.Strength, control.Strength)
+		}
+		switch {
+		case entry.Strength >= weakBelow:
+			entry.Status = coverageCovered
+		case entry.Strength > 0:
+			entry.Status = coverageWeak
+		}
+		entries = append(entries, entry)
+	}
+
+	var unlisted []string
+	for _, id := range order {
+		if !listed[strings.ToLower(id)] {
+			unlisted = append(unlisted, id)
+		}
+	}
+	return entries, unlisted
+}
+
+// summarizeCoverage counts entries by status overall and per family, in the order the
+// framework declares its families.
+func summarizeCoverage(framework *GuidanceDocument, entries []GuidelineCoverage) (CoverageSummary, []FamilyCoverage) {
+	var summary CoverageSummary
+	families := []FamilyCoverage{}
+	index := make(map[string]int)
+	for _, family := range framework.Families {
+		index[family.ID] = len(families)
+		families = append(families, FamilyCoverage{ID: family.ID, Title: family.Title})
+	}
+	for _, entry := range entries {
+		i, ok := index[entry.Family]
+		if !ok {
+			i = len(families)
+			index[entry.Family] = i
+			families = append(families, FamilyCoverage{ID: entry.Family})
+		}
+		summary.count(entry.Status)
+		families[i].count(entry.Status)
+	}
+
+	// Families without entries in the framework have nothing to cover.
+	kept := families[:0]
+	for _, family := range families {
+		if family.Total > 0 {
+			kept = append(kept, family)
+		}
+	}
+	return summary, kept
+}
+
+// count adds an entry of the given status.
+func (s *CoverageSummary) count(status string) {
+	s.Total++
+	switch status {
+	case coverageCovered:
+		s.Covered++
+	case coverageWeak:
+		s.Weak++
+	default:
+		s.Uncovered++
+	}
+	s.Percent = math.Round(float64(s.Covered)/float64(s.Total)*1000) / 10
+}
+
+// renderCoverageReport renders coverage as a markdown report.
+func renderCoverageReport(catalog *ControlCatalog, framework *GuidanceDocument, output OutputAnalyzeCoverage, entries []GuidelineCoverage) string {
+	catalogTitle := catalog.Title
+	if catalogTitle == "" {
+		catalogTitle = catalog.Metadata.ID
+	}
+	frameworkTitle := framework.Title
+	if frameworkTitle == "" {
+		frameworkTitle = framework.Metadata.ID
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Coverage of %s by %s\n\n", frameworkTitle, catalogTitle)
+	s := output.Summary
+	fmt.Fprintf(&b, "%d of %d guidelines covered (%.1f%%), %d weakly covered and %d uncovered. Mappings below strength %d count as weak.\n",
+		s.Covered, s.Total, s.Percent, s.Weak, s.Uncovered, output.WeakBelow)
+
+	b.WriteString("\n| Family | Guidelines | Covered | Weak | Uncovered | Coverage |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, family := range output.Families {
+		name := family.ID
+		switch {
+		case family.Title != "":
+			name = fmt.Sprintf("%s (%s)", family.Title, family.ID)
+		case name == "":
+			name = "(no family)"
+		}
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %.1f%% |\n",
+			tableCell(name), family.Total, family.Covered, family.Weak, family.Uncovered, family.Percent)
+	}
+
+	for _, section := range []struct {
+		status, heading string
+	}{
+		{coverageUncovered, "Uncovered"},
+		{coverageWeak, "Weakly covered"},
+		{coverageCovered, "Covered"},
+	} {
+		var lines []string
+		for _, entry := range entries {
+			if entry.Status != section.status {
+				continue
+			}
+			line := "- **" + entry.ID + "**"
+			if entry.Title != "" {
+				line += " " + entry.Title
+			}
+			if len(entry.Controls) > 0 {
+				controls := make([]string, 0, len(entry.Controls))
+				for _, control := range entry.Controls {
+					controls = append(controls, fmt.Sprintf("%s (%d)", control.ID, control.Strength))
+				}
+				line += ": " + strings.Join(controls, ", ")
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n## %s\n\n%s\n", section.heading, strings.Join(lines, "\n"))
+		}
+	}
+
+	if len(output.Unlisted) > 0 {
+		fmt.Fprintf(&b, "\n## Mapped entries not in the framework\n\n- %s\n", strings.Join(output.Unlisted, "\n- "))
+	}
+	return b.String()
+}
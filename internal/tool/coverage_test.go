@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeCoverage(t *testing.T) {
+	catalog := readTestCatalog(t)
+	guidance, err := os.ReadFile(filepath.Join("testdata", "nist-800-53.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		input          InputAnalyzeCoverage
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputAnalyzeCoverage)
+	}{
+		{
+			name:  "guidance document",
+			input: InputAnalyzeCoverage{GuidanceContent: string(guidance)},
+			validateOutput: func(t *testing.T, output OutputAnalyzeCoverage) {
+				assert.Equal(t, "FINOS-CCC", output.CatalogID)
+				assert.Equal(t, "NIST-800-53", output.Framework)
+				assert.Equal(t, CoverageSummary{Total: 10, Covered: 7, Uncovered: 3, Percent: 70}, output.Summary)
+				assert.Equal(t, []FamilyCoverage{
+					{ID: "AC", Title: "Access Control", CoverageSummary: CoverageSummary{Total: 3, Covered: 2, Uncovered: 1, Percent: 66.7}},
+					{ID: "AU", Title: "Audit and Accountability", CoverageSummary: CoverageSummary{Total: 2, Covered: 1, Uncovered: 1, Percent: 50}},
+					{ID: "CP", Title: "Contingency Planning", CoverageSummary: CoverageSummary{Total: 2, Covered: 2, Percent: 100}},
+					{ID: "SC", Title: "System and Communications Protection", CoverageSummary: CoverageSummary{Total: 3, Covered: 2, Uncovered: 1, Percent: 66.7}},
+				}, output.Families)
+				require.Len(t, output.Entries, 10)
+				assert.Equal(t, GuidelineCoverage{
+					ID:       "SC-8",
+					Title:    "Transmission Confidentiality and Integrity",
+					Family:   "SC",
+					Status:   coverageCovered,
+					Strength: 7,
+					Controls: []CoveringControl{{ID: "CCC.C01", Strength: 7}},
+				}, output.Entries[7])
+				assert.Equal(t, GuidelineCoverage{ID: "SC-28", Title: "Protection of Information at Rest", Family: "SC", Status: coverageUncovered}, output.Entries[9])
+				assert.Empty(t, output.Unlisted)
+			},
+		},
+		{
+			name:  "weak mappings",
+			input: InputAnalyzeCoverage{GuidanceContent: string(guidance), WeakBelow: 8},
+			validateOutput: func(t *testing.T, output OutputAnalyzeCoverage) {
+				assert.Equal(t, CoverageSummary{Total: 10, Weak: 7, Uncovered: 3}, output.Summary)
+				assert.Equal(t, coverageWeak, output.Entries[0].Status)
+			},
+		},
+		{
+			name:  "framework entry IDs",
+			input: InputAnalyzeCoverage{Framework: "ISO-27001", Guidelines: []string{"2013 A.13.1.1", "2013 A.8.2.3"}},
+			validateOutput: func(t *testing.T, output OutputAnalyzeCoverage) {
+				assert.Equal(t, CoverageSummary{Total: 2, Covered: 1, Uncovered: 1, Percent: 50}, output.Summary)
+				assert.Equal(t, []FamilyCoverage{{CoverageSummary: output.Summary}}, output.Families)
+				assert.Equal(t, []string{"2013 A.11.1.1"}, output.Unlisted)
+			},
+		},
+		{
+			name:  "markdown report",
+			input: InputAnalyzeCoverage{GuidanceContent: string(guidance), Format: formatMarkdown},
+			validateOutput: func(t *testing.T, output OutputAnalyzeCoverage) {
+				assert.Nil(t, output.Entries)
+				assert.True(t, strings.HasPrefix(output.Document, "# Coverage of NIST SP 800-53 Rev. 5 (excerpt) by FINOS Cloud Control Catalog\n\n"+
+					"7 of 10 guidelines covered (70.0%), 0 weakly covered and 3 uncovered. Mappings below strength 5 count as weak.\n"), output.Document)
+				assert.Contains(t, output.Document, "| Access Control (AC) | 3 | 2 | 0 | 1 | 66.7% |\n")
+				assert.Contains(t, output.Document, "\n## Uncovered\n\n- **AC-17** Remote Access\n- **AU-11** Audit Record Retention\n- **SC-28** Protection of Information at Rest\n")
+				assert.Contains(t, output.Document, "- **CP-10** System Recovery and Reconstitution: CCC.C08 (7)\n")
+				assert.NotContains(t, output.Document, "## Weakly covered")
+			},
+		},
+		{
+			name:        "missing framework",
+			input:       InputAnalyzeCoverage{},
+			wantErr:     true,
+			errContains: "guidance_content or guidelines is required",
+		},
+		{
+			name:        "entry IDs without framework",
+			input:       InputAnalyzeCoverage{Guidelines: []string{"SC-8"}},
+			wantErr:     true,
+			errContains: "framework is required with guidelines",
+		},
+		{
+			name:        "guidance document and entry IDs",
+			input:       InputAnalyzeCoverage{GuidanceContent: string(guidance), Guidelines: []string{"SC-8"}},
+			wantErr:     true,
+			errContains: "guidelines cannot be combined with a guidance document",
+		},
+		{
+			name:        "weak threshold out of range",
+			input:       InputAnalyzeCoverage{GuidanceContent: string(guidance), WeakBelow: 11},
+			wantErr:     true,
+			errContains: "weak_below must be between 1 and 10",
+		},
+		{
+			name:        "unsupported format",
+			input:       InputAnalyzeCoverage{GuidanceContent: string(guidance), Format: "csv"},
+			wantErr:     true,
+			errContains: `unsupported format "csv"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			input.ArtifactContent = catalog
+			_, output, err := AnalyzeCoverage(context.Background(), nil, input)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			tt.validateOutput(t, output)
+		})
+	}
+}
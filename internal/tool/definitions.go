@@ -0,0 +1,272 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/format"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// layerFilePattern matches the schema files that hold the definitions of a Gemara layer.
+var layerFilePattern = regexp.MustCompile(`^layer-(\d+)\.cue$`)
+
+// MetadataListDefinitions describes the ListDefinitions tool.
+var MetadataListDefinitions = &mcp.Tool{
+	Name:         "list_definitions",
+	Description:  "List the definitions of the Gemara CUE schema that artifacts can be validated against, with their layer and doc comment.",
+	InputSchema:  schemaFor[InputListDefinitions](),
+	OutputSchema: schemaFor[OutputListDefinitions](),
+	Annotations:  readOnlyAnnotations(false),
+}
+
+// InputListDefinitions is the input for the ListDefinitions tool.
+type InputListDefinitions struct {
+	Version string `json:"version,omitempty" jsonschema:"Version of the Gemara module (default: 'latest')"`
+	Layer   int    `json:"layer,omitempty" jsonschema:"Only list definitions of this Gemara layer (e.g., 2 for control and threat catalogs)"`
+}
+
+// DefinitionSummary names a definition of the Gemara schema.
+type DefinitionSummary struct {
+	Name  string `json:"name"`
+	Layer int    `json:"layer,omitempty"`
+	Doc   string `json:"doc,omitempty"`
+}
+
+// OutputListDefinitions is the output for the ListDefinitions tool.
+type OutputListDefinitions struct {
+	Version     string              `json:"version"`
+	Definitions []DefinitionSummary `json:"definitions"`
+}
+
+// ListDefinitions lists the definitions of the module loaded by the specified schema loader.
+func ListDefinitions(ctx context.Context, _ *mcp.CallToolRequest, input InputListDefinitions, loader *SchemaLoader) (*mcp.CallToolResult, OutputListDefinitions, error) {
+	schema, err := loader.Load(ctx, input.Version, false)
+	if err != nil {
+		return nil, OutputListDefinitions{}, err
+	}
+
+	defs, err := schemaDefinitions(schema.Value)
+	if err != nil {
+		return nil, OutputListDefinitions{}, err
+	}
+
+	summaries := make([]DefinitionSummary, 0, len(defs))
+	for _, def := range defs {
+		if input.Layer != 0 && def.Layer != input.Layer {
+			continue
+		}
+		summaries = append(summaries, DefinitionSummary{
+			Name:  def.Name,
+			Layer: def.Layer,
+			Doc:   def.Doc,
+		})
+	}
+
+	return nil, OutputListDefinitions{
+		Version:     schema.Version,
+		Definitions: summaries,
+	}, nil
+}
+
+// DefinitionDoc describes a definition of the Gemara schema.
+type DefinitionDoc struct {
+	Name string `json:"name"`
+	// Layer is the Gemara layer the definition belongs to, or zero for shared definitions.
+	Layer      int        `json:"layer,omitempty"`
+	Doc        string     `json:"doc,omitempty"`
+	Type       string     `json:"type"`
+	Constraint string     `json:"constraint,omitempty"`
+	Fields     []FieldDoc `json:"fields,omitempty"`
+}
+
+// FieldDoc describes a field of a definition.
+type FieldDoc struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Required   bool   `json:"required"`
+	Constraint string `json:"constraint,omitempty"`
+	Default    string `json:"default,omitempty"`
+	Doc        string `json:"doc,omitempty"`
+}
+
+// schemaDefinitions describes all definitions at the root of the schema, ordered by
+// layer and name with shared definitions first.
+func schemaDefinitions(schema cue.Value) ([]DefinitionDoc, error) {
+	it, err := schema.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schema definitions: %w", err)
+	}
+
+	var defs []DefinitionDoc
+	for it.Next() {
+		if !it.Selector().IsDefinition() {
+			continue
+		}
+		defs = append(defs, describeDefinition(it.Selector().String(), it.Value()))
+	}
+
+	sort.SliceStable(defs, func(i, j int) bool {
+		if defs[i].Layer != defs[j].Layer {
+			return defs[i].Layer < defs[j].Layer
+		}
+		return defs[i].Name < defs[j].Name
+	})
+	return defs, nil
+}
+
+// lookupDefinition finds a definition by name, with or without its leading '#'.
+func lookupDefinition(schema cue.Value, name string) (cue.Value, string, error) {
+	if !strings.HasPrefix(name, "#") {
+		name = "#" + name
+	}
+	v := schema.LookupPath(cue.ParsePath(name))
+	if !v.Exists() {
+		return cue.Value{}, name, fmt.Errorf("definition %s not found in schema", name)
+	}
+	return v, name, nil
+}
+
+// describeDefinition documents a single definition and, for structs, its fields.
+func describeDefinition(name string, v cue.Value) DefinitionDoc {
+	def := DefinitionDoc{
+		Name:  name,
+		Layer: definitionLayer(v),
+		Doc:   docText(v),
+		Type:  valueType(v),
+	}
+
+	if v.IncompleteKind() != cue.StructKind {
+		def.Constraint = sourceExpr(v)
+		return def
+	}
+
+	it, err := v.Fields(cue.Optional(true))
+	if err != nil {
+		return def
+	}
+	for it.Next() {
+		def.Fields = append(def.Fields, describeField(it.Selector(), it.Value()))
+	}
+	return def
+}
+
+func describeField(sel cue.Selector, v cue.Value) FieldDoc {
+	field := FieldDoc{
+		Name:     selectorName(sel),
+		Type:     valueType(v),
+		Required: sel.ConstraintType() != cue.OptionalConstraint,
+		Doc:      docText(v),
+	}
+	if expr := sourceExpr(v); expr != field.Type {
+		field.Constraint = expr
+	}
+	if kind := v.IncompleteKind(); kind != cue.ListKind && kind != cue.StructKind {
+		if d, ok := v.Default(); ok && d.IsConcrete() {
+			field.Default = fmt.Sprint(d)
+		}
+	}
+	return field
+}
+
+// selectorName returns the plain name of a field label without quotes or optionality markers.
+func selectorName(sel cue.Selector) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(sel.String(), "?"), "!")
+	if unquoted, err := strconv.Unquote(name); err == nil {
+		return unquoted
+	}
+	return name
+}
+
+// valueType names the type of a value, preferring the definition it references.
+func valueType(v cue.Value) string {
+	if expr := sourceValue(v); expr != nil {
+		if t := exprType(expr); t != "" {
+			return t
+		}
+	}
+	kind := v.IncompleteKind()
+	if kind == cue.ListKind {
+		elem := v.LookupPath(cue.MakePath(cue.AnyIndex))
+		if elem.Exists() {
+			return "[..." + valueType(elem) + "]"
+		}
+	}
+	return kind.String()
+}
+
+// exprType returns the referenced definition for identifiers and lists of them.
+func exprType(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		if strings.HasPrefix(x.Name, "#") {
+			return x.Name
+		}
+	case *ast.ListLit:
+		if len(x.Elts) == 1 {
+			if ellipsis, ok := x.Elts[0].(*ast.Ellipsis); ok && ellipsis.Type != nil {
+				if t := exprType(ellipsis.Type); t != "" {
+					return "[..." + t + "]"
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// sourceValue returns the expression a field or definition was declared with.
+func sourceValue(v cue.Value) ast.Expr {
+	switch n := v.Source().(type) {
+	case *ast.Field:
+		return n.Value
+	case ast.Expr:
+		return n
+	}
+	return nil
+}
+
+// sourceExpr formats the declared expression of a value on a single line.
+func sourceExpr(v cue.Value) string {
+	expr := sourceValue(v)
+	if expr == nil {
+		return ""
+	}
+	b, err := format.Node(expr)
+	if err != nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(string(b)), " ")
+}
+
+// docText joins the doc comments attached to a value.
+func docText(v cue.Value) string {
+	var parts []string
+	for _, group := range v.Doc() {
+		if text := strings.TrimSpace(group.Text()); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// definitionLayer infers the Gemara layer from the schema file declaring the definition.
+func definitionLayer(v cue.Value) int {
+	m := layerFilePattern.FindStringSubmatch(filepath.Base(v.Pos().Filename()))
+	if m == nil {
+		return 0
+	}
+	layer, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return layer
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDefinitions(t *testing.T) {
+	loader := newTestSchemaLoader(t)
+
+	tests := []struct {
+		name           string
+		input          InputListDefinitions
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputListDefinitions)
+	}{
+		{
+			name:  "all definitions of the latest version",
+			input: InputListDefinitions{},
+			validateOutput: func(t *testing.T, output OutputListDefinitions) {
+				assert.Equal(t, "v0.2.0", output.Version)
+				require.NotEmpty(t, output.Definitions)
+				assert.Equal(t, 0, output.Definitions[0].Layer, "shared definitions should be listed first")
+				assert.Contains(t, output.Definitions, DefinitionSummary{
+					Name:  "#EvaluationLog",
+					Layer: 5,
+					Doc:   "EvaluationLog records the results of evaluating controls against a target.",
+				})
+			},
+		},
+		{
+			name:  "filter by layer",
+			input: InputListDefinitions{Version: "v0.1.0", Layer: 2},
+			validateOutput: func(t *testing.T, output OutputListDefinitions) {
+				var names []string
+				for _, def := range output.Definitions {
+					names = append(names, def.Name)
+				}
+				assert.Equal(t, []string{"#AssessmentRequirement", "#Capability", "#Control", "#ControlCatalog", "#Threat", "#ThreatCatalog"}, names)
+			},
+		},
+		{
+			name:  "explicit version",
+			input: InputListDefinitions{Version: "v0.0.1"},
+			validateOutput: func(t *testing.T, output OutputListDefinitions) {
+				assert.Equal(t, "v0.0.1", output.Version)
+				assert.Len(t, output.Definitions, 3, "older version should only list its own definitions")
+			},
+		},
+		{
+			name:        "invalid version",
+			input:       InputListDefinitions{Version: "1.0"},
+			wantErr:     true,
+			errContains: `invalid schema version "1.0"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := ListDefinitions(context.Background(), nil, tt.input, loader)
+
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			if tt.validateOutput != nil {
+				tt.validateOutput(t, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Results of evaluations and assessments, in the order the schema declares them.
+const (
+	resultNotRun        = "Not Run"
+	resultPassed        = "Passed"
+	resultFailed        = "Failed"
+	resultNeedsReview   = "Needs Review"
+	resultNotApplicable = "Not Applicable"
+	resultUnknown       = "Unknown"
+	resultSkipped       = "Skipped"
+)
+
+var evaluationResults = []string{resultNotRun, resultPassed, resultFailed, resultNeedsReview, resultNotApplicable, resultUnknown, resultSkipped}
+
+// EvaluationLog records the results of evaluating controls against a target.
+type EvaluationLog struct {
+	Metadata    Metadata            `json:"metadata" yaml:"metadata"`
+	CatalogID   string              `json:"catalog-id,omitempty" yaml:"catalog-id,omitempty"`
+	Evaluations []ControlEvaluation `json:"evaluations" yaml:"evaluations"`
+}
+
+// ControlEvaluation is the outcome of evaluating a single control.
+type ControlEvaluation struct {
+	Name           string          `json:"name" yaml:"name"`
+	ControlID      string          `json:"control-id" yaml:"control-id"`
+	Result         string          `json:"result" yaml:"result"`
+	Message        string          `json:"message" yaml:"message"`
+	AssessmentLogs []AssessmentLog `json:"assessment-logs" yaml:"assessment-logs"`
+}
+
+// AssessmentLog is the outcome of assessing a single requirement.
+type AssessmentLog struct {
+	RequirementID  string   `json:"requirement-id" yaml:"requirement-id"`
+	Applicability  []string `json:"applicability,omitempty" yaml:"applicability,omitempty"`
+	Description    string   `json:"description" yaml:"description"`
+	Result         string   `json:"result" yaml:"result"`
+	Message        string   `json:"message" yaml:"message"`
+	Start          string   `json:"start" yaml:"start"`
+	End            string   `json:"end,omitempty" yaml:"end,omitempty"`
+	Recommendation string   `json:"recommendation,omitempty" yaml:"recommendation,omitempty"`
+}
+
+// ParseEvaluationLog decodes an evaluation log from YAML or JSON content without validating it.
+func ParseEvaluationLog(content []byte) (*EvaluationLog, error) {
+	return parseArtifact(content, "evaluation log", "evaluations", func(l *EvaluationLog) bool {
+		return l.Metadata.ID != "" || len(l.Evaluations) > 0
+	})
+}
+
+// MetadataSummarizeEvaluationLog describes the SummarizeEvaluationLog tool.
+var MetadataSummarizeEvaluationLog = &mcp.Tool{
+	Name:         "summarize_evaluation_log",
+	Description:  "Summarize a Gemara evaluation log: count results per status for controls and assessment requirements, compute pass rates, and link each evaluated control and requirement to its entry in the evaluated control catalog when it is given or found in the client's workspace, as JSON or a markdown report.",
+	InputSchema:  withEnum(schemaFor[InputSummarizeEvaluationLog](), "format", formatJSON, formatMarkdown),
+	OutputSchema: schemaFor[OutputSummarizeEvaluationLog](),
+	Annotations:  readOnlyAnnotations(true),
+}
+
+// InputSummarizeEvaluationLog is the input for the SummarizeEvaluationLog tool.
+type InputSummarizeEvaluationLog struct {
+	ArtifactContent string `json:"artifact_content,omitempty" jsonschema:"YAML or JSON content of the evaluation log"`
+	ArtifactSource  string `json:"artifact_source,omitempty" jsonschema:"Location to load the evaluation log from instead of artifact_content"`
+	ArtifactID      string `json:"artifact_id,omitempty" jsonschema:"Metadata ID of an evaluation log in the client's workspace instead of artifact_content"`
+	CatalogContent  string `json:"catalog_content,omitempty" jsonschema:"YAML content of the evaluated control catalog (default: the workspace catalog named by the log's catalog-id)"`
+	CatalogSource   string `json:"catalog_source,omitempty" jsonschema:"Location to load the evaluated control catalog from instead of catalog_content"`
+	CatalogID       string `json:"catalog_id,omitempty" jsonschema:"Metadata ID of the evaluated control catalog in the client's workspace instead of the log's catalog-id"`
+	Format          string `json:"format,omitempty" jsonschema:"Output format (default: 'json')"`
+}
+
+// OutputSummarizeEvaluationLog is the output for the SummarizeEvaluationLog tool.
+type OutputSummarizeEvaluationLog struct {
+	LogID     string `json:"log_id"`
+	CatalogID string `json:"catalog_id,omitempty"`
+	// Linked reports whether the evaluated catalog was available to link entries to.
+	Linked       bool         `json:"linked"`
+	Controls     ResultCounts `json:"controls"`
+	Requirements ResultCounts `json:"requirements"`
+	// Evaluations hold the results of each control in the JSON format.
+	Evaluations []ControlResult `json:"evaluations,omitempty"`
+	// Unknown are the evaluated controls and requirements the catalog does not define, and
+	// NotEvaluated the catalog's controls the log has no results for.
+	Unknown      []string `json:"unknown,omitempty"`
+	NotEvaluated []string `json:"not_evaluated,omitempty"`
+	// Document holds the markdown report in that format.
+	Document      string `json:"document,omitempty"`
+	Source        string `json:"source,omitempty"`
+	CatalogSource string `json:"catalog_source,omitempty"`
+}
+
+// ResultCounts counts evaluation results by status.
+type ResultCounts struct {
+	Total    int            `json:"total"`
+	ByResult map[string]int `json:"by_result"`
+	// PassRate is the percentage of passed results, leaving out results that are Not Run,
+	// Not Applicable or Skipped.
+	PassRate float64 `json:"pass_rate"`
+}
+
+// ControlResult is the result of evaluating a control, linked to the catalog.
+type ControlResult struct {
+	ControlID string `json:"control_id"`
+	Name      string `json:"name"`
+	Result    string `json:"result"`
+	Message   string `json:"message,omitempty"`
+	// Title and URI describe the control in the catalog, when linked.
+	Title        string              `json:"title,omitempty"`
+	URI          string              `json:"uri,omitempty"`
+	Requirements ResultCounts        `json:"requirements"`
+	Assessments  []RequirementResult `json:"assessments"`
+}
+
+// RequirementResult is the result of assessing a requirement, linked to the catalog.
+type RequirementResult struct {
+	RequirementID  string `json:"requirement_id"`
+	Result         string `json:"result"`
+	Message        string `json:"message,omitempty"`
+	Recommendation string `json:"recommendation,omitempty"`
+	// Text is the requirement as the catalog states it, when linked.
+	Text string `json:"text,omitempty"`
+}
+
+// SummarizeEvaluationLog aggregates the results of an evaluation log, linking them to the
+// evaluated catalog given inline or found in the workspace index.
+func SummarizeEvaluationLog(_ context.Context, _ *mcp.CallToolRequest, input InputSummarizeEvaluationLog, index *WorkspaceIndex) (*mcp.CallToolResult, OutputSummarizeEvaluationLog, error) {
+	format := input.Format
+	if format == "" {
+		format = formatJSON
+	}
+	if format != formatJSON && format != formatMarkdown {
+		return nil, OutputSummarizeEvaluationLog{}, fmt.Errorf("unsupported format %q: use %q or %q", format, formatJSON, formatMarkdown)
+	}
+	if input.ArtifactContent == "" {
+		return nil, OutputSummarizeEvaluationLog{}, fmt.Errorf("artifact_content is required")
+	}
+	log, err := ParseEvaluationLog([]byte(input.ArtifactContent))
+	if err != nil {
+		return nil, OutputSummarizeEvaluationLog{}, err
+	}
+
+	output := OutputSummarizeEvaluationLog{
+		LogID:     log.Metadata.ID,
+		CatalogID: log.CatalogID,
+	}
+	catalog, catalogPath, err := evaluatedCatalog(log, input, index)
+	if err != nil {
+		return nil, OutputSummarizeEvaluationLog{}, err
+	}
+	if catalog != nil {
+		output.CatalogID, output.Linked, output.CatalogSource = catalog.Metadata.ID, true, catalogPath
+	}
+
+	evaluated := make(map[string]bool)
+	var controls, requirements []string
+	for _, evaluation := range log.Evaluations {
+		evaluated[evaluation.ControlID] = true
+		controls = append(controls, evaluation.Result)
+
+		result := ControlResult{
+			ControlID:   evaluation.ControlID,
+			Name:        evaluation.Name,
+			Result:      evaluation.Result,
+			Message:     compactText(evaluation.Message),
+			Assessments: []RequirementResult{},
+		}
+		control, known := Control{}, false
+		if catalog != nil {
+			if control, known = catalog.Control(evaluation.ControlID); known {
+				result.Title = compactText(control.Title)
+				if catalogPath != "" {
+					result.URI = controlURI(catalog.Metadata.ID, control.ID)
+				}
+			} else {
+				output.Unknown = append(output.Unknown, evaluation.ControlID)
+			}
+		}
+
+		var results []string
+		for _, assessment := range evaluation.AssessmentLogs {
+			results = append(results, assessment.Result)
+			requirement := RequirementResult{
+				RequirementID:  assessment.RequirementID,
+				Result:         assessment.Result,
+				Message:        compactText(assessment.Message),
+				Recommendation: compactText(assessment.Recommendation),
+			}
+			if catalog != nil {
+				if r, ok := controlRequirement(control, assessment.RequirementID); ok {
+					requirement.Text = compactText(r.Text)
+				} else {
+					output.Unknown = append(output.Unknown, assessment.RequirementID)
+				}
+			}
+			result.Assessments = append(result.Assessments, requirement)
+		}
+		result.Requirements = countResults(results)
+		requirements = append(requirements, results...)
+		output.Evaluations = append(output.Evaluations, result)
+	}
+	output.Controls = countResults(controls)
+	output.Requirements = countResults(requirements)
+	if catalog != nil {
+		for _, control := range catalog.Controls {
+			if !evaluated[control.ID] {
+				output.NotEvaluated = append(output.NotEvaluated, control.ID)
+			}
+		}
+	}
+
+	if format == formatMarkdown {
+		output.Document = renderEvaluationSummary(log, catalog, output)
+		output.Evaluations = nil
+	}
+	return nil, output, nil
+}
+
+// evaluatedCatalog returns the catalog given inline or, failing that, the workspace catalog
+// the log names with its path, which makes its controls available as resources. It returns
+// nil when neither is available.
+func evaluatedCatalog(log *EvaluationLog, input InputSummarizeEvaluationLog, index *WorkspaceIndex) (*ControlCatalog, string, error) {
+	content, path := input.CatalogContent, ""
+	if content == "" {
+		id := input.CatalogID
+		if id == "" {
+			id = log.CatalogID
+		}
+		artifact, ok := index.Artifact(id)
+		if id == "" || !ok || artifact.Definition != "#ControlCatalog" {
+			if input.CatalogID != "" {
+				return nil, "", fmt.Errorf("catalog %s is not in the workspace index", input.CatalogID)
+			}
+			return nil, "", nil
+		}
+		content, path = string(artifact.Content), artifact.Path
+	}
+
+	catalog, err := ParseControlCatalog([]byte(content))
+	if err != nil {
+		return nil, "", err
+	}
+	if log.CatalogID != "" && catalog.Metadata.ID != log.CatalogID {
+		return nil, "", fmt.Errorf("evaluation log %s evaluates catalog %s, not %s", log.Metadata.ID, log.CatalogID, catalog.Metadata.ID)
+	}
+	return catalog, path, nil
+}
+
+// controlRequirement returns the assessment requirement of a control with the given ID.
+func controlRequirement(control Control, id string) (AssessmentRequirement, bool) {
+	for _, requirement := range control.AssessmentRequirements {
+		if requirement.ID == id {
+			return requirement, true
+		}
+	}
+	return AssessmentRequirement{}, false
+}
+
+// countResults counts results by status and computes their pass rate.
+func countResults(results []string) ResultCounts {
+	counts := ResultCounts{Total: len(results), ByResult: make(map[string]int)}
+	decided := 0
+	for _, result := range results {
+		counts.ByResult[result]++
+		switch result {
+		case resultNotRun, resultNotApplicable, resultSkipped:
+		default:
+			decided++
+		}
+	}
+	if decided > 0 {
+		counts.PassRate = math.Round(float64(counts.ByResult[resultPassed])/float64(decided)*1000) / 10
+	}
+	return counts
+}
+
+// renderEvaluationSummary renders an evaluation summary as a markdown report.
+func renderEvaluationSummary(log *EvaluationLog, catalog *ControlCatalog, output OutputSummarizeEvaluationLog) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Evaluation %s", log.Metadata.ID)
+	switch {
+	case catalog != nil && catalog.Title != "":
+		fmt.Fprintf(&b, " of %s", catalog.Title)
+	case output.CatalogID != "":
+		fmt.Fprintf(&b, " of %s", output.CatalogID)
+	}
+	fmt.Fprintf(&b, "\n\nControls: %d evaluated, %.1f%% passed. Requirements: %d assessed, %.1f%% passed.\n",
+		output.Controls.Total, output.Controls.PassRate, output.Requirements.Total, output.Requirements.PassRate)
+
+	b.WriteString("\n| Result | Controls | Requirements |\n|---|---|---|\n")
+	for _, result := range resultsOf(output.Controls, output.Requirements) {
+		fmt.Fprintf(&b, "| %s | %d | %d |\n", tableCell(result), output.Controls.ByResult[result], output.Requirements.ByResult[result])
+	}
+
+	for _, evaluation := range log.Evaluations {
+		title := evaluation.Name
+		if catalog != nil {
+			if control, ok := catalog.Control(evaluation.ControlID); ok {
+				title = compactText(control.Title)
+			}
+		}
+		fmt.Fprintf(&b, "\n## %s: %s (%s)\n", evaluation.ControlID, title, evaluation.Result)
+		if message := compactText(evaluation.Message); message != "" {
+			fmt.Fprintf(&b, "\n%s\n", message)
+		}
+		if len(evaluation.AssessmentLogs) == 0 {
+			continue
+		}
+		b.WriteString("\n| Requirement | Result | Message |\n|---|---|---|\n")
+		for _, assessment := range evaluation.AssessmentLogs {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", tableCell(assessment.RequirementID), tableCell(assessment.Result), tableCell(assessment.Message))
+		}
+	}
+
+	if len(output.Unknown) > 0 {
+		fmt.Fprintf(&b, "\n## Not in the catalog\n\n- %s\n", strings.Join(output.Unknown, "\n- "))
+	}
+	if len(output.NotEvaluated) > 0 {
+		fmt.Fprintf(&b, "\n## Not evaluated\n\n- %s\n", strings.Join(output.NotEvaluated, "\n- "))
+	}
+	return b.String()
+}
+
+// resultsOf lists the statuses present in any of the counts, schema statuses first in
+// schema order, then others as they sort.
+func resultsOf(counts ...ResultCounts) []string {
+	var results []string
+	for _, result := range evaluationResults {
+		for _, c := range counts {
+			if c.ByResult[result] > 0 {
+				results = append(results, result)
+				break
+			}
+		}
+	}
+	var others []string
+	for _, c := range counts {
+		for result := range c.ByResult {
+			if !slices.Contains(evaluationResults, result) && !slices.Contains(others, result) {
+				others = append(others, result)
+			}
+		}
+	}
+	slices.Sort(others)
+	return append(results, others...)
+}
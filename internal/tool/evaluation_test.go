@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEvaluationLog evaluates controls of the FINOS CCC test catalog, and one it lacks.
+const testEvaluationLog = `metadata:
+  id: weekly-2026-02-01
+  description: Weekly evaluation of the storage account.
+  author:
+    id: ci
+    name: CI
+catalog-id: FINOS-CCC
+evaluations:
+  - name: Encryption in transit
+    control-id: CCC.C01
+    result: Passed
+    message: All endpoints require TLS 1.2.
+    assessment-logs:
+      - requirement-id: CCC.C01.TR01
+        description: TLS handshake on exposed ports
+        result: Passed
+        message: ok
+        start: "2026-02-01T00:00:00Z"
+      - requirement-id: CCC.C01.TR02
+        description: Unencrypted requests are refused
+        result: Passed
+        message: ok
+        start: "2026-02-01T00:00:00Z"
+  - name: Restricted regions
+    control-id: CCC.C06
+    result: Failed
+    message: One bucket is deployed in a restricted region.
+    assessment-logs:
+      - requirement-id: CCC.C06.TR01
+        description: Deployment is refused in restricted regions
+        result: Failed
+        message: bucket "exports" is in a restricted region
+        start: "2026-02-01T00:00:00Z"
+        recommendation: Move the bucket to an approved region.
+      - requirement-id: CCC.C06.TR02
+        description: Replicas stay in approved regions
+        result: Passed
+        message: ok
+        start: "2026-02-01T00:00:00Z"
+  - name: Log integrity
+    control-id: CCC.C09
+    result: Needs Review
+    message: Retention could not be verified.
+    assessment-logs:
+      - requirement-id: CCC.C09.TR01
+        description: Logs are immutable
+        result: Passed
+        message: ok
+        start: "2026-02-01T00:00:00Z"
+      - requirement-id: CCC.C09.TR02
+        description: Log retention
+        result: Needs Review
+        message: retention policy is managed externally
+        start: "2026-02-01T00:00:00Z"
+      - requirement-id: CCC.C09.TR03
+        description: Log deletion alerts
+        result: Not Applicable
+        message: no deletion API
+        start: "2026-02-01T00:00:00Z"
+  - name: Replication
+    control-id: CCC.C08
+    result: Not Applicable
+    message: Replication is disabled.
+    assessment-logs: []
+  - name: Custom check
+    control-id: CCC.C42
+    result: Failed
+    message: Custom check failed.
+    assessment-logs:
+      - requirement-id: CCC.C42.TR01
+        description: Custom requirement
+        result: Failed
+        message: failed
+        start: "2026-02-01T00:00:00Z"
+`
+
+func TestSummarizeEvaluationLog(t *testing.T) {
+	index := newTestMappingIndex(t)
+
+	tests := []struct {
+		name           string
+		input          InputSummarizeEvaluationLog
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputSummarizeEvaluationLog)
+	}{
+		{
+			name:  "catalog from the workspace",
+			input: InputSummarizeEvaluationLog{ArtifactContent: testEvaluationLog},
+			validateOutput: func(t *testing.T, output OutputSummarizeEvaluationLog) {
+				assert.Equal(t, "weekly-2026-02-01", output.LogID)
+				assert.Equal(t, "FINOS-CCC", output.CatalogID)
+				assert.True(t, output.Linked)
+				assert.True(t, strings.HasSuffix(output.CatalogSource, "good-ccc.yaml"), output.CatalogSource)
+				assert.Equal(t, ResultCounts{
+					Total:    5,
+					ByResult: map[string]int{"Passed": 1, "Failed": 2, "Needs Review": 1, "Not Applicable": 1},
+					PassRate: 25,
+				}, output.Controls)
+				assert.Equal(t, ResultCounts{
+					Total:    8,
+					ByResult: map[string]int{"Passed": 4, "Failed": 2, "Needs Review": 1, "Not Applicable": 1},
+					PassRate: 57.1,
+				}, output.Requirements)
+				assert.Equal(t, []string{"CCC.C42", "CCC.C42.TR01"}, output.Unknown)
+				assert.Equal(t, []string{"CCC.C10"}, output.NotEvaluated)
+
+				require.Len(t, output.Evaluations, 5)
+				c06 := output.Evaluations[1]
+				assert.Equal(t, "Prevent Deployment in Restricted Regions", c06.Title)
+				assert.Equal(t, "gemara://artifact/FINOS-CCC/controls/CCC.C06", c06.URI)
+				assert.Equal(t, 50.0, c06.Requirements.PassRate)
+				assert.Equal(t, "Move the bucket to an approved region.", c06.Assessments[0].Recommendation)
+				assert.NotEmpty(t, c06.Assessments[0].Text, "requirements should carry the catalog text")
+				assert.Empty(t, output.Evaluations[4].URI)
+			},
+		},
+		{
+			name:  "inline catalog",
+			input: InputSummarizeEvaluationLog{ArtifactContent: testEvaluationLog, CatalogContent: readTestCatalog(t)},
+			validateOutput: func(t *testing.T, output OutputSummarizeEvaluationLog) {
+				assert.True(t, output.Linked)
+				assert.Empty(t, output.CatalogSource)
+				assert.Empty(t, output.Evaluations[0].URI, "inline catalogs are not workspace resources")
+				assert.NotEmpty(t, output.Evaluations[0].Title)
+			},
+		},
+		{
+			name:  "no catalog available",
+			input: InputSummarizeEvaluationLog{ArtifactContent: strings.Replace(testEvaluationLog, "catalog-id: FINOS-CCC", "catalog-id: ACME-CCC", 1)},
+			validateOutput: func(t *testing.T, output OutputSummarizeEvaluationLog) {
+				assert.False(t, output.Linked)
+				assert.Equal(t, "ACME-CCC", output.CatalogID)
+				assert.Empty(t, output.Unknown)
+				assert.Empty(t, output.NotEvaluated)
+				assert.Empty(t, output.Evaluations[0].Title)
+				assert.Equal(t, 5, output.Controls.Total)
+			},
+		},
+		{
+			name:  "markdown report",
+			input: InputSummarizeEvaluationLog{ArtifactContent: testEvaluationLog, Format: formatMarkdown},
+			validateOutput: func(t *testing.T, output OutputSummarizeEvaluationLog) {
+				assert.Nil(t, output.Evaluations)
+				assert.True(t, strings.HasPrefix(output.Document, "# Evaluation weekly-2026-02-01 of FINOS Cloud Control Catalog\n\n"+
+					"Controls: 5 evaluated, 25.0% passed. Requirements: 8 assessed, 57.1% passed.\n\n"+
+					"| Result | Controls | Requirements |\n|---|---|---|\n| Passed | 1 | 4 |\n| Failed | 2 | 2 |\n| Needs Review | 1 | 1 |\n| Not Applicable | 1 | 1 |\n"), output.Document)
+				assert.Contains(t, output.Document, "\n## CCC.C06: Prevent Deployment in Restricted Regions (Failed)\n\nOne bucket is deployed in a restricted region.\n\n| Requirement | Result | Message |\n")
+				assert.Contains(t, output.Document, "| CCC.C06.TR01 | Failed | bucket \"exports\" is in a restricted region |\n")
+				assert.Contains(t, output.Document, "\n## CCC.C42: Custom check (Failed)\n")
+				assert.Contains(t, output.Document, "\n## Not evaluated\n\n- CCC.C10\n")
+			},
+		},
+		{
+			name:        "catalog of another ID",
+			input:       InputSummarizeEvaluationLog{ArtifactContent: testEvaluationLog, CatalogContent: strings.Replace(readTestCatalog(t), "id: FINOS-CCC", "id: ACME-CCC", 1)},
+			wantErr:     true,
+			errContains: "evaluation log weekly-2026-02-01 evaluates catalog FINOS-CCC, not ACME-CCC",
+		},
+		{
+			name:        "catalog missing from the workspace",
+			input:       InputSummarizeEvaluationLog{ArtifactContent: testEvaluationLog, CatalogID: "ACME-CCC"},
+			wantErr:     true,
+			errContains: "catalog ACME-CCC is not in the workspace index",
+		},
+		{
+			name:        "missing log",
+			input:       InputSummarizeEvaluationLog{},
+			wantErr:     true,
+			errContains: "artifact_content is required",
+		},
+		{
+			name:        "unsupported format",
+			input:       InputSummarizeEvaluationLog{ArtifactContent: testEvaluationLog, Format: "csv"},
+			wantErr:     true,
+			errContains: `unsupported format "csv"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := SummarizeEvaluationLog(context.Background(), nil, tt.input, index)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			tt.validateOutput(t, output)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MetadataDiffEvaluationLogs describes the DiffEvaluationLogs tool.
+var MetadataDiffEvaluationLogs = &mcp.Tool{
+	Name:         "diff_evaluation_logs",
+	Description:  "Compare two Gemara evaluation logs of the same catalog, such as consecutive nightly runs, and report the assessment requirement results that are newly failing, newly passing, otherwise changed, added or removed, as JSON or a markdown report to post in a pull request or ticket.",
+	InputSchema:  withEnum(schemaFor[InputDiffEvaluationLogs](), "format", formatJSON, formatMarkdown),
+	OutputSchema: schemaFor[OutputDiffEvaluationLogs](),
+	Annotations:  readOnlyAnnotations(true),
+}
+
+// InputDiffEvaluationLogs is the input for the DiffEvaluationLogs tool.
+type InputDiffEvaluationLogs struct {
+	FromContent string `json:"from_content,omitempty" jsonschema:"YAML or JSON content of the earlier evaluation log"`
+	FromSource  string `json:"from_source,omitempty" jsonschema:"Location to load the earlier evaluation log from instead of from_content"`
+	FromID      string `json:"from_id,omitempty" jsonschema:"Metadata ID of the earlier evaluation log in the client's workspace instead of from_content"`
+	ToContent   string `json:"to_content,omitempty" jsonschema:"YAML or JSON content of the later evaluation log"`
+	ToSource    string `json:"to_source,omitempty" jsonschema:"Location to load the later evaluation log from instead of to_content"`
+	ToID        string `json:"to_id,omitempty" jsonschema:"Metadata ID of the later evaluation log in the client's workspace instead of to_content"`
+	Format      string `json:"format,omitempty" jsonschema:"Output format (default: 'json')"`
+}
+
+// OutputDiffEvaluationLogs is the output for the DiffEvaluationLogs tool.
+type OutputDiffEvaluationLogs struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	CatalogID string `json:"catalog_id,omitempty"`
+	// Regression reports whether any requirement is newly failing.
+	Regression   bool                `json:"regression"`
+	NewlyFailing []RequirementChange `json:"newly_failing"`
+	NewlyPassing []RequirementChange `json:"newly_passing"`
+	// Changed are the other changes of result, such as Passed to Needs Review.
+	Changed   []RequirementChange `json:"changed"`
+	Added     []RequirementChange `json:"added"`
+	Removed   []RequirementChange `json:"removed"`
+	Unchanged int                 `json:"unchanged"`
+	// Document holds the markdown report in that format.
+	Document   string `json:"document,omitempty"`
+	FromSource string `json:"from_source,omitempty"`
+	ToSource   string `json:"to_source,omitempty"`
+}
+
+// RequirementChange is the change of an assessment requirement's result between two logs.
+type RequirementChange struct {
+	ControlID     string `json:"control_id"`
+	RequirementID string `json:"requirement_id"`
+	// From and To are the results in each log, empty when the requirement is absent from it.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Message is the assessment message of the later log, or of the earlier one for removed results.
+	Message string `json:"message,omitempty"`
+}
+
+// DiffEvaluationLogs compares the requirement results of two evaluation logs.
+func DiffEvaluationLogs(_ context.Context, _ *mcp.CallToolRequest, input InputDiffEvaluationLogs) (*mcp.CallToolResult, OutputDiffEvaluationLogs, error) {
+	format := input.Format
+	if format == "" {
+		format = formatJSON
+	}
+	if format != formatJSON && format != formatMarkdown {
+		return nil, OutputDiffEvaluationLogs{}, fmt.Errorf("unsupported format %q: use %q or %q", format, formatJSON, formatMarkdown)
+	}
+	if input.FromContent == "" {
+		return nil, OutputDiffEvaluationLogs{}, fmt.Errorf("from_content is required")
+	}
+	if input.ToContent == "" {
+		return nil, OutputDiffEvaluationLogs{}, fmt.Errorf("to_content is required")
+	}
+	from, err := ParseEvaluationLog([]byte(input.FromContent))
+	if err != nil {
+		return nil, OutputDiffEvaluationLogs{}, fmt.Errorf("from: %w", err)
+	}
+	to, err := ParseEvaluationLog([]byte(input.ToContent))
+	if err != nil {
+		return nil, OutputDiffEvaluationLogs{}, fmt.Errorf("to: %w", err)
+	}
+	if from.CatalogID != "" && to.CatalogID != "" && from.CatalogID != to.CatalogID {
+		return nil, OutputDiffEvaluationLogs{}, fmt.Errorf("the logs evaluate different catalogs: %s and %s", from.CatalogID, to.CatalogID)
+	}
+
+	output := OutputDiffEvaluationLogs{
+		From:         from.Metadata.ID,
+		To:           to.Metadata.ID,
+		CatalogID:    cmp.Or(to.CatalogID, from.CatalogID),
+		NewlyFailing: []RequirementChange{},
+		NewlyPassing: []RequirementChange{},
+		Changed:      []RequirementChange{},
+		Added:        []RequirementChange{},
+		Removed:      []RequirementChange{},
+	}
+	before := requirementResults(from)
+	after := requirementResults(to)
+	for _, key := range after.keys {
+		current := after.results[key]
+		previous, ok := before.results[key]
+		change := RequirementChange{
+			ControlID:     key.control,
+			RequirementID: key.requirement,
+			From:          previous.Result,
+			To:            current.Result,
+			Message:       compactText(current.Message),
+		}
+		switch {
+		case !ok:
+			output.Added = append(output.Added, change)
+		case previous.Result == current.Result:
+			output.Unchanged++
+		case current.Result == resultFailed:
+			output.NewlyFailing = append(output.NewlyFailing, change)
+		case current.Result == resultPassed:
+			output.NewlyPassing = append(output.NewlyPassing, change)
+		default:
+			output.Changed = append(output.Changed, change)
+		}
+	}
+	for _, key := range before.keys {
+		if _, ok := after.results[key]; ok {
+			continue
+		}
+		previous := before.results[key]
+		output.Removed = append(output.Removed, RequirementChange{
+			ControlID:     key.control,
+			RequirementID: key.requirement,
+			From:          previous.Result,
+			Message:       compactText(previous.Message),
+		})
+	}
+	output.Regression = len(output.NewlyFailing) > 0
+
+	if format == formatMarkdown {
+		output.Document = renderEvaluationDiff(output)
+	}
+	return nil, output, nil
+}
+
+// requirementKey identifies a requirement result within an evaluation log.
+type requirementKey struct {
+	control, requirement string
+}
+
+// loggedResults are the requirement results of a log in log order.
+type loggedResults struct {
+	keys    []requirementKey
+	results map[requirementKey]AssessmentLog
+}
+
+// requirementResults indexes the assessment logs of an evaluation log. When a requirement
+// is assessed more than once, the last assessment counts.
+func requirementResults(log *EvaluationLog) loggedResults {
+	logged := loggedResults{results: make(map[requirementKey]AssessmentLog)}
+	for _, evaluation := range log.Evaluations {
+		for _, assessment := range evaluation.AssessmentLogs {
+			key := requirementKey{control: evaluation.ControlID, requirement: assessment.RequirementID}
+			if _, ok := logged.results[key]; !ok {
+				logged.keys = append(logged.keys, key)
+			}
+			logged.results[key] = assessment
+		}
+	}
+	return logged
+}
+
+// renderEvaluationDiff renders the changes between two evaluation logs as a markdown report.
+func renderEvaluationDiff(output OutputDiffEvaluationLogs) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Evaluation changes from %s to %s\n\n", output.From, output.To)
+	newlyFailing := fmt.Sprintf("%d newly failing", len(output.NewlyFailing))
+	if output.Regression {
+		newlyFailing = "**" + newlyFailing + "**"
+	}
+	fmt.Fprintf(&b, "%s, %d newly passing, %d changed, %d added, %d removed and %d unchanged requirement results.\n",
+		newlyFailing, len(output.NewlyPassing), len(output.Changed), len(output.Added), len(output.Removed), output.Unchanged)
+
+	for _, section := range []struct {
+		heading string
+		changes []RequirementChange
+	}{
+		{"Newly failing", output.NewlyFailing},
+		{"Newly passing", output.NewlyPassing},
+		{"Changed", output.Changed},
+		{"Added", output.Added},
+		{"Removed", output.Removed},
+	} {
+		if len(section.changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n| Control | Requirement | Before | After | Message |\n|---|---|---|---|---|\n", section.heading)
+		for _, change := range section.changes {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				tableCell(change.ControlID), tableCell(change.RequirementID), resultCell(change.From), resultCell(change.To), tableCell(change.Message))
+		}
+	}
+	return b.String()
+}
+
+// resultCell formats a result for a markdown table cell, marking absent results.
+func resultCell(result string) string {
+	if result == "" {
+		return "-"
+	}
+	return tableCell(result)
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffEvaluationLogs(t *testing.T) {
+	later := strings.NewReplacer(
+		"id: weekly-2026-02-01", "id: weekly-2026-02-08",
+		"description: Unencrypted requests are refused\n        result: Passed\n        message: ok",
+		"description: Unencrypted requests are refused\n        result: Failed\n        message: port 80 accepts plain HTTP",
+		"result: Failed\n        message: bucket \"exports\" is in a restricted region",
+		"result: Passed\n        message: ok",
+		"result: Not Applicable\n        message: no deletion API",
+		"result: Skipped\n        message: deletion alerts are not configured",
+		"CCC.C42.TR01", "CCC.C42.TR02",
+	).Replace(testEvaluationLog)
+
+	tests := []struct {
+		name           string
+		input          InputDiffEvaluationLogs
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputDiffEvaluationLogs)
+	}{
+		{
+			name:  "changes between runs",
+			input: InputDiffEvaluationLogs{FromContent: testEvaluationLog, ToContent: later},
+			validateOutput: func(t *testing.T, output OutputDiffEvaluationLogs) {
+				assert.Equal(t, "weekly-2026-02-01", output.From)
+				assert.Equal(t, "weekly-2026-02-08", output.To)
+				assert.Equal(t, "FINOS-CCC", output.CatalogID)
+				assert.True(t, output.Regression)
+				assert.Equal(t, []RequirementChange{{ControlID: "CCC.C01", RequirementID: "CCC.C01.TR02", From: "Passed", To: "Failed", Message: "port 80 accepts plain HTTP"}}, output.NewlyFailing)
+				assert.Equal(t, []RequirementChange{{ControlID: "CCC.C06", RequirementID: "CCC.C06.TR01", From: "Failed", To: "Passed", Message: "ok"}}, output.NewlyPassing)
+				assert.Equal(t, []RequirementChange{{ControlID: "CCC.C09", RequirementID: "CCC.C09.TR03", From: "Not Applicable", To: "Skipped", Message: "deletion alerts are not configured"}}, output.Changed)
+				assert.Equal(t, []RequirementChange{{ControlID: "CCC.C42", RequirementID: "CCC.C42.TR02", To: "Failed", Message: "failed"}}, output.Added)
+				assert.Equal(t, []RequirementChange{{ControlID: "CCC.C42", RequirementID: "CCC.C42.TR01", From: "Failed", Message: "failed"}}, output.Removed)
+				assert.Equal(t, 4, output.Unchanged)
+				assert.Empty(t, output.Document)
+			},
+		},
+		{
+			name:  "identical logs",
+			input: InputDiffEvaluationLogs{FromContent: testEvaluationLog, ToContent: testEvaluationLog},
+			validateOutput: func(t *testing.T, output OutputDiffEvaluationLogs) {
+				assert.False(t, output.Regression)
+				assert.Empty(t, output.NewlyFailing)
+				assert.Empty(t, output.Added)
+				assert.Empty(t, output.Removed)
+				assert.Equal(t, 8, output.Unchanged)
+			},
+		},
+		{
+			name:  "markdown report",
+			input: InputDiffEvaluationLogs{FromContent: testEvaluationLog, ToContent: later, Format: formatMarkdown},
+			validateOutput: func(t *testing.T, output OutputDiffEvaluationLogs) {
+				assert.True(t, strings.HasPrefix(output.Document, "# Evaluation changes from weekly-2026-02-01 to weekly-2026-02-08\n\n"+
+					"**1 newly failing**, 1 newly passing, 1 changed, 1 added, 1 removed and 4 unchanged requirement results.\n\n"+
+					"## Newly failing\n\n| Control | Requirement | Before | After | Message |\n|---|---|---|---|---|\n"+
+					"| CCC.C01 | CCC.C01.TR02 | Passed | Failed | port 80 accepts plain HTTP |\n"), output.Document)
+				assert.Contains(t, output.Document, "\n## Removed\n\n| Control | Requirement | Before | After | Message |\n|---|---|---|---|---|\n| CCC.C42 | CCC.C42.TR01 | Failed | - | failed |\n")
+			},
+		},
+		{
+			name:        "logs of different catalogs",
+			input:       InputDiffEvaluationLogs{FromContent: testEvaluationLog, ToContent: strings.Replace(later, "catalog-id: FINOS-CCC", "catalog-id: ACME-CCC", 1)},
+			wantErr:     true,
+			errContains: "the logs evaluate different catalogs: FINOS-CCC and ACME-CCC",
+		},
+		{
+			name:        "not an evaluation log",
+			input:       InputDiffEvaluationLogs{FromContent: testEvaluationLog, ToContent: "owner: security-team\n"},
+			wantErr:     true,
+			errContains: "to: failed to parse evaluation log",
+		},
+		{
+			name:        "missing earlier log",
+			input:       InputDiffEvaluationLogs{ToContent: later},
+			wantErr:     true,
+			errContains: "from_content is required",
+		},
+		{
+			name:        "unsupported format",
+			input:       InputDiffEvaluationLogs{FromContent: testEvaluationLog, ToContent: later, Format: "csv"},
+			wantErr:     true,
+			errContains: `unsupported format "csv"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := DiffEvaluationLogs(context.Background(), nil, tt.input)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			tt.validateOutput(t, output)
+		})
+	}
+}
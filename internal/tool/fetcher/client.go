@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds the configured maximum size.
+var ErrResponseTooLarge = errors.New("response body exceeds maximum size")
+
+// ClientOptions configures the shared HTTP client used by HTTP-based fetchers.
+type ClientOptions struct {
+	// Timeout bounds each request, including reading the response body.
+	Timeout time.Duration
+	// UserAgent is sent with every request when set.
+	UserAgent string
+	// CAFile is a PEM bundle of certificates trusted in addition to the system roots.
+	CAFile string
+	// ProxyURL overrides the proxy otherwise taken from HTTPS_PROXY, HTTP_PROXY and NO_PROXY.
+	ProxyURL string
+	// MaxBodySize limits the number of bytes read from a response body. Zero means no limit.
+	MaxBodySize int64
+}
+
+// Client is an HTTP client shared across fetchers so that connections are reused.
+type Client struct {
+	httpClient  *http.Client
+	transport   http.RoundTripper
+	maxBodySize int64
+}
+
+// NewClient creates a new shared HTTP client from the provided options.
+func NewClient(opts ClientOptions) (*Client, error) {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected default transport type %T", http.DefaultTransport)
+	}
+	transport := base.Clone()
+
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	} else {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	if opts.CAFile != "" {
+		pool, err := loadCertPool(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	var rt http.RoundTripper = transport
+	if opts.UserAgent != "" {
+		rt = &userAgentTransport{base: transport, userAgent: opts.UserAgent}
+	}
+
+	return &Client{
+		httpClient: &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: rt,
+		},
+		transport:   rt,
+		maxBodySize: opts.MaxBodySize,
+	}, nil
+}
+
+// Transport returns the round tripper used by the client so that other HTTP consumers
+// can share its proxy, TLS and User-Agent configuration.
+func (c *Client) Transport() http.RoundTripper {
+	return c.transport
+}
+
+// MaxBodySize returns the maximum number of bytes fetched for a single response or blob.
+// Zero means no limit.
+func (c *Client) MaxBodySize() int64 {
+	return c.maxBodySize
+}
+
+// Get performs a GET request and returns the response body.
+func (c *Client) Get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if c.maxBodySize <= 0 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return body, nil
+	}
+
+	if resp.ContentLength > c.maxBodySize {
+		return nil, fmt.Errorf("%w: %s declares %d bytes, limit is %d bytes", ErrResponseTooLarge, url, resp.ContentLength, c.maxBodySize)
+	}
+
+	// Read one byte past the limit so an oversized body can be told apart from one that fits exactly.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > c.maxBodySize {
+		return nil, fmt.Errorf("%w: %s exceeds limit of %d bytes", ErrResponseTooLarge, url, c.maxBodySize)
+	}
+
+	return body, nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", caFile)
+	}
+	return pool, nil
+}
+
+// userAgentTransport sets the User-Agent header on outgoing requests.
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(req)
+}
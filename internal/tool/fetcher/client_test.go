@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		opts        ClientOptions
+		wantErr     bool
+		errIs       error
+		errContains string
+		wantBody    string
+	}{
+		{
+			name: "sends configured user agent",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(r.Header.Get("User-Agent")))
+			},
+			opts:     ClientOptions{Timeout: time.Second, UserAgent: "gemara-mcp/test"},
+			wantBody: "gemara-mcp/test",
+		},
+		{
+			name: "body within limit is returned",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("12345"))
+			},
+			opts:     ClientOptions{Timeout: time.Second, MaxBodySize: 5},
+			wantBody: "12345",
+		},
+		{
+			name: "declared content length over limit is rejected",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("123456"))
+			},
+			opts:    ClientOptions{Timeout: time.Second, MaxBodySize: 5},
+			wantErr: true,
+			errIs:   ErrResponseTooLarge,
+		},
+		{
+			name: "streamed body over limit is rejected",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				flusher := w.(http.Flusher)
+				_, _ = w.Write([]byte("123"))
+				flusher.Flush()
+				_, _ = w.Write([]byte("456"))
+			},
+			opts:    ClientOptions{Timeout: time.Second, MaxBodySize: 5},
+			wantErr: true,
+			errIs:   ErrResponseTooLarge,
+		},
+		{
+			name: "non-200 status returns error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			opts:        ClientOptions{Timeout: time.Second},
+			wantErr:     true,
+			errContains: "unexpected status code: 404",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client, err := NewClient(tt.opts)
+			require.NoError(t, err, "should create client")
+
+			body, err := client.Get(context.Background(), server.URL)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				if tt.errIs != nil {
+					assert.ErrorIs(t, err, tt.errIs)
+				}
+				if tt.errContains != "" {
+					assert.Contains(t, err.Error(), tt.errContains)
+				}
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			assert.Equal(t, tt.wantBody, string(body))
+		})
+	}
+}
+
+func TestClientProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		_, _ = w.Write([]byte("via proxy"))
+	}))
+	defer proxy.Close()
+
+	client, err := NewClient(ClientOptions{Timeout: time.Second, ProxyURL: proxy.URL})
+	require.NoError(t, err)
+
+	body, err := client.Get(context.Background(), "http://gemara.invalid/lexicon.yaml")
+	require.NoError(t, err, "request should be routed through the proxy")
+	assert.Equal(t, "via proxy", string(body))
+	assert.Equal(t, "http://gemara.invalid/lexicon.yaml", proxied)
+}
+
+func TestClientCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("trusted"))
+	}))
+	defer server.Close()
+
+	// Without the custom CA the self-signed test certificate is rejected.
+	untrusted, err := NewClient(ClientOptions{Timeout: time.Second})
+	require.NoError(t, err)
+	_, err = untrusted.Get(context.Background(), server.URL)
+	require.Error(t, err, "should reject unknown certificate authority")
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, certPEM, 0o600))
+
+	trusted, err := NewClient(ClientOptions{Timeout: time.Second, CAFile: caFile})
+	require.NoError(t, err)
+	body, err := trusted.Get(context.Background(), server.URL)
+	require.NoError(t, err, "should trust certificate from CA file")
+	assert.Equal(t, "trusted", string(body))
+}
+
+func TestNewClientErrors(t *testing.T) {
+	emptyCA := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(emptyCA, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name        string
+		opts        ClientOptions
+		errContains string
+	}{
+		{
+			name:        "missing CA file",
+			opts:        ClientOptions{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+			errContains: "failed to read CA file",
+		},
+		{
+			name:        "CA file without certificates",
+			opts:        ClientOptions{CAFile: emptyCA},
+			errContains: "no certificates found",
+		},
+		{
+			name:        "invalid proxy URL",
+			opts:        ClientOptions{ProxyURL: "://bad"},
+			errContains: "invalid proxy URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(tt.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
@@ -5,9 +5,6 @@ package fetcher
 import (
 	"context"
 	"fmt"
-	"io"
-	"net/http"
-	"time"
 )
 
 // Fetcher is a generic interface for fetching raw data from a source.
@@ -18,51 +15,67 @@ type Fetcher interface {
 
 // HTTPFetcher fetches data from an HTTP URL.
 type HTTPFetcher struct {
-	url     string
-	timeout time.Duration
+	url    string
+	client *Client
 }
 
-// NewHTTPFetcher creates a new HTTP fetcher.
-func NewHTTPFetcher(url string, timeout time.Duration) *HTTPFetcher {
+// NewHTTPFetcher creates a new HTTP fetcher that uses the provided shared client.
+func NewHTTPFetcher(url string, client *Client) *HTTPFetcher {
 	return &HTTPFetcher{
-		url:     url,
-		timeout: timeout,
+		url:    url,
+		client: client,
 	}
 }
 
 func (f *HTTPFetcher) Fetch(ctx context.Context) ([]byte, string, error) {
-	client := &http.Client{
-		Timeout: f.timeout,
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	body, err := f.client.Get(ctx, f.url)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create request: %w", err)
+		return nil, "", err
 	}
+	return body, f.url, nil
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to fetch data: %w", err)
-	}
-	defer resp.Body.Close() //nolint:errcheck
+// VerifiedFetcher wraps a Fetcher, checking fetched data with verifiers before returning it.
+type VerifiedFetcher struct {
+	fetcher   Fetcher
+	verifiers []Verifier
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+// NewVerifiedFetcher creates a fetcher that returns the data of f only if it passes all verifiers.
+func NewVerifiedFetcher(f Fetcher, verifiers ...Verifier) *VerifiedFetcher {
+	return &VerifiedFetcher{
+		fetcher:   f,
+		verifiers: verifiers,
 	}
+}
 
-	body, err := io.ReadAll(resp.Body)
+func (v *VerifiedFetcher) Fetch(ctx context.Context) ([]byte, string, error) {
+	data, sourceID, err := v.fetcher.Fetch(ctx)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, "", err
+	}
+	if err := verify(ctx, sourceID, data, v.verifiers); err != nil {
+		return nil, "", err
 	}
+	return data, sourceID, nil
+}
 
-	return body, f.url, nil
+// verify checks data fetched from sourceID with each verifier in turn.
+func verify(ctx context.Context, sourceID string, data []byte, verifiers []Verifier) error {
+	for _, v := range verifiers {
+		if err := v.Verify(ctx, data); err != nil {
+			return fmt.Errorf("%s: %w", sourceID, err)
+		}
+	}
+	return nil
 }
 
 // CachedFetcher wraps a Fetcher with caching behavior.
 type CachedFetcher struct {
-	fetcher Fetcher
-	cache   *Cache
-	source  string
+	fetcher   Fetcher
+	cache     *Cache
+	source    string
+	verifiers []Verifier
 }
 
 // NewCachedFetcher creates a new cached fetcher that wraps the provided fetcher.
@@ -74,8 +87,20 @@ func NewCachedFetcher(f Fetcher, cache *Cache, source string) *CachedFetcher {
 	}
 }
 
+// WithVerifiers adds verifiers that fetched data must pass before it is cached.
+func (c *CachedFetcher) WithVerifiers(verifiers ...Verifier) *CachedFetcher {
+	c.verifiers = append(c.verifiers, verifiers...)
+	return c
+}
+
+// Cached returns the data in the cache without fetching it, reporting whether it was found.
+func (c *CachedFetcher) Cached() ([]byte, string, bool) {
+	return c.cache.Get(c.source)
+}
+
 // Fetch retrieves data, checking cache first and storing results in cache.
 // If refresh is true, bypasses cache and fetches fresh data.
+// Freshly fetched data must pass all verifiers before it is cached.
 func (c *CachedFetcher) Fetch(ctx context.Context, refresh bool) ([]byte, string, error) {
 	if !refresh {
 		if cachedData, cachedSource, found := c.cache.Get(c.source); found {
@@ -88,6 +113,10 @@ func (c *CachedFetcher) Fetch(ctx context.Context, refresh bool) ([]byte, string
 		return nil, "", err
 	}
 
+	if err := verify(ctx, sourceID, data, c.verifiers); err != nil {
+		return nil, "", err
+	}
+
 	c.cache.Set(c.source, data, sourceID)
 	return data, sourceID, nil
 }
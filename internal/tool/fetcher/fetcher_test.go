@@ -150,3 +150,62 @@ func TestCachedFetcher(t *testing.T) {
 		})
 	}
 }
+
+func TestCachedFetcherVerification(t *testing.T) {
+	ctx := context.Background()
+	data := []byte("lexicon data")
+
+	good, err := NewDigestVerifier(Digest(data))
+	require.NoError(t, err)
+	bad, err := NewDigestVerifier(Digest([]byte("other data")))
+	require.NoError(t, err)
+
+	t.Run("verified data is cached", func(t *testing.T) {
+		cache := NewCache(24 * time.Hour)
+		mock := &mockFetcher{data: data, source: "mock://test"}
+		cf := NewCachedFetcher(mock, cache, "test://source").WithVerifiers(good)
+
+		got, _, err := cf.Fetch(ctx, false)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+
+		_, _, found := cache.Get("test://source")
+		assert.True(t, found, "verified data should be cached")
+	})
+
+	t.Run("unverified data is not cached", func(t *testing.T) {
+		cache := NewCache(24 * time.Hour)
+		mock := &mockFetcher{data: data, source: "mock://test"}
+		cf := NewCachedFetcher(mock, cache, "test://source").WithVerifiers(good, bad)
+
+		_, _, err := cf.Fetch(ctx, false)
+		require.ErrorIs(t, err, ErrIntegrity)
+		assert.Contains(t, err.Error(), "mock://test", "error should name the source")
+
+		_, _, found := cache.Get("test://source")
+		assert.False(t, found, "data failing verification must not be cached")
+	})
+}
+
+func TestVerifiedFetcher(t *testing.T) {
+	ctx := context.Background()
+	data := []byte("catalog data")
+	mock := &mockFetcher{data: data, source: "mock://catalog"}
+
+	good, err := NewDigestVerifier(Digest(data))
+	require.NoError(t, err)
+	got, source, err := NewVerifiedFetcher(mock, good).Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+	assert.Equal(t, "mock://catalog", source)
+
+	bad, err := NewDigestVerifier(Digest([]byte("other data")))
+	require.NoError(t, err)
+	_, _, err = NewVerifiedFetcher(mock, good, bad).Fetch(ctx)
+	require.ErrorIs(t, err, ErrIntegrity)
+	assert.Contains(t, err.Error(), "mock://catalog", "error should name the source")
+
+	mock.err = errors.New("unreachable")
+	_, _, err = NewVerifiedFetcher(mock, good).Fetch(ctx)
+	assert.EqualError(t, err, "unreachable")
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const defaultGitRef = "HEAD"
+
+// GitFetcher reads a file at a pinned revision from a local git clone or bare repository.
+// It shells out to the git binary so that no network access is needed.
+type GitFetcher struct {
+	repo string
+	ref  string
+	path string
+	// maxSize limits the size of the file read. Zero means no limit.
+	maxSize int64
+}
+
+// NewGitFetcher creates a fetcher for path at ref in the repository at repo.
+// The ref may be a branch, tag or commit and defaults to HEAD when empty.
+func NewGitFetcher(repo, ref, path string) *GitFetcher {
+	if ref == "" {
+		ref = defaultGitRef
+	}
+	return &GitFetcher{
+		repo: repo,
+		ref:  ref,
+		path: strings.TrimPrefix(path, "/"),
+	}
+}
+
+// WithMaxBodySize limits the size of the file the fetcher reads, refusing larger ones with
+// ErrResponseTooLarge before they are read. Zero means no limit.
+func (f *GitFetcher) WithMaxBodySize(n int64) *GitFetcher {
+	f.maxSize = n
+	return f
+}
+
+// Fetch retrieves the file contents and returns the resolved commit SHA as the source identifier.
+func (f *GitFetcher) Fetch(ctx context.Context) ([]byte, string, error) {
+	if strings.HasPrefix(f.ref, "-") {
+		return nil, "", fmt.Errorf("invalid git ref %q", f.ref)
+	}
+	if f.path == "" {
+		return nil, "", fmt.Errorf("git source %s has no file path", f.repo)
+	}
+
+	out, err := f.git(ctx, "rev-parse", "--verify", "--end-of-options", f.ref+"^{commit}")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve %s in %s: %w", f.ref, f.repo, err)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	object := commit + ":" + f.path
+	if f.maxSize > 0 {
+		out, err := f.git(ctx, "cat-file", "-s", object)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s at %s in %s: %w", f.path, commit, f.repo, err)
+		}
+		size, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read size of %s at %s in %s: %w", f.path, commit, f.repo, err)
+		}
+		if size > f.maxSize {
+			return nil, "", fmt.Errorf("%w: %s at %s is %d bytes, limit is %d bytes", ErrResponseTooLarge, f.path, commit, size, f.maxSize)
+		}
+	}
+
+	data, err := f.git(ctx, "cat-file", "blob", object)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s at %s in %s: %w", f.path, commit, f.repo, err)
+	}
+
+	return data, commit, nil
+}
+
+func (f *GitFetcher) git(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", f.repo}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
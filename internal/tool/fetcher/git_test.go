@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gitRepo creates a repository with two commits of docs/lexicon.yaml, tagging the first as v1.
+// It returns the repository path and the SHAs of both commits.
+func gitRepo(t *testing.T) (string, string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, out)
+		return strings.TrimSpace(string(out))
+	}
+	write := func(content string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "lexicon.yaml"), []byte(content), 0o600))
+	}
+
+	run("init", "--quiet")
+	write("- term: Control\n")
+	run("add", ".")
+	run("commit", "--quiet", "-m", "first")
+	run("tag", "v1")
+	first := run("rev-parse", "HEAD")
+
+	write("- term: Control\n- term: Threat\n")
+	run("commit", "--quiet", "-am", "second")
+	second := run("rev-parse", "HEAD")
+
+	return dir, first, second
+}
+
+func TestGitFetcher(t *testing.T) {
+	repo, first, second := gitRepo(t)
+
+	bare := filepath.Join(t.TempDir(), "bare.git")
+	out, err := exec.Command("git", "clone", "--quiet", "--bare", repo, bare).CombinedOutput()
+	require.NoError(t, err, "%s", out)
+
+	tests := []struct {
+		name        string
+		repo        string
+		ref         string
+		path        string
+		wantData    string
+		wantSource  string
+		errContains string
+	}{
+		{
+			name:       "default ref reads HEAD",
+			repo:       repo,
+			path:       "docs/lexicon.yaml",
+			wantData:   "- term: Control\n- term: Threat\n",
+			wantSource: second,
+		},
+		{
+			name:       "tag pins earlier revision",
+			repo:       repo,
+			ref:        "v1",
+			path:       "docs/lexicon.yaml",
+			wantData:   "- term: Control\n",
+			wantSource: first,
+		},
+		{
+			name:       "abbreviated commit in bare repository",
+			repo:       bare,
+			ref:        first[:12],
+			path:       "/docs/lexicon.yaml",
+			wantData:   "- term: Control\n",
+			wantSource: first,
+		},
+		{
+			name:        "unknown ref",
+			repo:        repo,
+			ref:         "v9",
+			path:        "docs/lexicon.yaml",
+			errContains: "failed to resolve v9",
+		},
+		{
+			name:        "missing file",
+			repo:        repo,
+			path:        "docs/missing.yaml",
+			errContains: "failed to read docs/missing.yaml",
+		},
+		{
+			name:        "option-like ref is rejected",
+			repo:        repo,
+			ref:         "--output=/tmp/x",
+			path:        "docs/lexicon.yaml",
+			errContains: "invalid git ref",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, sourceID, err := NewGitFetcher(tt.repo, tt.ref, tt.path).Fetch(context.Background())
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantData, string(data))
+			assert.Equal(t, tt.wantSource, sourceID, "source should be the resolved commit SHA")
+		})
+	}
+}
+
+func TestGitFetcherMaxBodySize(t *testing.T) {
+	repo, _, second := gitRepo(t)
+	content := "- term: Control\n- term: Threat\n"
+
+	data, _, err := NewGitFetcher(repo, "", "docs/lexicon.yaml").WithMaxBodySize(int64(len(content))).Fetch(context.Background())
+	require.NoError(t, err, "files within the limit should be read")
+	assert.Equal(t, content, string(data))
+
+	_, _, err = NewGitFetcher(repo, "", "docs/lexicon.yaml").WithMaxBodySize(int64(len(content) - 1)).Fetch(context.Background())
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+	assert.Contains(t, err.Error(), "docs/lexicon.yaml at "+second)
+
+	client, err := NewClient(ClientOptions{Timeout: time.Second, MaxBodySize: 8})
+	require.NoError(t, err)
+	f, err := NewForURL("git+file://"+repo+"#docs/lexicon.yaml", client)
+	require.NoError(t, err)
+	_, _, err = f.Fetch(context.Background())
+	assert.ErrorIs(t, err, ErrResponseTooLarge, "git sources should be limited by the client's body size")
+}
+
+func TestNewForURLGit(t *testing.T) {
+	repo, first, second := gitRepo(t)
+	client, err := NewClient(ClientOptions{Timeout: time.Second})
+	require.NoError(t, err)
+
+	// A clone under a directory whose name contains @, with a branch whose name contains a slash.
+	clone := filepath.Join(t.TempDir(), "user@example.com", "gemara")
+	out, err := exec.Command("git", "clone", "--quiet", repo, clone).CombinedOutput()
+	require.NoError(t, err, "%s", out)
+	out, err = exec.Command("git", "-C", clone, "branch", "release/v1", first).CombinedOutput()
+	require.NoError(t, err, "%s", out)
+
+	tests := []struct {
+		name        string
+		url         string
+		wantData    string
+		wantSource  string
+		errContains string
+	}{
+		{
+			name:       "ref after @",
+			url:        "git+file://" + repo + "@v1#docs/lexicon.yaml",
+			wantData:   "- term: Control\n",
+			wantSource: first,
+		},
+		{
+			name:       "@ in the repository path is not a ref",
+			url:        "git+file://" + clone + "#docs/lexicon.yaml",
+			wantData:   "- term: Control\n- term: Threat\n",
+			wantSource: second,
+		},
+		{
+			name:       "ref after @ in a repository path with @",
+			url:        "git+file://" + clone + "@v1#docs/lexicon.yaml",
+			wantData:   "- term: Control\n",
+			wantSource: first,
+		},
+		{
+			name:       "ref with a slash after ?ref=",
+			url:        "git+file://" + clone + "?ref=release/v1#docs/lexicon.yaml",
+			wantData:   "- term: Control\n",
+			wantSource: first,
+		},
+		{
+			name:        "ref with a slash after @",
+			url:         "git+file://" + repo + "@release/v1#docs/lexicon.yaml",
+			errContains: "failed to resolve HEAD in " + repo + "@release/v1",
+		},
+		{
+			name:        "missing file path",
+			url:         "git+file://" + repo + "@v1",
+			errContains: "must name a repository and a file path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewForURL(tt.url, client)
+			if err == nil {
+				var data []byte
+				var sourceID string
+				data, sourceID, err = f.Fetch(context.Background())
+				if err == nil {
+					assert.Equal(t, tt.wantData, string(data))
+					assert.Equal(t, tt.wantSource, sourceID)
+				}
+			}
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
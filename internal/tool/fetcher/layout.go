@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"cuelabs.dev/go/oci/ociregistry"
+	"cuelabs.dev/go/oci/ociregistry/ocimem"
+	"cuelabs.dev/go/oci/ociregistry/ociref"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// NewOCILayoutFetcher creates a fetcher for an artifact stored in a local OCI image layout
+// directory. The reference is a tag recorded in the layout's index or a manifest digest.
+func NewOCILayoutFetcher(dir, reference, layer string) *OCIFetcher {
+	ref := ociref.Reference{Repository: dir}
+	if d, err := digest.Parse(reference); err == nil {
+		ref.Digest = d
+	} else {
+		ref.Tag = reference
+	}
+	return &OCIFetcher{
+		registry: NewOCILayout(dir),
+		ref:      ref,
+		layer:    layer,
+		scheme:   "oci-layout",
+	}
+}
+
+// NewOCILayout returns a read-only registry backed by an OCI image layout directory.
+// The layout holds a single repository, so repository names passed to it are ignored.
+func NewOCILayout(dir string) ociregistry.Interface {
+	l := &ociLayout{dir: dir}
+	return &ociregistry.Funcs{
+		GetBlob_:     l.getBlob,
+		GetManifest_: l.getBlob,
+		GetTag_:      l.getTag,
+		ResolveTag_:  l.resolveTag,
+	}
+}
+
+type ociLayout struct {
+	dir string
+}
+
+func (l *ociLayout) getBlob(_ context.Context, _ string, d ociregistry.Digest) (ociregistry.BlobReader, error) {
+	if err := d.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid digest %q: %w", d, err)
+	}
+	data, err := os.ReadFile(filepath.Join(l.dir, "blobs", d.Algorithm().String(), d.Encoded()))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s: %w", d, ociregistry.ErrBlobUnknown)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s: %w", d, err)
+	}
+	return ocimem.NewBytesReader(data, ociregistry.Descriptor{
+		Digest: d,
+		Size:   int64(len(data)),
+	}), nil
+}
+
+func (l *ociLayout) getTag(ctx context.Context, repo string, tag string) (ociregistry.BlobReader, error) {
+	desc, err := l.resolveTag(ctx, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	return l.getBlob(ctx, repo, desc.Digest)
+}
+
+func (l *ociLayout) resolveTag(_ context.Context, _ string, tag string) (ociregistry.Descriptor, error) {
+	data, err := os.ReadFile(filepath.Join(l.dir, "index.json"))
+	if err != nil {
+		return ociregistry.Descriptor{}, fmt.Errorf("failed to read OCI layout index: %w", err)
+	}
+
+	var index ocispec.Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return ociregistry.Descriptor{}, fmt.Errorf("failed to parse OCI layout index: %w", err)
+	}
+
+	for _, desc := range index.Manifests {
+		if desc.Annotations[ocispec.AnnotationRefName] == tag {
+			return desc, nil
+		}
+	}
+	return ociregistry.Descriptor{}, fmt.Errorf("tag %q: %w", tag, ociregistry.ErrManifestUnknown)
+}
@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"cuelabs.dev/go/oci/ociregistry"
+	"cuelabs.dev/go/oci/ociregistry/ociauth"
+	"cuelabs.dev/go/oci/ociregistry/ociclient"
+	"cuelabs.dev/go/oci/ociregistry/ociref"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// OCIFetcher fetches a single layer of an OCI artifact from a registry.
+type OCIFetcher struct {
+	registry ociregistry.Interface
+	ref      ociref.Reference
+	layer    string
+	scheme   string
+	// maxSize limits the size of the manifest and the layer read. Zero means no limit.
+	maxSize int64
+}
+
+// NewOCIFetcher creates a fetcher for the artifact referenced by tag or digest in ref.
+// The layer is selected by its title annotation or media type; when layer is empty the
+// artifact must contain exactly one layer.
+func NewOCIFetcher(registry ociregistry.Interface, ref ociref.Reference, layer string) *OCIFetcher {
+	return &OCIFetcher{
+		registry: registry,
+		ref:      ref,
+		layer:    layer,
+		scheme:   "oci",
+	}
+}
+
+// WithMaxBodySize limits the size of the manifest and layer the fetcher reads, refusing
+// larger ones with ErrResponseTooLarge. Zero means no limit.
+func (f *OCIFetcher) WithMaxBodySize(n int64) *OCIFetcher {
+	f.maxSize = n
+	return f
+}
+
+// NewOCIRegistry creates a client for a remote OCI registry that authenticates using the
+// local Docker configuration and shares the transport of the provided client.
+func NewOCIRegistry(host string, client *Client) (ociregistry.Interface, error) {
+	config, err := ociauth.Load(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry credentials: %w", err)
+	}
+	return ociclient.New(host, &ociclient.Options{
+		Transport: ociauth.NewStdTransport(ociauth.StdTransportParams{
+			Config:    config,
+			Transport: client.Transport(),
+		}),
+		Insecure: isLocalHost(host),
+	})
+}
+
+// Fetch retrieves the selected layer and returns it with the artifact's manifest digest
+// as the source identifier.
+func (f *OCIFetcher) Fetch(ctx context.Context) ([]byte, string, error) {
+	manifestData, err := f.manifest(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.MediaType == ocispec.MediaTypeImageIndex {
+		return nil, "", fmt.Errorf("%s is an image index; reference a single artifact manifest instead", f.ref)
+	}
+
+	desc, err := f.selectLayer(manifest.Layers)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := readBlob(ctx, f.registry, f.ref.Repository, desc, f.maxSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sourceRef := ociref.Reference{
+		Host:       f.ref.Host,
+		Repository: f.ref.Repository,
+		Digest:     digest.FromBytes(manifestData),
+	}
+	return data, f.scheme + "://" + sourceRef.String(), nil
+}
+
+func (f *OCIFetcher) manifest(ctx context.Context) ([]byte, error) {
+	var (
+		r   ociregistry.BlobReader
+		err error
+	)
+	switch {
+	case f.ref.Digest != "":
+		r, err = f.registry.GetManifest(ctx, f.ref.Repository, f.ref.Digest)
+	case f.ref.Tag != "":
+		r, err = f.registry.GetTag(ctx, f.ref.Repository, f.ref.Tag)
+	default:
+		return nil, fmt.Errorf("reference %s has neither a tag nor a digest", f.ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest for %s: %w", f.ref, err)
+	}
+	defer r.Close() //nolint:errcheck
+
+	data, err := readLimited(r, f.maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest for %s: %w", f.ref, err)
+	}
+	if f.ref.Digest != "" && digest.FromBytes(data) != f.ref.Digest {
+		return nil, fmt.Errorf("%w: manifest for %s does not match its digest", ErrIntegrity, f.ref)
+	}
+	return data, nil
+}
+
+func (f *OCIFetcher) selectLayer(layers []ocispec.Descriptor) (ocispec.Descriptor, error) {
+	if f.layer == "" {
+		if len(layers) != 1 {
+			return ocispec.Descriptor{}, fmt.Errorf("%s has %d layers; select one of %s", f.ref, len(layers), layerNames(layers))
+		}
+		return layers[0], nil
+	}
+
+	for _, desc := range layers {
+		if desc.Annotations[ocispec.AnnotationTitle] == f.layer || desc.MediaType == f.layer {
+			return desc, nil
+		}
+	}
+	return ocispec.Descriptor{}, fmt.Errorf("layer %q not found in %s; available layers: %s", f.layer, f.ref, layerNames(layers))
+}
+
+// readBlob reads a blob and checks it against its descriptor. Blobs whose descriptor
+// declares more than maxSize bytes are refused before they are fetched.
+func readBlob(ctx context.Context, registry ociregistry.Interface, repo string, desc ocispec.Descriptor, maxSize int64) ([]byte, error) {
+	if maxSize > 0 && desc.Size > maxSize {
+		return nil, fmt.Errorf("%w: blob %s declares %d bytes, limit is %d bytes", ErrResponseTooLarge, desc.Digest, desc.Size, maxSize)
+	}
+	r, err := registry.GetBlob(ctx, repo, desc.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blob %s: %w", desc.Digest, err)
+	}
+	defer r.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(io.LimitReader(r, desc.Size+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s: %w", desc.Digest, err)
+	}
+	if int64(len(data)) != desc.Size || digest.FromBytes(data) != desc.Digest {
+		return nil, fmt.Errorf("%w: blob %s does not match its descriptor", ErrIntegrity, desc.Digest)
+	}
+	return data, nil
+}
+
+// readLimited reads r up to maxSize bytes, failing with ErrResponseTooLarge if it holds more.
+// Zero means no limit.
+func readLimited(r io.Reader, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		return io.ReadAll(r)
+	}
+	// Read one byte past the limit so an oversized body can be told apart from one that fits exactly.
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, maxSize)
+	}
+	return data, nil
+}
+
+func layerNames(layers []ocispec.Descriptor) string {
+	names := make([]string, 0, len(layers))
+	for _, desc := range layers {
+		if title := desc.Annotations[ocispec.AnnotationTitle]; title != "" {
+			names = append(names, title)
+		} else {
+			names = append(names, desc.MediaType)
+		}
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+// isLocalHost reports whether host refers to the local machine, where registries
+// are commonly served over plain HTTP.
+func isLocalHost(host string) bool {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	if hostname == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(hostname)
+	return ip != nil && ip.IsLoopback()
+}
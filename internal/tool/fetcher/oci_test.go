@@ -0,0 +1,320 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cuelabs.dev/go/oci/ociregistry"
+	"cuelabs.dev/go/oci/ociregistry/ocimem"
+	"cuelabs.dev/go/oci/ociregistry/ociref"
+	"cuelabs.dev/go/oci/ociregistry/ociserver"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gemaraLayerMediaType = "application/vnd.gemara.artifact.v1+yaml"
+
+// testLayer is a file stored as a layer of a test artifact.
+type testLayer struct {
+	title   string
+	content string
+}
+
+// artifactManifest builds an artifact manifest and the blobs it references.
+func artifactManifest(t *testing.T, layers ...testLayer) ([]byte, map[digest.Digest][]byte) {
+	t.Helper()
+	blobs := make(map[digest.Digest][]byte)
+
+	config := []byte("{}")
+	blobs[digest.FromBytes(config)] = config
+
+	manifest := ocispec.Manifest{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: "application/vnd.gemara.catalog.v1",
+		Config: ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeEmptyJSON,
+			Digest:    digest.FromBytes(config),
+			Size:      int64(len(config)),
+		},
+	}
+	manifest.SchemaVersion = 2
+	for _, l := range layers {
+		data := []byte(l.content)
+		blobs[digest.FromBytes(data)] = data
+		manifest.Layers = append(manifest.Layers, ocispec.Descriptor{
+			MediaType:   gemaraLayerMediaType,
+			Digest:      digest.FromBytes(data),
+			Size:        int64(len(data)),
+			Annotations: map[string]string{ocispec.AnnotationTitle: l.title},
+		})
+	}
+
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	return data, blobs
+}
+
+// writeLayout writes an OCI image layout containing one artifact tagged with tag.
+func writeLayout(t *testing.T, tag string, layers ...testLayer) (string, digest.Digest) {
+	t.Helper()
+	dir := t.TempDir()
+	manifest, blobs := artifactManifest(t, layers...)
+	blobs[digest.FromBytes(manifest)] = manifest
+
+	for d, data := range blobs {
+		path := filepath.Join(dir, "blobs", d.Algorithm().String(), d.Encoded())
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+	}
+
+	index := ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{{
+			MediaType:   ocispec.MediaTypeImageManifest,
+			Digest:      digest.FromBytes(manifest),
+			Size:        int64(len(manifest)),
+			Annotations: map[string]string{ocispec.AnnotationRefName: tag},
+		}},
+	}
+	index.SchemaVersion = 2
+	indexData, err := json.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.json"), indexData, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0o600))
+
+	return dir, digest.FromBytes(manifest)
+}
+
+// pushArtifact stores an artifact in an in-memory registry under repo:tag.
+func pushArtifact(t *testing.T, registry ociregistry.Interface, repo, tag string, layers ...testLayer) digest.Digest {
+	t.Helper()
+	ctx := context.Background()
+	manifest, blobs := artifactManifest(t, layers...)
+	for d, data := range blobs {
+		_, err := registry.PushBlob(ctx, repo, ociregistry.Descriptor{MediaType: "application/octet-stream", Digest: d, Size: int64(len(data))}, bytes.NewReader(data))
+		require.NoError(t, err)
+	}
+	_, err := registry.PushManifest(ctx, repo, tag, manifest, ocispec.MediaTypeImageManifest)
+	require.NoError(t, err)
+	return digest.FromBytes(manifest)
+}
+
+func TestOCILayoutFetcher(t *testing.T) {
+	dir, manifestDigest := writeLayout(t, "v1",
+		testLayer{title: "lexicon.yaml", content: "- term: Control\n"},
+		testLayer{title: "ccc.yaml", content: "title: FINOS Cloud Control Catalog\n"},
+	)
+
+	tests := []struct {
+		name        string
+		reference   string
+		layer       string
+		wantData    string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:      "tag and layer title",
+			reference: "v1",
+			layer:     "lexicon.yaml",
+			wantData:  "- term: Control\n",
+		},
+		{
+			name:      "digest and layer title",
+			reference: manifestDigest.String(),
+			layer:     "ccc.yaml",
+			wantData:  "title: FINOS Cloud Control Catalog\n",
+		},
+		{
+			name:        "multiple layers require selection",
+			reference:   "v1",
+			wantErr:     true,
+			errContains: "has 2 layers",
+		},
+		{
+			name:        "unknown layer",
+			reference:   "v1",
+			layer:       "missing.yaml",
+			wantErr:     true,
+			errContains: `layer "missing.yaml" not found`,
+		},
+		{
+			name:        "unknown tag",
+			reference:   "v2",
+			layer:       "ccc.yaml",
+			wantErr:     true,
+			errContains: "failed to fetch manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewOCILayoutFetcher(dir, tt.reference, tt.layer)
+			data, sourceID, err := f.Fetch(context.Background())
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantData, string(data))
+			assert.Equal(t, "oci-layout://"+dir+"@"+manifestDigest.String(), sourceID, "source should be pinned to manifest digest")
+		})
+	}
+}
+
+func TestOCILayoutFetcherDetectsTampering(t *testing.T) {
+	dir, _ := writeLayout(t, "v1", testLayer{title: "ccc.yaml", content: "title: original\n"})
+
+	blob := digest.FromString("title: original\n")
+	path := filepath.Join(dir, "blobs", blob.Algorithm().String(), blob.Encoded())
+	require.NoError(t, os.WriteFile(path, []byte("title: tampered\n"), 0o600))
+
+	_, _, err := NewOCILayoutFetcher(dir, "v1", "").Fetch(context.Background())
+	assert.ErrorIs(t, err, ErrIntegrity)
+}
+
+func TestOCIFetcher(t *testing.T) {
+	registry := ocimem.New()
+	manifestDigest := pushArtifact(t, registry, "gemara/lexicon", "v1",
+		testLayer{title: "lexicon.yaml", content: "- term: Control\n"},
+	)
+
+	server := httptest.NewServer(ociserver.New(registry, nil))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	client, err := NewClient(ClientOptions{Timeout: time.Second})
+	require.NoError(t, err)
+
+	f, err := NewForURL("oci://"+host+"/gemara/lexicon:v1", client)
+	require.NoError(t, err)
+
+	data, sourceID, err := f.Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "- term: Control\n", string(data))
+	assert.Equal(t, "oci://"+host+"/gemara/lexicon@"+manifestDigest.String(), sourceID)
+}
+
+func TestOCIFetcherMaxBodySize(t *testing.T) {
+	registry := ocimem.New()
+	pushArtifact(t, registry, "gemara/lexicon", "v1",
+		testLayer{title: "lexicon.yaml", content: strings.Repeat("- term: Control\n", 100)},
+	)
+	ref := ociref.Reference{Repository: "gemara/lexicon", Tag: "v1"}
+	// Oversized blobs must be refused from their descriptor, without being fetched.
+	guarded := &ociregistry.Funcs{
+		GetTag_: registry.GetTag,
+		GetBlob_: func(context.Context, string, ociregistry.Digest) (ociregistry.BlobReader, error) {
+			t.Error("oversized blob should not be fetched")
+			return nil, ociregistry.ErrBlobUnknown
+		},
+	}
+
+	_, _, err := NewOCIFetcher(guarded, ref, "").WithMaxBodySize(1000).Fetch(context.Background())
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+	assert.Contains(t, err.Error(), "declares 1600 bytes, limit is 1000 bytes")
+
+	_, _, err = NewOCIFetcher(registry, ref, "").WithMaxBodySize(100).Fetch(context.Background())
+	require.ErrorIs(t, err, ErrResponseTooLarge, "oversized manifests should be refused")
+
+	data, _, err := NewOCIFetcher(registry, ref, "").WithMaxBodySize(1600).Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, data, 1600)
+}
+
+func TestNewForURL(t *testing.T) {
+	client, err := NewClient(ClientOptions{Timeout: time.Second})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		url         string
+		wantType    Fetcher
+		errContains string
+	}{
+		{
+			name:     "https",
+			url:      "https://example.com/lexicon.yaml",
+			wantType: &HTTPFetcher{},
+		},
+		{
+			name:     "oci with tag and layer",
+			url:      "oci://registry.example.com/gemara/catalogs:v1#ccc.yaml",
+			wantType: &OCIFetcher{},
+		},
+		{
+			name:     "oci layout",
+			url:      "oci-layout://./layout:v1",
+			wantType: &OCIFetcher{},
+		},
+		{
+			name:        "oci without tag or digest",
+			url:         "oci://registry.example.com/gemara/catalogs",
+			errContains: "must include a tag or digest",
+		},
+		{
+			name:        "unsupported scheme",
+			url:         "ftp://example.com/lexicon.yaml",
+			errContains: "unsupported source scheme",
+		},
+		{
+			name:        "missing scheme",
+			url:         "lexicon.yaml",
+			errContains: "has no scheme",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewForURL(tt.url, client)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tt.wantType, f)
+		})
+	}
+
+	_, err = NewForURL("oci-layout://./layout:v1", nil)
+	assert.ErrorContains(t, err, "needs a client", "sources should not be fetched without a client and its size limit")
+}
+
+func TestSplitLayoutReference(t *testing.T) {
+	tests := []struct {
+		location string
+		wantDir  string
+		wantRef  string
+	}{
+		{location: "/srv/layout:v1", wantDir: "/srv/layout", wantRef: "v1"},
+		{location: "./layout", wantDir: "./layout", wantRef: "latest"},
+		{location: "/srv/layout@sha256:abc", wantDir: "/srv/layout", wantRef: "sha256:abc"},
+		{location: "host:dir/layout", wantDir: "host:dir/layout", wantRef: "latest"},
+		{location: "/home/me@work/layout", wantDir: "/home/me@work/layout", wantRef: "latest"},
+		{location: "/home/me@work/layout:v1", wantDir: "/home/me@work/layout", wantRef: "v1"},
+		{location: "/home/me@work/layout@sha256:abc", wantDir: "/home/me@work/layout", wantRef: "sha256:abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			dir, ref := splitLayoutReference(tt.location)
+			assert.Equal(t, tt.wantDir, dir)
+			assert.Equal(t, tt.wantRef, ref)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"fmt"
+	"strings"
+
+	"cuelabs.dev/go/oci/ociregistry/ociref"
+)
+
+const defaultLayoutTag = "latest"
+
+// NewForURL creates a fetcher for a source URL that uses client and its response size
+// limit. Supported forms are:
+//
+//	http://... and https://...
+//	oci://HOST/REPOSITORY:TAG[#LAYER] or oci://HOST/REPOSITORY@DIGEST[#LAYER]
+//	oci-layout://DIR[:TAG][#LAYER] or oci-layout://DIR@DIGEST[#LAYER]
+//	git+file://REPO[@REF]#PATH or git+file://REPO?ref=REF#PATH
+//
+// LAYER selects an artifact layer by title annotation or media type. REF is a branch,
+// tag or commit of a local clone or bare repository and defaults to HEAD. After @ it
+// cannot contain a slash, so that REPO may contain @; refs such as release/v1 are given
+// with ?ref=.
+func NewForURL(rawURL string, client *Client) (Fetcher, error) {
+	scheme, rest, found := strings.Cut(rawURL, "://")
+	if !found {
+		return nil, fmt.Errorf("source %q has no scheme", rawURL)
+	}
+	if client == nil {
+		return nil, fmt.Errorf("source %q needs a client to fetch it", rawURL)
+	}
+
+	switch scheme {
+	case "http", "https":
+		return NewHTTPFetcher(rawURL, client), nil
+	case "oci":
+		location, layer, _ := strings.Cut(rest, "#")
+		ref, err := ociref.Parse(location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OCI reference %q: %w", location, err)
+		}
+		if ref.Tag == "" && ref.Digest == "" {
+			return nil, fmt.Errorf("OCI reference %q must include a tag or digest", location)
+		}
+		registry, err := NewOCIRegistry(ref.Host, client)
+		if err != nil {
+			return nil, err
+		}
+		return NewOCIFetcher(registry, ref, layer).WithMaxBodySize(client.MaxBodySize()), nil
+	case "oci-layout":
+		location, layer, _ := strings.Cut(rest, "#")
+		dir, reference := splitLayoutReference(location)
+		if dir == "" {
+			return nil, fmt.Errorf("OCI layout source %q has no directory", rawURL)
+		}
+		return NewOCILayoutFetcher(dir, reference, layer).WithMaxBodySize(client.MaxBodySize()), nil
+	case "git+file":
+		location, path, _ := strings.Cut(rest, "#")
+		repo, ref := splitGitReference(location)
+		if repo == "" || path == "" {
+			return nil, fmt.Errorf("git source %q must name a repository and a file path", rawURL)
+		}
+		return NewGitFetcher(repo, ref, path).WithMaxBodySize(client.MaxBodySize()), nil
+	default:
+		return nil, fmt.Errorf("unsupported source scheme %q", scheme)
+	}
+}
+
+// splitGitReference splits a git repository location into the repository and the ref
+// given after ?ref=, or after an @ that follows the last slash.
+func splitGitReference(location string) (string, string) {
+	if repo, ref, found := strings.Cut(location, "?ref="); found {
+		return repo, ref
+	}
+	if i := strings.LastIndex(location, "@"); i > strings.LastIndex(location, "/") {
+		return location[:i], location[i+1:]
+	}
+	return location, ""
+}
+
+// splitLayoutReference splits an OCI layout location into its directory and the digest
+// after an @ or the tag after a colon that follows the last slash.
+func splitLayoutReference(location string) (string, string) {
+	lastSlash := strings.LastIndex(location, "/")
+	if i := strings.LastIndex(location, "@"); i > lastSlash {
+		return location[:i], location[i+1:]
+	}
+	if i := strings.LastIndex(location, ":"); i > lastSlash {
+		return location[:i], location[i+1:]
+	}
+	return location, defaultLayoutTag
+}
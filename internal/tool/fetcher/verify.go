@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const digestPrefix = "sha256:"
+
+// ErrIntegrity is returned when fetched data does not match its declared digest or signature.
+var ErrIntegrity = errors.New("integrity verification failed")
+
+// Verifier checks fetched data before it is cached.
+type Verifier interface {
+	// Verify returns an error wrapping ErrIntegrity if data is not what the source declares.
+	Verify(ctx context.Context, data []byte) error
+}
+
+// Digest returns the SHA-256 digest of data in "sha256:<hex>" form.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return digestPrefix + hex.EncodeToString(sum[:])
+}
+
+// DigestVerifier verifies data against an expected SHA-256 digest.
+type DigestVerifier struct {
+	expected string
+}
+
+// NewDigestVerifier creates a verifier for the given SHA-256 digest, either as bare hex
+// or in "sha256:<hex>" form.
+func NewDigestVerifier(digest string) (*DigestVerifier, error) {
+	expected, err := normalizeDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+	return &DigestVerifier{expected: expected}, nil
+}
+
+func (v *DigestVerifier) Verify(_ context.Context, data []byte) error {
+	if actual := Digest(data); actual != v.expected {
+		return fmt.Errorf("%w: expected %s, got %s", ErrIntegrity, v.expected, actual)
+	}
+	return nil
+}
+
+// ChecksumFileVerifier verifies data against an entry in a checksum file in the format
+// written by sha256sum. When a public key is configured, the checksum file itself must
+// carry a valid Ed25519 signature.
+type ChecksumFileVerifier struct {
+	checksums Fetcher
+	signature Fetcher
+	publicKey ed25519.PublicKey
+	name      string
+}
+
+// NewChecksumFileVerifier creates a verifier that looks up name in the checksum file
+// retrieved by checksums.
+func NewChecksumFileVerifier(checksums Fetcher, name string) *ChecksumFileVerifier {
+	return &ChecksumFileVerifier{
+		checksums: checksums,
+		name:      name,
+	}
+}
+
+// WithSignature requires the checksum file to be signed by publicKey, with the
+// signature retrieved by signature. The signature may be raw or base64 encoded.
+func (v *ChecksumFileVerifier) WithSignature(signature Fetcher, publicKey ed25519.PublicKey) *ChecksumFileVerifier {
+	v.signature = signature
+	v.publicKey = publicKey
+	return v
+}
+
+func (v *ChecksumFileVerifier) Verify(ctx context.Context, data []byte) error {
+	checksums, _, err := v.checksums.Fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch checksum file: %w", err)
+	}
+
+	if v.signature != nil {
+		sig, _, err := v.signature.Fetch(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch checksum signature: %w", err)
+		}
+		sig, err = decodeSignature(sig)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrIntegrity, err)
+		}
+		if !ed25519.Verify(v.publicKey, checksums, sig) {
+			return fmt.Errorf("%w: checksum file signature does not match public key", ErrIntegrity)
+		}
+	}
+
+	expected, err := lookupChecksum(checksums, v.name)
+	if err != nil {
+		return err
+	}
+	if actual := Digest(data); actual != expected {
+		return fmt.Errorf("%w: checksum file declares %s for %s, got %s", ErrIntegrity, expected, v.name, actual)
+	}
+	return nil
+}
+
+// ParsePublicKey parses a PEM-encoded PKIX Ed25519 public key.
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T: only Ed25519 is supported", key)
+	}
+	return edKey, nil
+}
+
+func normalizeDigest(digest string) (string, error) {
+	hexDigest := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(digest), digestPrefix))
+	decoded, err := hex.DecodeString(hexDigest)
+	if err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 digest %q", digest)
+	}
+	return digestPrefix + hexDigest, nil
+}
+
+func lookupChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// sha256sum marks binary-mode entries with a leading '*'.
+		if strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		return normalizeDigest(fields[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read checksum file: %w", err)
+	}
+	return "", fmt.Errorf("%w: no checksum for %s in checksum file", ErrIntegrity, name)
+}
+
+func decodeSignature(sig []byte) ([]byte, error) {
+	if len(sig) == ed25519.SignatureSize {
+		return sig, nil
+	}
+	// A raw signature may end in whitespace bytes itself, so only the line ending that editors
+	// and shell redirection append is trimmed.
+	if raw := bytes.TrimSuffix(bytes.TrimSuffix(sig, []byte("\n")), []byte("\r")); len(raw) == ed25519.SignatureSize {
+		return raw, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || len(decoded) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("malformed checksum signature")
+	}
+	return decoded, nil
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lexiconData = "- term: Control\n  definition: Safeguard or countermeasure\n"
+
+func TestDigest(t *testing.T) {
+	assert.Equal(t, "sha256:ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", Digest([]byte("abc")))
+}
+
+func TestDigestVerifier(t *testing.T) {
+	digest := Digest([]byte(lexiconData))
+
+	tests := []struct {
+		name       string
+		digest     string
+		data       string
+		wantNewErr bool
+		wantErr    bool
+	}{
+		{
+			name:   "matching prefixed digest",
+			digest: digest,
+			data:   lexiconData,
+		},
+		{
+			name:   "matching bare uppercase digest",
+			digest: strings.ToUpper(strings.TrimPrefix(digest, "sha256:")),
+			data:   lexiconData,
+		},
+		{
+			name:    "mismatched data",
+			digest:  digest,
+			data:    lexiconData + "tampered",
+			wantErr: true,
+		},
+		{
+			name:       "malformed digest",
+			digest:     "sha256:abc",
+			wantNewErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewDigestVerifier(tt.digest)
+			if tt.wantNewErr {
+				assert.Error(t, err, "should reject malformed digest")
+				return
+			}
+			require.NoError(t, err)
+
+			err = v.Verify(context.Background(), []byte(tt.data))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrIntegrity)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestChecksumFileVerifier(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	hexDigest := strings.TrimPrefix(Digest([]byte(lexiconData)), "sha256:")
+	checksums := []byte(strings.Repeat("0", 64) + "  other.yaml\n" + hexDigest + " *lexicon.yaml\n")
+	signature := ed25519.Sign(privateKey, checksums)
+
+	tests := []struct {
+		name      string
+		verifier  func() *ChecksumFileVerifier
+		data      string
+		wantErr   bool
+		errIs     error
+		errSubstr string
+	}{
+		{
+			name: "unsigned checksum file with matching entry",
+			verifier: func() *ChecksumFileVerifier {
+				return NewChecksumFileVerifier(&mockFetcher{data: checksums}, "lexicon.yaml")
+			},
+			data: lexiconData,
+		},
+		{
+			name: "raw signature from trusted key",
+			verifier: func() *ChecksumFileVerifier {
+				return NewChecksumFileVerifier(&mockFetcher{data: checksums}, "lexicon.yaml").
+					WithSignature(&mockFetcher{data: signature}, publicKey)
+			},
+			data: lexiconData,
+		},
+		{
+			name: "raw signature with trailing newline",
+			verifier: func() *ChecksumFileVerifier {
+				return NewChecksumFileVerifier(&mockFetcher{data: checksums}, "lexicon.yaml").
+					WithSignature(&mockFetcher{data: append(slices.Clone(signature), '\n')}, publicKey)
+			},
+			data: lexiconData,
+		},
+		{
+			name: "base64 signature from trusted key",
+			verifier: func() *ChecksumFileVerifier {
+				encoded := []byte(base64.StdEncoding.EncodeToString(signature) + "\n")
+				return NewChecksumFileVerifier(&mockFetcher{data: checksums}, "lexicon.yaml").
+					WithSignature(&mockFetcher{data: encoded}, publicKey)
+			},
+			data: lexiconData,
+		},
+		{
+			name: "signature from untrusted key",
+			verifier: func() *ChecksumFileVerifier {
+				return NewChecksumFileVerifier(&mockFetcher{data: checksums}, "lexicon.yaml").
+					WithSignature(&mockFetcher{data: signature}, otherPublicKey)
+			},
+			data:      lexiconData,
+			wantErr:   true,
+			errIs:     ErrIntegrity,
+			errSubstr: "signature",
+		},
+		{
+			name: "tampered data",
+			verifier: func() *ChecksumFileVerifier {
+				return NewChecksumFileVerifier(&mockFetcher{data: checksums}, "lexicon.yaml")
+			},
+			data:    lexiconData + "tampered",
+			wantErr: true,
+			errIs:   ErrIntegrity,
+		},
+		{
+			name: "missing entry",
+			verifier: func() *ChecksumFileVerifier {
+				return NewChecksumFileVerifier(&mockFetcher{data: checksums}, "missing.yaml")
+			},
+			data:      lexiconData,
+			wantErr:   true,
+			errIs:     ErrIntegrity,
+			errSubstr: "no checksum for missing.yaml",
+		},
+		{
+			name: "checksum file fetch error",
+			verifier: func() *ChecksumFileVerifier {
+				return NewChecksumFileVerifier(&mockFetcher{err: assert.AnError}, "lexicon.yaml")
+			},
+			data:      lexiconData,
+			wantErr:   true,
+			errSubstr: "failed to fetch checksum file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.verifier().Verify(context.Background(), []byte(tt.data))
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			if tt.errIs != nil {
+				assert.ErrorIs(t, err, tt.errIs)
+			}
+			if tt.errSubstr != "" {
+				assert.Contains(t, err.Error(), tt.errSubstr)
+			}
+		})
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+
+	parsed, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, parsed)
+
+	_, err = ParsePublicKey([]byte("not pem"))
+	assert.Error(t, err, "should reject non-PEM input")
+}
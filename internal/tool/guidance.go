@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+// GuidanceDocument captures high-level guidance such as standards, regulations and best
+// practice frameworks.
+type GuidanceDocument struct {
+	Title        string      `json:"title" yaml:"title"`
+	Metadata     Metadata    `json:"metadata" yaml:"metadata"`
+	DocumentType string      `json:"document-type" yaml:"document-type"`
+	Families     []Family    `json:"families,omitempty" yaml:"families,omitempty"`
+	Guidelines   []Guideline `json:"guidelines,omitempty" yaml:"guidelines,omitempty"`
+}
+
+// Guideline is a single recommendation within a guidance document.
+type Guideline struct {
+	ID                string         `json:"id" yaml:"id"`
+	Title             string         `json:"title" yaml:"title"`
+	Objective         string         `json:"objective" yaml:"objective"`
+	Family            string         `json:"family,omitempty" yaml:"family,omitempty"`
+	Recommendations   []string       `json:"recommendations,omitempty" yaml:"recommendations,omitempty"`
+	GuidelineMappings []MultiMapping `json:"guideline-mappings,omitempty" yaml:"guideline-mappings,omitempty"`
+}
+
+// ParseGuidanceDocument decodes a guidance document from YAML or JSON content without validating it.
+func ParseGuidanceDocument(content []byte) (*GuidanceDocument, error) {
+	return parseArtifact(content, "guidance document", "guidelines", func(d *GuidanceDocument) bool {
+		return d.Metadata.ID != "" || len(d.Guidelines) > 0
+	})
+}
+
+// Guideline returns the guideline with the given ID.
+func (d *GuidanceDocument) Guideline(id string) (Guideline, bool) {
+	for _, guideline := range d.Guidelines {
+		if guideline.ID == id {
+			return guideline, true
+		}
+	}
+	return Guideline{}, false
+}
@@ -0,0 +1,320 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// WorkspaceIndex holds the Gemara artifacts found under the workspace directories of its
+// clients, keyed by their metadata ID, and rescans the files that change. Each MCP session
+// scans its own roots, and the index covers the directories of all of them, so sessions
+// sharing an index do not replace each other's artifacts. Artifacts without a metadata ID
+// cannot be addressed and are left out.
+type WorkspaceIndex struct {
+	loader *SchemaLoader
+
+	// scanMu serializes scans; mu guards the indexed state.
+	scanMu sync.Mutex
+	mu     sync.Mutex
+	// roots holds the directories each session scanned, with those scanned outside
+	// sessions under nil.
+	roots map[*mcp.ServerSession][]string
+	// files holds the candidate artifact files under the roots with the artifact each is.
+	files     map[string]indexedFile
+	artifacts map[string]IndexedArtifact
+}
+
+// IndexedArtifact is an artifact of a workspace index with its content.
+type IndexedArtifact struct {
+	WorkspaceArtifact
+	Content []byte
+	// ControlIDs are the IDs of the controls the artifact declares, in document order.
+	ControlIDs []string
+}
+
+// IndexChanges lists the IDs of the artifacts a scan added, updated and removed.
+type IndexChanges struct {
+	Added, Updated, Removed []string
+	// PreviousControlIDs holds the IDs of the controls updated and removed artifacts declared
+	// before the scan, keyed by artifact ID, for those that declared any.
+	PreviousControlIDs map[string][]string
+}
+
+// Empty reports whether the scan changed no artifacts.
+func (c IndexChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Updated) == 0 && len(c.Removed) == 0
+}
+
+// fileStamp identifies a version of a file for change detection.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// indexedFile is a version of a workspace file with the artifact found in it, if any.
+type indexedFile struct {
+	stamp    fileStamp
+	artifact *IndexedArtifact
+}
+
+// NewWorkspaceIndex creates an empty index that detects artifacts with the latest module
+// version known to the specified schema loader.
+func NewWorkspaceIndex(loader *SchemaLoader) *WorkspaceIndex {
+	return &WorkspaceIndex{
+		loader:    loader,
+		roots:     make(map[*mcp.ServerSession][]string),
+		files:     make(map[string]indexedFile),
+		artifacts: make(map[string]IndexedArtifact),
+	}
+}
+
+// Scan indexes the artifacts under dirs, replacing the directories scanned before outside
+// MCP sessions, as commands that index a workspace of their own do.
+func (w *WorkspaceIndex) Scan(ctx context.Context, dirs []string) (IndexChanges, error) {
+	return w.ScanSession(ctx, nil, dirs)
+}
+
+// ScanSession indexes the artifacts under dirs as the roots of session, replacing those the
+// session scanned before and keeping those of other sessions.
+func (w *WorkspaceIndex) ScanSession(ctx context.Context, session *mcp.ServerSession, dirs []string) (IndexChanges, error) {
+	w.scanMu.Lock()
+	defer w.scanMu.Unlock()
+
+	w.mu.Lock()
+	previous, ok := w.roots[session]
+	w.roots[session] = slices.Clone(dirs)
+	w.mu.Unlock()
+
+	changes, err := w.refresh(ctx)
+	if err != nil {
+		w.mu.Lock()
+		if ok {
+			w.roots[session] = previous
+		} else {
+			delete(w.roots, session)
+		}
+		w.mu.Unlock()
+	}
+	return changes, err
+}
+
+// Drop removes the roots of session from the index, with the artifacts no other session's
+// roots contain.
+func (w *WorkspaceIndex) Drop(ctx context.Context, session *mcp.ServerSession) (IndexChanges, error) {
+	w.scanMu.Lock()
+	defer w.scanMu.Unlock()
+
+	w.mu.Lock()
+	delete(w.roots, session)
+	w.mu.Unlock()
+	return w.refresh(ctx)
+}
+
+// Refresh rescans the files under the directories of the index that were added, removed
+// or modified since the last scan.
+func (w *WorkspaceIndex) Refresh(ctx context.Context) (IndexChanges, error) {
+	w.scanMu.Lock()
+	defer w.scanMu.Unlock()
+	return w.refresh(ctx)
+}
+
+// refresh detects the artifacts of the files under the directories of all clients that
+// changed since the last scan and swaps them in, reporting what changed. Files whose
+// modification time and size are unchanged keep the artifact found before.
+func (w *WorkspaceIndex) refresh(ctx context.Context) (IndexChanges, error) {
+	w.mu.Lock()
+	var dirs []string
+	for _, roots := range w.roots {
+		dirs = append(dirs, roots...)
+	}
+	indexed := w.files
+	w.mu.Unlock()
+	slices.Sort(dirs)
+	dirs = slices.Compact(dirs)
+
+	stamps, err := stampFiles(dirs)
+	if err != nil {
+		return IndexChanges{}, err
+	}
+	var changed []string
+	for path, stamp := range stamps {
+		if file, ok := indexed[path]; !ok || file.stamp != stamp {
+			changed = append(changed, path)
+		}
+	}
+	if len(changed) == 0 && len(stamps) == len(indexed) {
+		return IndexChanges{}, nil
+	}
+
+	files := make(map[string]indexedFile, len(stamps))
+	for path, stamp := range stamps {
+		files[path] = indexedFile{stamp: stamp, artifact: indexed[path].artifact}
+	}
+	if len(changed) > 0 {
+		schema, err := w.loader.Load(ctx, defaultSchemaVersion, false)
+		if err != nil {
+			return IndexChanges{}, err
+		}
+		defs, err := artifactDefinitions(schema.Value)
+		if err != nil {
+			return IndexChanges{}, err
+		}
+
+		slices.Sort(changed)
+		reporterFrom(ctx).expect(len(changed))
+		for _, path := range changed {
+			found, content, ok, err := scanArtifactFile(ctx, schema, defs, path)
+			if err != nil {
+				return IndexChanges{}, err
+			}
+			var artifact *IndexedArtifact
+			if ok && found.ID != "" {
+				artifact = &IndexedArtifact{
+					WorkspaceArtifact: found,
+					Content:           content,
+					ControlIDs:        controlIDs(content),
+				}
+			}
+			files[path] = indexedFile{stamp: stamps[path], artifact: artifact}
+		}
+	}
+
+	r := reporterFrom(ctx)
+	paths := slices.Sorted(maps.Keys(files))
+	artifacts := make(map[string]IndexedArtifact, len(files))
+	for _, path := range paths {
+		artifact := files[path].artifact
+		if artifact == nil {
+			continue
+		}
+		if other, ok := artifacts[artifact.ID]; ok {
+			r.log(ctx, logWarning, "ignoring %s: artifact %s is already defined in %s", path, artifact.ID, other.Path)
+			continue
+		}
+		artifacts[artifact.ID] = *artifact
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var changes IndexChanges
+	keepControls := func(previous IndexedArtifact) {
+		if len(previous.ControlIDs) == 0 {
+			return
+		}
+		if changes.PreviousControlIDs == nil {
+			changes.PreviousControlIDs = make(map[string][]string)
+		}
+		changes.PreviousControlIDs[previous.ID] = previous.ControlIDs
+	}
+	for id, artifact := range artifacts {
+		previous, ok := w.artifacts[id]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, id)
+		case previous.Path != artifact.Path || !bytes.Equal(previous.Content, artifact.Content):
+			changes.Updated = append(changes.Updated, id)
+			keepControls(previous)
+		}
+	}
+	for id, previous := range w.artifacts {
+		if _, ok := artifacts[id]; !ok {
+			changes.Removed = append(changes.Removed, id)
+			keepControls(previous)
+		}
+	}
+	slices.Sort(changes.Added)
+	slices.Sort(changes.Updated)
+	slices.Sort(changes.Removed)
+
+	w.files, w.artifacts = files, artifacts
+	return changes, nil
+}
+
+// Artifact returns the indexed artifact with the given metadata ID.
+func (w *WorkspaceIndex) Artifact(id string) (IndexedArtifact, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	artifact, ok := w.artifacts[id]
+	return artifact, ok
+}
+
+// Artifacts returns the indexed artifacts ordered by ID.
+func (w *WorkspaceIndex) Artifacts() []IndexedArtifact {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	artifacts := slices.Collect(maps.Values(w.artifacts))
+	slices.SortFunc(artifacts, func(a, b IndexedArtifact) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return artifacts
+}
+
+// Control returns the control with the given ID of an artifact as YAML.
+func (a IndexedArtifact) Control(id string) ([]byte, bool) {
+	var doc struct {
+		Controls []yaml.MapSlice `yaml:"controls"`
+	}
+	if err := yaml.Unmarshal(a.Content, &doc); err != nil {
+		return nil, false
+	}
+	for _, control := range doc.Controls {
+		if control.ToMap()["id"] != id {
+			continue
+		}
+		data, err := yaml.Marshal(control)
+		if err != nil {
+			return nil, false
+		}
+		return data, true
+	}
+	return nil, false
+}
+
+// controlIDs returns the IDs of the top-level controls of artifact content.
+func controlIDs(content []byte) []string {
+	var doc struct {
+		Controls []struct {
+			ID string `yaml:"id"`
+		} `yaml:"controls"`
+	}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil
+	}
+	var ids []string
+	for _, control := range doc.Controls {
+		if control.ID != "" {
+			ids = append(ids, control.ID)
+		}
+	}
+	return ids
+}
+
+// stampFiles records the modification time and size of the candidate artifact files under dirs.
+func stampFiles(dirs []string) (map[string]fileStamp, error) {
+	stamps := make(map[string]fileStamp)
+	for _, dir := range dirs {
+		files, err := artifactFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return stamps, nil
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceIndex(t *testing.T) {
+	dir := newTestWorkspace(t)
+	index := NewWorkspaceIndex(newTestSchemaLoader(t))
+	ctx := context.Background()
+
+	changes, err := index.Scan(ctx, []string{dir})
+	require.NoError(t, err)
+	assert.Equal(t, IndexChanges{Added: []string{"FINOS-CCC", "OS-THREATS", "nightly-2026-01-01"}}, changes)
+
+	catalog, ok := index.Artifact("FINOS-CCC")
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, "catalogs", "ccc.yaml"), catalog.Path)
+	assert.Equal(t, []string{"CCC.C01", "CCC.C06", "CCC.C08", "CCC.C09", "CCC.C10"}, catalog.ControlIDs)
+
+	control, ok := catalog.Control("CCC.C06")
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(string(control), "id: CCC.C06\n"), "control should keep its field order:\n%s", control)
+	_, ok = catalog.Control("CCC.C99")
+	assert.False(t, ok, "unknown control should not be found")
+
+	changes, err = index.Refresh(ctx)
+	require.NoError(t, err)
+	assert.True(t, changes.Empty(), "unchanged files should not be rescanned")
+
+	threats := filepath.Join(dir, "threats", "object-storage.yml")
+	require.NoError(t, os.WriteFile(threats, []byte(strings.Replace(threatCatalogV010, "Object Storage Threats", "Object Storage Threat Model", 1)), 0o600))
+	require.NoError(t, os.Remove(filepath.Join(dir, "evaluations", "nightly.json")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "copy.yaml"), []byte(strings.Replace(threatCatalogV010, "OS-THREATS", "OS-THREATS-COPY", 1)), 0o600))
+
+	changes, err = index.Refresh(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, IndexChanges{
+		Added:   []string{"OS-THREATS-COPY"},
+		Updated: []string{"OS-THREATS"},
+		Removed: []string{"nightly-2026-01-01"},
+	}, changes)
+
+	ids := make([]string, 0, 3)
+	for _, artifact := range index.Artifacts() {
+		ids = append(ids, artifact.ID)
+	}
+	assert.Equal(t, []string{"FINOS-CCC", "OS-THREATS", "OS-THREATS-COPY"}, ids)
+
+	updated, ok := index.Artifact("OS-THREATS")
+	require.True(t, ok)
+	assert.Equal(t, "Object Storage Threat Model", updated.Title)
+}
+
+func TestWorkspaceIndexDuplicateIDs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.yaml", "b.yaml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(threatCatalogV010), 0o600))
+	}
+
+	index := NewWorkspaceIndex(newTestSchemaLoader(t))
+	changes, err := index.Scan(context.Background(), []string{dir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OS-THREATS"}, changes.Added)
+
+	artifact, ok := index.Artifact("OS-THREATS")
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, "a.yaml"), artifact.Path, "the first artifact by path should win")
+}
+
+func TestWorkspaceIndexRefreshWithoutScan(t *testing.T) {
+	index := NewWorkspaceIndex(newTestSchemaLoader(t))
+	changes, err := index.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.True(t, changes.Empty())
+	assert.Empty(t, index.Artifacts())
+}
+
+func TestWorkspaceIndexSessions(t *testing.T) {
+	dir := newTestWorkspace(t)
+	index := NewWorkspaceIndex(newTestSchemaLoader(t))
+	ctx := context.Background()
+	catalogs, threats, workspace := new(mcp.ServerSession), new(mcp.ServerSession), new(mcp.ServerSession)
+
+	changes, err := index.ScanSession(ctx, catalogs, []string{filepath.Join(dir, "catalogs")})
+	require.NoError(t, err)
+	assert.Equal(t, IndexChanges{Added: []string{"FINOS-CCC"}}, changes)
+
+	changes, err = index.ScanSession(ctx, threats, []string{filepath.Join(dir, "threats")})
+	require.NoError(t, err)
+	assert.Equal(t, IndexChanges{Added: []string{"OS-THREATS"}}, changes, "the artifacts of other sessions should be kept")
+
+	changes, err = index.ScanSession(ctx, workspace, []string{dir})
+	require.NoError(t, err)
+	assert.Equal(t, IndexChanges{Added: []string{"nightly-2026-01-01"}}, changes)
+
+	changes, err = index.Drop(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, IndexChanges{Removed: []string{"nightly-2026-01-01"}}, changes, "artifacts under the roots of other sessions should be kept")
+
+	changes, err = index.ScanSession(ctx, threats, []string{filepath.Join(dir, "missing")})
+	require.Error(t, err)
+	assert.True(t, changes.Empty())
+	_, ok := index.Artifact("OS-THREATS")
+	assert.True(t, ok, "a failed scan should keep the previous roots of the session")
+
+	changes, err = index.Drop(ctx, catalogs)
+	require.NoError(t, err)
+	assert.Equal(t, IndexChanges{
+		Removed:            []string{"FINOS-CCC"},
+		PreviousControlIDs: map[string][]string{"FINOS-CCC": {"CCC.C01", "CCC.C06", "CCC.C08", "CCC.C09", "CCC.C10"}},
+	}, changes, "the controls of removed artifacts should be kept")
+}
+
+func TestWorkspaceIndexUnchangedFiles(t *testing.T) {
+	dir := newTestWorkspace(t)
+	index := NewWorkspaceIndex(newTestSchemaLoader(t))
+	ctx := context.Background()
+
+	_, err := index.Scan(ctx, []string{dir})
+	require.NoError(t, err)
+
+	// A file rewritten with the same size and modification time is taken as unchanged.
+	threats := filepath.Join(dir, "threats", "object-storage.yml")
+	info, err := os.Stat(threats)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(threats, []byte(strings.Replace(threatCatalogV010, "Object Storage Threats", "Object Storage Threatz", 1)), 0o600))
+	require.NoError(t, os.Chtimes(threats, info.ModTime(), info.ModTime()))
+	catalog := filepath.Join(dir, "catalogs", "ccc.yaml")
+	content, err := os.ReadFile(catalog)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(catalog, []byte(strings.Replace(string(content), "FINOS Cloud Control Catalog", "FINOS Cloud Controls Catalog", 1)), 0o600))
+
+	changes, err := index.Refresh(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"FINOS-CCC"}, changes.Updated, "only files with new stamps should be rescanned")
+	artifact, ok := index.Artifact("OS-THREATS")
+	require.True(t, ok)
+	assert.Equal(t, "Object Storage Threats", artifact.Title)
+}
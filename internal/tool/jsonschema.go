@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/encoding/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MetadataExportJSONSchema describes the ExportJSONSchema tool.
+var MetadataExportJSONSchema = &mcp.Tool{
+	Name:         "export_json_schema",
+	Description:  "Convert a Gemara CUE definition, or all definitions as a bundle, into JSON Schema (draft 2020-12) for editors and tools that do not understand CUE.",
+	InputSchema:  schemaFor[InputExportJSONSchema](),
+	OutputSchema: schemaFor[OutputExportJSONSchema](),
+	Annotations:  readOnlyAnnotations(false),
+}
+
+// InputExportJSONSchema is the input for the ExportJSONSchema tool.
+type InputExportJSONSchema struct {
+	Version    string `json:"version,omitempty" jsonschema:"Version of the Gemara module (default: 'latest')"`
+	Definition string `json:"definition,omitempty" jsonschema:"Definition to convert (e.g., '#ControlCatalog'); when omitted, all definitions are exported under $defs"`
+}
+
+// OutputExportJSONSchema is the output for the ExportJSONSchema tool.
+type OutputExportJSONSchema struct {
+	Version    string         `json:"version"`
+	Definition string         `json:"definition,omitempty"`
+	Schema     map[string]any `json:"schema"`
+}
+
+// ExportJSONSchema converts definitions of the module loaded by the specified schema loader to JSON Schema.
+func ExportJSONSchema(ctx context.Context, _ *mcp.CallToolRequest, input InputExportJSONSchema, loader *SchemaLoader) (*mcp.CallToolResult, OutputExportJSONSchema, error) {
+	schema, err := loader.Load(ctx, input.Version, false)
+	if err != nil {
+		return nil, OutputExportJSONSchema{}, err
+	}
+
+	output := OutputExportJSONSchema{Version: schema.Version}
+	if input.Definition == "" {
+		output.Schema, err = JSONSchemaBundle(schema)
+	} else {
+		output.Definition, output.Schema, err = DefinitionJSONSchema(schema, input.Definition)
+	}
+	if err != nil {
+		return nil, OutputExportJSONSchema{}, err
+	}
+	return nil, output, nil
+}
+
+// DefinitionJSONSchema converts a single definition to a JSON Schema whose root validates
+// documents of that definition. It returns the definition name with its leading '#'.
+func DefinitionJSONSchema(schema *Schema, definition string) (string, map[string]any, error) {
+	v, name, err := lookupDefinition(schema.Value, definition)
+	if err != nil {
+		return "", nil, err
+	}
+	doc, err := generateJSONSchema(v)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to convert %s to JSON Schema: %w", name, err)
+	}
+	return name, doc, nil
+}
+
+// JSONSchemaBundle converts all definitions into a single JSON Schema that holds each of
+// them under $defs, keyed by the definition name without its leading '#'.
+func JSONSchemaBundle(schema *Schema) (map[string]any, error) {
+	defs, err := schemaDefinitions(schema.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	bundled := make(map[string]any)
+	for _, def := range defs {
+		doc, err := generateJSONSchema(schema.Value.LookupPath(cue.ParsePath(def.Name)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s to JSON Schema: %w", def.Name, err)
+		}
+		if refs, ok := doc["$defs"].(map[string]any); ok {
+			for name, ref := range refs {
+				if err := bundleDefinition(bundled, name, ref); err != nil {
+					return nil, fmt.Errorf("failed to bundle %s: %w", def.Name, err)
+				}
+			}
+		}
+		delete(doc, "$schema")
+		delete(doc, "$defs")
+		if err := bundleDefinition(bundled, jsonSchemaName(def.Name), doc); err != nil {
+			return nil, fmt.Errorf("failed to bundle %s: %w", def.Name, err)
+		}
+	}
+
+	return map[string]any{
+		"$schema": jsonschema.VersionDraft2020_12.String(),
+		"$defs":   bundled,
+	}, nil
+}
+
+// bundleDefinition adds the schema of a definition to bundled under name. Definitions are
+// converted with the definitions they reference, so a name may be added more than once,
+// but only with the same schema.
+func bundleDefinition(bundled map[string]any, name string, schema any) error {
+	if existing, ok := bundled[name]; ok && !reflect.DeepEqual(existing, schema) {
+		return fmt.Errorf("$defs entry %q has conflicting schemas", name)
+	}
+	bundled[name] = schema
+	return nil
+}
+
+// generateJSONSchema converts a CUE value to a JSON Schema document.
+func generateJSONSchema(v cue.Value) (map[string]any, error) {
+	expr, err := jsonschema.Generate(v, &jsonschema.GenerateConfig{
+		NameFunc: func(_ cue.Value, path cue.Path) string {
+			return jsonSchemaName(path.String())
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := v.Context().BuildExpr(expr).MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// jsonSchemaName maps a definition path such as #Control to its $defs key.
+func jsonSchemaName(path string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(path, "#"), ".#", ".")
+}
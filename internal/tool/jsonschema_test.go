@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/goccy/go-yaml"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resolveJSONSchema decodes a generated schema with an independent JSON Schema implementation.
+func resolveJSONSchema(t *testing.T, doc map[string]any) *jsonschema.Resolved {
+	t.Helper()
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	var schema jsonschema.Schema
+	require.NoError(t, json.Unmarshal(data, &schema), "generated schema should decode")
+	resolved, err := schema.Resolve(nil)
+	require.NoError(t, err, "generated schema should resolve")
+	return resolved
+}
+
+// loadYAMLInstance reads a YAML document as a JSON Schema instance.
+func loadYAMLInstance(t *testing.T, data []byte) any {
+	t.Helper()
+	var instance any
+	require.NoError(t, yaml.Unmarshal(data, &instance))
+	// Round-trip through JSON so the instance only holds JSON types.
+	jsonData, err := json.Marshal(instance)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(jsonData, &instance))
+	return instance
+}
+
+func TestExportJSONSchema(t *testing.T) {
+	loader := newTestSchemaLoader(t)
+
+	tests := []struct {
+		name           string
+		input          InputExportJSONSchema
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputExportJSONSchema)
+	}{
+		{
+			name:  "single definition",
+			input: InputExportJSONSchema{Definition: "ControlCatalog"},
+			validateOutput: func(t *testing.T, output OutputExportJSONSchema) {
+				assert.Equal(t, "v0.2.0", output.Version)
+				assert.Equal(t, "#ControlCatalog", output.Definition)
+				assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", output.Schema["$schema"])
+				assert.Equal(t, "object", output.Schema["type"], "root should describe the definition itself")
+				assert.Contains(t, output.Schema["$defs"], "Control", "referenced definitions should be included")
+				assert.NotContains(t, output.Schema["$defs"], "EvaluationLog", "unreferenced definitions should be omitted")
+			},
+		},
+		{
+			name:  "bundle of all definitions",
+			input: InputExportJSONSchema{Version: "v0.1.0"},
+			validateOutput: func(t *testing.T, output OutputExportJSONSchema) {
+				assert.Empty(t, output.Definition)
+				assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", output.Schema["$schema"])
+				defs, ok := output.Schema["$defs"].(map[string]any)
+				require.True(t, ok, "bundle should hold definitions under $defs")
+				for _, name := range []string{"ControlCatalog", "EvaluationLog", "Policy", "Result", "Metadata"} {
+					assert.Contains(t, defs, name)
+				}
+				assert.NotContains(t, defs["ControlCatalog"], "$schema", "bundled definitions should not be standalone documents")
+				resolveJSONSchema(t, output.Schema)
+			},
+		},
+		{
+			name:        "unknown definition",
+			input:       InputExportJSONSchema{Definition: "#Nope"},
+			wantErr:     true,
+			errContains: "definition #Nope not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := ExportJSONSchema(context.Background(), nil, tt.input, loader)
+
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			if tt.validateOutput != nil {
+				tt.validateOutput(t, output)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaBundleConflictingDefinitions(t *testing.T) {
+	// The definition #A.#B and the field #A.B share the $defs key "A.B".
+	value := cuecontext.New().CompileString(`
+#A: {#B: {x: int}, B: {z: string}}
+#C: {y: #A.#B}
+#D: {w: #A.B}
+`)
+	require.NoError(t, value.Err())
+
+	_, err := JSONSchemaBundle(&Schema{Version: "v0.0.0", Value: value})
+	require.Error(t, err, "conflicting $defs entries should not overwrite each other")
+	assert.Contains(t, err.Error(), `$defs entry "A.B" has conflicting schemas`)
+}
+
+func TestExportJSONSchemaRoundTrip(t *testing.T) {
+	_, output, err := ExportJSONSchema(context.Background(), nil, InputExportJSONSchema{Definition: "#ControlCatalog"}, newTestSchemaLoader(t))
+	require.NoError(t, err)
+	resolved := resolveJSONSchema(t, output.Schema)
+
+	data, err := os.ReadFile("testdata/good-ccc.yaml")
+	require.NoError(t, err)
+	assert.NoError(t, resolved.Validate(loadYAMLInstance(t, data)), "good-ccc.yaml should satisfy the generated schema")
+
+	tests := []struct {
+		name     string
+		document string
+	}{
+		{
+			name:     "missing required field",
+			document: "metadata: {id: x, description: d, author: {id: a, name: A}}\n",
+		},
+		{
+			name:     "unknown field on closed definition",
+			document: "title: t\nmetadata: {id: x, description: d, author: {id: a, name: A}}\nunknown: true\n",
+		},
+		{
+			name:     "value outside enum",
+			document: "title: t\nmetadata: {id: x, description: d, author: {id: a, name: A, type: Robot}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, resolved.Validate(loadYAMLInstance(t, []byte(tt.document))), "invalid document should be rejected")
+		})
+	}
+}
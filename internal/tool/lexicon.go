@@ -22,26 +22,21 @@ type LexiconEntry struct {
 type OutputGetLexicon struct {
 	Entries []LexiconEntry `json:"entries"`
 	Source  string         `json:"source"`
+	Digest  string         `json:"digest"`
 }
 
 // MetadataGetLexicon describes the GetLexicon tool.
 var MetadataGetLexicon = &mcp.Tool{
-	Name:        "get_lexicon",
-	Description: "Retrieve the Gemara Lexicon containing definitions of terms used in the Gemara model.",
-	InputSchema: map[string]interface{}{
-		"type": "object",
-		"properties": map[string]interface{}{
-			"refresh": map[string]interface{}{
-				"type":        "boolean",
-				"description": "Force refresh of lexicon cache (default: false)",
-			},
-		},
-	},
+	Name:         "get_lexicon",
+	Description:  "Retrieve the Gemara Lexicon containing definitions of terms used in the Gemara model.",
+	InputSchema:  schemaFor[InputGetLexicon](),
+	OutputSchema: schemaFor[OutputGetLexicon](),
+	Annotations:  readOnlyAnnotations(false),
 }
 
 // InputGetLexicon is the input for the GetLexicon tool.
 type InputGetLexicon struct {
-	Refresh bool `json:"refresh"`
+	Refresh bool `json:"refresh,omitempty" jsonschema:"Force refresh of lexicon cache (default: false)"`
 }
 
 // GetLexicon retrieves the Gemara Lexicon using the specified cached fetcher.
@@ -51,13 +46,23 @@ func GetLexicon(ctx context.Context, _ *mcp.CallToolRequest, input InputGetLexic
 		return nil, OutputGetLexicon{}, err
 	}
 
-	var entries []LexiconEntry
-	if err := yaml.Unmarshal(data, &entries); err != nil {
-		return nil, OutputGetLexicon{}, fmt.Errorf("failed to parse YAML: %w", err)
+	entries, err := parseLexicon(data)
+	if err != nil {
+		return nil, OutputGetLexicon{}, err
 	}
 
 	return nil, OutputGetLexicon{
 		Entries: entries,
 		Source:  sourceID,
+		Digest:  fetcher.Digest(data),
 	}, nil
 }
+
+// parseLexicon decodes the entries of a lexicon document.
+func parseLexicon(data []byte) ([]LexiconEntry, error) {
+	var entries []LexiconEntry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return entries, nil
+}
@@ -16,18 +16,28 @@ import (
 
 // mockFetcher is a test fetcher that returns predefined data.
 type mockFetcher struct {
-	data   []byte
-	source string
-	err    error
+	data      []byte
+	source    string
+	err       error
+	callCount int
 }
 
 func (m *mockFetcher) Fetch(ctx context.Context) ([]byte, string, error) {
+	m.callCount++
 	if m.err != nil {
 		return nil, "", m.err
 	}
 	return m.data, m.source, nil
 }
 
+// sampleLexicon is a minimal lexicon document.
+const sampleLexicon = `- term: Assessment
+  definition: Atomic process used to determine a resource's compliance
+  references: ["Layer 5"]
+- term: Control
+  definition: Safeguard or countermeasure
+  references: ["Layer 2"]`
+
 func TestGetLexicon(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -40,12 +50,7 @@ func TestGetLexicon(t *testing.T) {
 		{
 			name: "successful fetch and parse",
 			mockFetcher: &mockFetcher{
-				data: []byte(`- term: Assessment
-  definition: Atomic process used to determine a resource's compliance
-  references: ["Layer 5"]
-- term: Control
-  definition: Safeguard or countermeasure
-  references: ["Layer 2"]`),
+				data:   []byte(sampleLexicon),
 				source: "mock://lexicon.yaml",
 			},
 			input:          InputGetLexicon{Refresh: false},
@@ -55,6 +60,7 @@ func TestGetLexicon(t *testing.T) {
 				assert.Len(t, output.Entries, 2, "should have 2 entries")
 				assert.Equal(t, "Assessment", output.Entries[0].Term, "first term should be Assessment")
 				assert.Equal(t, "Control", output.Entries[1].Term, "second term should be Control")
+				assert.Equal(t, fetcher.Digest([]byte(sampleLexicon)), output.Digest, "should report digest of fetched lexicon")
 			},
 		},
 		{
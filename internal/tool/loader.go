@@ -0,0 +1,388 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"sync"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/load"
+	"cuelang.org/go/mod/modconfig"
+	"cuelang.org/go/mod/module"
+	"golang.org/x/mod/semver"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
+)
+
+const (
+	gemaraModulePath  = "github.com/gemaraproj/gemara"
+	gemaraModuleMajor = gemaraModulePath + "@v0"
+)
+
+// Schema is a loaded version of the Gemara CUE module.
+type Schema struct {
+	// Version is the exact module version that was loaded.
+	Version string
+	// Value is the root of the module's package.
+	Value cue.Value
+	// Digest is the SHA-256 digest of the module's file listing, which identifies the
+	// content of the loaded version.
+	Digest string
+}
+
+// SchemaLoader loads versions of the Gemara CUE module from a CUE registry.
+// Loaded module instances are kept for the lifetime of the loader, while every
+// Load builds the schema in a fresh CUE context so callers can use it independently.
+type SchemaLoader struct {
+	config *modconfig.Config
+	// checks verify each module version before it is loaded, fetching checksum files with client.
+	checks SourceChecks
+	client *fetcher.Client
+
+	mu        sync.Mutex
+	registry  modconfig.Registry
+	latest    string
+	instances map[string]*build.Instance
+	// digests holds the digest of each loaded version.
+	digests map[string]string
+
+	// cacheMu guards what completion reads, so it never waits for a load in progress.
+	// Loads update it once they have resolved or built a version.
+	cacheMu      sync.RWMutex
+	cachedLatest string
+	// cachedVersions holds the versions listed or loaded so far in ascending semver order.
+	cachedVersions []string
+	// definitions holds the names of the root definitions of each loaded version.
+	definitions map[string][]string
+}
+
+// NewSchemaLoader creates a loader that resolves modules using the given registry
+// configuration. A nil config uses the standard CUE environment (CUE_REGISTRY, CUE_CACHE_DIR).
+func NewSchemaLoader(config *modconfig.Config) *SchemaLoader {
+	return &SchemaLoader{
+		config:      config,
+		instances:   make(map[string]*build.Instance),
+		digests:     make(map[string]string),
+		definitions: make(map[string][]string),
+	}
+}
+
+// SchemaModuleReference returns the reference of a version of the Gemara module, such as
+// github.com/gemaraproj/gemara@v0.2.0, which locates it in SourceChecks.
+func SchemaModuleReference(version string) string {
+	return gemaraModulePath + "@" + version
+}
+
+// WithVerification requires the module versions the loader loads to pass checks. Versions
+// are located by their module reference, such as github.com/gemaraproj/gemara@v0.2.0, and
+// listed in checksum files under its base name with the SHA-256 digest of their file
+// listing, which Schema.Digest reports. Checksum files are fetched with client.
+func (l *SchemaLoader) WithVerification(checks SourceChecks, client *fetcher.Client) *SchemaLoader {
+	l.checks = checks
+	l.client = client
+	return l
+}
+
+// Load returns the schema for version, which is an exact module version or "latest".
+// If refresh is true, the module is resolved and loaded again instead of reusing a
+// previously loaded instance.
+func (l *SchemaLoader) Load(ctx context.Context, version string, refresh bool) (*Schema, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	version, inst, err := l.instance(ctx, version, refresh)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := buildSchema(ctx, cuecontext.New(), version, inst)
+	if err != nil {
+		return nil, err
+	}
+	schema.Digest = l.digests[version]
+	if err := l.cacheDefinitions(schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// LoadVersions returns the schemas for several versions built in a single CUE context,
+// so values from different versions can be compared with each other.
+func (l *SchemaLoader) LoadVersions(ctx context.Context, versions ...string) ([]*Schema, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cueCtx := cuecontext.New()
+	schemas := make([]*Schema, 0, len(versions))
+	for _, version := range versions {
+		version, inst, err := l.instance(ctx, version, false)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := buildSchema(ctx, cueCtx, version, inst)
+		if err != nil {
+			return nil, err
+		}
+		schema.Digest = l.digests[version]
+		if err := l.cacheDefinitions(schema); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, nil
+}
+
+// instance resolves version and returns the module instance for it, loading the
+// module if it has not been loaded yet.
+func (l *SchemaLoader) instance(ctx context.Context, version string, refresh bool) (string, *build.Instance, error) {
+	reg, err := l.moduleRegistry()
+	if err != nil {
+		return "", nil, err
+	}
+
+	version, err = l.resolveVersion(ctx, reg, version, refresh)
+	if err != nil {
+		return "", nil, err
+	}
+
+	inst, found := l.instances[version]
+	if !found || refresh {
+		if err := ctx.Err(); err != nil {
+			return "", nil, err
+		}
+		reporterFrom(ctx).step(ctx, "loading module %s@%s", gemaraModulePath, version)
+
+		digest, err := l.verify(ctx, reg, version)
+		if err != nil {
+			return "", nil, err
+		}
+
+		// Pass the module path as an argument to load it from the registry
+		buildInstances := load.Instances([]string{gemaraModulePath + "@" + version}, &load.Config{
+			Registry: reg,
+		})
+		if len(buildInstances) == 0 {
+			return "", nil, fmt.Errorf("failed to load module: no instances returned")
+		}
+		if err := buildInstances[0].Err; err != nil {
+			return "", nil, fmt.Errorf("failed to load module: %w", err)
+		}
+		inst = buildInstances[0]
+		l.instances[version] = inst
+		l.digests[version] = digest
+		l.cacheVersions("", version)
+	}
+	return version, inst, nil
+}
+
+// verify fetches the files of a module version, checks them as the loader's checks declare
+// and returns the digest of their listing.
+func (l *SchemaLoader) verify(ctx context.Context, reg modconfig.Registry, version string) (string, error) {
+	m, err := module.NewVersion(gemaraModulePath, version)
+	if err != nil {
+		return "", err
+	}
+	loc, err := reg.Fetch(ctx, m)
+	if err != nil {
+		return "", fmt.Errorf("failed to load module: %w", err)
+	}
+	listing, err := moduleListing(loc)
+	if err != nil {
+		return "", err
+	}
+
+	source := l.checks.Source(SchemaModuleReference(version))
+	verifiers, err := source.verifiers(l.client)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range verifiers {
+		if err := v.Verify(ctx, listing); err != nil {
+			return "", fmt.Errorf("%s: %w", source.URL, err)
+		}
+	}
+	return fetcher.Digest(listing), nil
+}
+
+// moduleListing lists the files of a module as sha256sum would, one "<hex digest>  <path>"
+// line per file in path order.
+func moduleListing(loc module.SourceLoc) ([]byte, error) {
+	var listing bytes.Buffer
+	err := fs.WalkDir(loc.FS, loc.Dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(loc.FS, name)
+		if err != nil {
+			return err
+		}
+		rel := name
+		if loc.Dir != "." {
+			rel = name[len(path.Clean(loc.Dir))+1:]
+		}
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(&listing, "%s  %s\n", hex.EncodeToString(sum[:]), rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module files: %w", err)
+	}
+	return listing.Bytes(), nil
+}
+
+func buildSchema(ctx context.Context, cueCtx *cue.Context, version string, inst *build.Instance) (*Schema, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	reporterFrom(ctx).step(ctx, "building schema %s@%s", gemaraModulePath, version)
+
+	value := cueCtx.BuildInstance(inst)
+	if err := value.Err(); err != nil {
+		return nil, fmt.Errorf("failed to build schema: %w", err)
+	}
+
+	return &Schema{
+		Version: version,
+		Value:   value,
+	}, nil
+}
+
+func (l *SchemaLoader) moduleRegistry() (modconfig.Registry, error) {
+	if l.registry != nil {
+		return l.registry, nil
+	}
+	reg, err := modconfig.NewRegistry(l.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CUE registry: %w", err)
+	}
+	l.registry = reg
+	return reg, nil
+}
+
+// Versions returns the published versions of the module in ascending semver order.
+func (l *SchemaLoader) Versions(ctx context.Context) ([]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	reg, err := l.moduleRegistry()
+	if err != nil {
+		return nil, err
+	}
+	versions, err := moduleVersions(ctx, reg)
+	if err != nil {
+		return nil, err
+	}
+	l.latest = versions[len(versions)-1]
+	l.cacheVersions(l.latest, versions...)
+	return slices.Clone(versions), nil
+}
+
+// CachedVersions returns the versions of the module the loader knows of without reaching
+// the registry, in ascending semver order: those listed by earlier calls and those loaded.
+func (l *SchemaLoader) CachedVersions() []string {
+	l.cacheMu.RLock()
+	defer l.cacheMu.RUnlock()
+	return slices.Clone(l.cachedVersions)
+}
+
+// CachedDefinitionNames returns the sorted names of the root definitions of version if the
+// version has been loaded, without reaching the registry or waiting for loads in progress.
+// "latest" is the latest version resolved so far. Invalid versions have no names.
+func (l *SchemaLoader) CachedDefinitionNames(version string) []string {
+	l.cacheMu.RLock()
+	defer l.cacheMu.RUnlock()
+
+	if version == "" || version == defaultSchemaVersion {
+		version = l.cachedLatest
+	}
+	return slices.Clone(l.definitions[version])
+}
+
+// cacheVersions records versions as known and latest, if set, as the latest version.
+func (l *SchemaLoader) cacheVersions(latest string, versions ...string) {
+	l.cacheMu.Lock()
+	defer l.cacheMu.Unlock()
+
+	if latest != "" {
+		l.cachedLatest = latest
+	}
+	for _, version := range versions {
+		if !slices.Contains(l.cachedVersions, version) {
+			l.cachedVersions = append(l.cachedVersions, version)
+		}
+	}
+	semver.Sort(l.cachedVersions)
+}
+
+// cacheDefinitions records the names of the root definitions of a built schema, once per version.
+func (l *SchemaLoader) cacheDefinitions(schema *Schema) error {
+	l.cacheMu.RLock()
+	_, found := l.definitions[schema.Version]
+	l.cacheMu.RUnlock()
+	if found {
+		return nil
+	}
+
+	it, err := schema.Value.Fields(cue.Definitions(true))
+	if err != nil {
+		return fmt.Errorf("failed to list schema definitions: %w", err)
+	}
+	names := []string{}
+	for it.Next() {
+		if it.Selector().IsDefinition() {
+			names = append(names, it.Selector().String())
+		}
+	}
+	slices.Sort(names)
+
+	l.cacheMu.Lock()
+	defer l.cacheMu.Unlock()
+	l.definitions[schema.Version] = names
+	return nil
+}
+
+// resolveVersion maps "latest" to the highest published version of the module.
+func (l *SchemaLoader) resolveVersion(ctx context.Context, reg modconfig.Registry, version string, refresh bool) (string, error) {
+	if version != "" && version != defaultSchemaVersion {
+		if !semver.IsValid(version) {
+			return "", fmt.Errorf("invalid schema version %q", version)
+		}
+		return version, nil
+	}
+	if l.latest != "" && !refresh {
+		return l.latest, nil
+	}
+
+	r := reporterFrom(ctx)
+	r.step(ctx, "resolving latest version of %s", gemaraModulePath)
+	versions, err := moduleVersions(ctx, reg)
+	if err != nil {
+		return "", err
+	}
+	l.latest = versions[len(versions)-1]
+	l.cacheVersions(l.latest, versions...)
+	r.log(ctx, logInfo, "resolved latest version of %s to %s", gemaraModulePath, l.latest)
+	return l.latest, nil
+}
+
+// moduleVersions lists the published versions of the module in ascending semver order.
+func moduleVersions(ctx context.Context, reg modconfig.Registry) ([]string, error) {
+	versions, err := reg.ModuleVersions(ctx, gemaraModuleMajor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of %s: %w", gemaraModulePath, err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no published versions of %s found", gemaraModulePath)
+	}
+	semver.Sort(versions)
+	return versions, nil
+}
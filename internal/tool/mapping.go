@@ -0,0 +1,437 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Kinds of nodes in a mapping graph.
+const (
+	nodeControl   = "control"
+	nodeThreat    = "threat"
+	nodeGuideline = "guideline"
+	// nodeExternal is an entry of a document outside the Gemara layers, such as a MITRE
+	// ATT&CK technique a threat maps to.
+	nodeExternal = "external"
+)
+
+// Relations between nodes of a mapping graph.
+const (
+	relationMitigates = "mitigates"
+	relationSatisfies = "satisfies"
+	relationMapsTo    = "maps-to"
+)
+
+const (
+	// defaultMappingStrength is the strength the schema assigns to entries without one.
+	defaultMappingStrength = 1
+	// maxMappingStrength is the strength of identical entries, the weight of an empty path.
+	maxMappingStrength = 10
+	maxTraceHops       = 5
+	maxTracePaths      = 50
+)
+
+// MetadataTraceMapping describes the TraceMapping tool.
+var MetadataTraceMapping = &mcp.Tool{
+	Name:         "trace_mapping",
+	Description:  "Trace the mappings between controls, threats and guidelines of the Gemara artifacts in the client's workspace, e.g., which controls cover NIST-800-53 SC-8, which guidelines CCC.C01 satisfies, or the paths from a threat to the guidelines of a framework. Paths are weighted by the weakest mapping strength along them.",
+	InputSchema:  withEnum(schemaFor[InputTraceMapping](), "kind", nodeControl, nodeThreat, nodeGuideline, nodeExternal),
+	OutputSchema: schemaFor[OutputTraceMapping](),
+	Annotations:  readOnlyAnnotations(false),
+}
+
+// InputTraceMapping is the input for the TraceMapping tool.
+type InputTraceMapping struct {
+	From    string `json:"from" jsonschema:"Control, threat or guideline to start from, as 'document:id' or an ID that is unique across the workspace (e.g., 'NIST-800-53:SC-8', 'CCC.C01', 'CCC:CCC.TH02')"`
+	To      string `json:"to,omitempty" jsonschema:"Node to trace paths to; when omitted, the nodes reachable from 'from' are listed"`
+	Kind    string `json:"kind,omitempty" jsonschema:"Only list reachable nodes of this kind"`
+	MaxHops int    `json:"max_hops,omitempty" jsonschema:"Maximum number of mappings in a path (default: 1 without 'to', 3 with it; at most 5)"`
+}
+
+// OutputTraceMapping is the output for the TraceMapping tool.
+type OutputTraceMapping struct {
+	From MappingNode  `json:"from"`
+	To   *MappingNode `json:"to,omitempty"`
+	// Paths are ordered by weight, then by length. Without 'to', only the best path to each
+	// reachable node is listed.
+	Paths []MappingPath `json:"paths"`
+	// Truncated is set when more paths were found than are listed; with 'to', the search
+	// stopped at the limit, so stronger paths may not be listed.
+	Truncated bool `json:"truncated,omitempty"`
+	// Documents are the metadata IDs of the workspace artifacts the graph was built from.
+	Documents []string `json:"documents"`
+}
+
+// MappingNode is a control, threat or guideline in a mapping graph.
+type MappingNode struct {
+	Ref      string `json:"ref"`
+	Kind     string `json:"kind"`
+	Document string `json:"document"`
+	ID       string `json:"id"`
+	Title    string `json:"title,omitempty"`
+	// Loaded reports whether the node is defined by a workspace artifact rather than only
+	// referenced by mappings.
+	Loaded bool `json:"loaded"`
+}
+
+// MappingEdge is a mapping from one node to another, in the direction it is declared.
+type MappingEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"`
+	Strength int    `json:"strength"`
+	Remarks  string `json:"remarks,omitempty"`
+}
+
+// MappingPath is a chain of mappings from the start node to a target.
+type MappingPath struct {
+	Target MappingNode `json:"target"`
+	// Nodes are the refs of the nodes along the path, starting with the start node.
+	Nodes []string      `json:"nodes"`
+	Hops  []MappingEdge `json:"hops"`
+	// Weight is the smallest strength along the path.
+	Weight int `json:"weight"`
+}
+
+// MappingGraph links the controls, threats and guidelines of artifacts through their
+// mappings. Mappings are traversed in both directions.
+type MappingGraph struct {
+	nodes     map[string]*MappingNode
+	edges     map[string][]MappingEdge
+	documents []string
+}
+
+// NewMappingGraph creates an empty mapping graph.
+func NewMappingGraph() *MappingGraph {
+	return &MappingGraph{
+		nodes: make(map[string]*MappingNode),
+		edges: make(map[string][]MappingEdge),
+	}
+}
+
+// BuildMappingGraph builds a mapping graph from indexed workspace artifacts. Artifacts of
+// other definitions, or that cannot be parsed, are left out.
+func BuildMappingGraph(artifacts []IndexedArtifact) *MappingGraph {
+	g := NewMappingGraph()
+	for _, artifact := range artifacts {
+		switch artifact.Definition {
+		case "#ControlCatalog":
+			if catalog, err := ParseControlCatalog(artifact.Content); err == nil {
+				g.AddControlCatalog(catalog)
+			}
+		case "#ThreatCatalog":
+			if catalog, err := ParseThreatCatalog(artifact.Content); err == nil {
+				g.AddThreatCatalog(catalog)
+			}
+		case "#GuidanceDocument":
+			if document, err := ParseGuidanceDocument(artifact.Content); err == nil {
+				g.AddGuidanceDocument(document)
+			}
+		}
+	}
+	return g
+}
+
+// AddControlCatalog adds the controls of a catalog with the threats they mitigate and the
+// guidelines they satisfy.
+func (g *MappingGraph) AddControlCatalog(catalog *ControlCatalog) {
+	document := catalog.Metadata.ID
+	g.documents = append(g.documents, document)
+	for _, control := range catalog.Controls {
+		from := g.define(nodeControl, document, control.ID, control.Title)
+		g.connect(from, nodeThreat, relationMitigates, control.ThreatMappings)
+		g.connect(from, nodeGuideline, relationSatisfies, control.GuidelineMappings)
+	}
+}
+
+// AddThreatCatalog adds the threats of a catalog with the external entries they map to.
+func (g *MappingGraph) AddThreatCatalog(catalog *ThreatCatalog) {
+	document := catalog.Metadata.ID
+	g.documents = append(g.documents, document)
+	for _, threat := range catalog.Threats {
+		from := g.define(nodeThreat, document, threat.ID, threat.Title)
+		g.connect(from, nodeExternal, relationMapsTo, threat.ExternalMappings)
+	}
+}
+
+// AddGuidanceDocument adds the guidelines of a document with the guidelines they map to.
+func (g *MappingGraph) AddGuidanceDocument(document *GuidanceDocument) {
+	id := document.Metadata.ID
+	g.documents = append(g.documents, id)
+	for _, guideline := range document.Guidelines {
+		from := g.define(nodeGuideline, id, guideline.ID, guideline.Title)
+		g.connect(from, nodeGuideline, relationMapsTo, guideline.GuidelineMappings)
+	}
+}
+
+// Documents returns the metadata IDs of the artifacts added to the graph.
+func (g *MappingGraph) Documents() []string {
+	return g.documents
+}
+
+// define adds a node declared by an artifact, completing a node only referenced so far.
+func (g *MappingGraph) define(kind, document, id, title string) string {
+	ref := g.reference(kind, document, id)
+	node := g.nodes[ref]
+	node.Kind, node.Title, node.Loaded = kind, compactText(title), true
+	return ref
+}
+
+// reference adds a node referenced by a mapping unless it is known already.
+func (g *MappingGraph) reference(kind, document, id string) string {
+	ref := document + ":" + id
+	if _, ok := g.nodes[ref]; !ok {
+		g.nodes[ref] = &MappingNode{Ref: ref, Kind: kind, Document: document, ID: id}
+	}
+	return ref
+}
+
+// connect adds an edge from a node to each entry of mappings, which are nodes of kind.
+func (g *MappingGraph) connect(from, kind, relation string, mappings []MultiMapping) {
+	for _, mapping := range mappings {
+		for _, entry := range mapping.Entries {
+			to := g.reference(kind, mapping.ReferenceID, entry.ReferenceID)
+			strength := entry.Strength
+			if strength == 0 {
+				strength = defaultMappingStrength
+			}
+			edge := MappingEdge{From: from, To: to, Relation: relation, Strength: strength, Remarks: compactText(entry.Remarks)}
+			g.edges[from] = append(g.edges[from], edge)
+			g.edges[to] = append(g.edges[to], edge)
+		}
+	}
+}
+
+// Resolve finds the node a reference names: 'document:id', an ID unique across the graph or
+// 'document id', ignoring case.
+func (g *MappingGraph) Resolve(ref string) (MappingNode, error) {
+	var candidates []*MappingNode
+	for _, match := range []func(*MappingNode) bool{
+		func(n *MappingNode) bool { return strings.EqualFold(n.Ref, ref) },
+		func(n *MappingNode) bool { return strings.EqualFold(n.ID, ref) },
+		func(n *MappingNode) bool {
+			document, id, ok := strings.Cut(ref, " ")
+			return ok && strings.EqualFold(n.Document, document) && strings.EqualFold(n.ID, id)
+		},
+	} {
+		for _, node := range g.nodes {
+			if match(node) {
+				candidates = append(candidates, node)
+			}
+		}
+		if len(candidates) > 0 {
+			break
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return MappingNode{}, fmt.Errorf("%q is not a control, threat or guideline of the workspace artifacts or their mappings", ref)
+	case 1:
+		return *candidates[0], nil
+	}
+	refs := make([]string, 0, len(candidates))
+	for _, node := range candidates {
+		refs = append(refs, node.Ref)
+	}
+	slices.Sort(refs)
+	return MappingNode{}, fmt.Errorf("%q is ambiguous: use one of %s", ref, strings.Join(refs, ", "))
+}
+
+// BestPaths returns the best path of at most maxHops mappings from a node to each node
+// accept selects: the path whose weakest mapping is strongest, then the shortest. Paths are
+// sorted by weight, then by length. The search relaxes the mappings of the nodes improved in
+// the previous round, once per hop, so it grows with the number of mappings rather than with
+// the number of paths between dense many-to-many mappings.
+func (g *MappingGraph) BestPaths(from string, maxHops int, accept func(MappingNode) bool) []MappingPath {
+	// levels[k] holds the best route found to each node with at most k hops. A route
+	// improved in round k records that round as its hop count and the edge it arrived by.
+	type route struct {
+		weight, hops int
+		edge         MappingEdge
+		previous     string
+	}
+	levels := []map[string]route{{from: {weight: maxMappingStrength}}}
+	frontier := []string{from}
+	for hop := 1; hop <= maxHops && len(frontier) > 0; hop++ {
+		current := maps.Clone(levels[hop-1])
+		var improved []string
+		for _, ref := range frontier {
+			weight := levels[hop-1][ref].weight
+			for _, edge := range g.edges[ref] {
+				next := edge.To
+				if next == ref {
+					next = edge.From
+				}
+				candidate := route{weight: min(weight, edge.Strength), hops: hop, edge: edge, previous: ref}
+				if known, ok := current[next]; ok && known.weight >= candidate.weight {
+					continue
+				}
+				if _, ok := current[next]; !ok || current[next].hops < hop {
+					improved = append(improved, next)
+				}
+				current[next] = candidate
+			}
+		}
+		levels = append(levels, current)
+		frontier = improved
+	}
+
+	paths := []MappingPath{}
+	best := levels[len(levels)-1]
+	for ref, r := range best {
+		if ref == from || !accept(*g.nodes[ref]) {
+			continue
+		}
+		path := MappingPath{Target: *g.nodes[ref], Weight: r.weight}
+		for node, hops := ref, r.hops; node != from; {
+			r := levels[hops][node]
+			path.Nodes = append(path.Nodes, node)
+			path.Hops = append(path.Hops, r.edge)
+			node, hops = r.previous, r.hops-1
+		}
+		path.Nodes = append(path.Nodes, from)
+		slices.Reverse(path.Nodes)
+		slices.Reverse(path.Hops)
+		paths = append(paths, path)
+	}
+	sortPaths(paths)
+	return paths
+}
+
+// PathsTo returns the paths of at most maxHops mappings from a node to another, sorted by
+// weight, then by length. Branches that cannot reach the target within the remaining hops
+// are not explored, and the search stops once limit paths are found, reporting whether it
+// stopped early.
+func (g *MappingGraph) PathsTo(from, to string, maxHops, limit int) ([]MappingPath, bool) {
+	// distance holds the number of mappings from each node to the target, up to maxHops.
+	distance := map[string]int{to: 0}
+	queue := []string{to}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if distance[ref] == maxHops {
+			continue
+		}
+		for _, edge := range g.edges[ref] {
+			next := edge.To
+			if next == ref {
+				next = edge.From
+			}
+			if _, ok := distance[next]; !ok {
+				distance[next] = distance[ref] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	paths := []MappingPath{}
+	truncated := false
+	visited := map[string]bool{from: true}
+	nodes := []string{from}
+	var hops []MappingEdge
+
+	var walk func(ref string, weight int)
+	walk = func(ref string, weight int) {
+		if ref == to {
+			if len(paths) == limit {
+				truncated = true
+				return
+			}
+			paths = append(paths, MappingPath{
+				Target: *g.nodes[ref],
+				Nodes:  slices.Clone(nodes),
+				Hops:   slices.Clone(hops),
+				Weight: weight,
+			})
+			return
+		}
+		for _, edge := range g.edges[ref] {
+			next := edge.To
+			if next == ref {
+				next = edge.From
+			}
+			if d, ok := distance[next]; !ok || visited[next] || len(hops)+1+d > maxHops {
+				continue
+			}
+			visited[next] = true
+			nodes, hops = append(nodes, next), append(hops, edge)
+			walk(next, min(weight, edge.Strength))
+			nodes, hops = nodes[:len(nodes)-1], hops[:len(hops)-1]
+			visited[next] = false
+			if truncated {
+				return
+			}
+		}
+	}
+	if _, ok := distance[from]; ok && from != to {
+		walk(from, maxMappingStrength)
+	}
+	sortPaths(paths)
+	return paths, truncated
+}
+
+// sortPaths orders paths by weight, then by length, then by target.
+func sortPaths(paths []MappingPath) {
+	slices.SortStableFunc(paths, func(a, b MappingPath) int {
+		return cmp.Or(
+			cmp.Compare(b.Weight, a.Weight),
+			cmp.Compare(len(a.Hops), len(b.Hops)),
+			cmp.Compare(a.Target.Ref, b.Target.Ref),
+		)
+	})
+}
+
+// TraceMapping traces mappings through the artifacts of the workspace index.
+func TraceMapping(_ context.Context, _ *mcp.CallToolRequest, input InputTraceMapping, index *WorkspaceIndex) (*mcp.CallToolResult, OutputTraceMapping, error) {
+	if input.From == "" {
+		return nil, OutputTraceMapping{}, fmt.Errorf("from is required")
+	}
+	if input.Kind != "" && !slices.Contains([]string{nodeControl, nodeThreat, nodeGuideline, nodeExternal}, input.Kind) {
+		return nil, OutputTraceMapping{}, fmt.Errorf("unsupported kind %q: use %q, %q, %q or %q", input.Kind, nodeControl, nodeThreat, nodeGuideline, nodeExternal)
+	}
+	maxHops := input.MaxHops
+	if maxHops == 0 {
+		maxHops = 1
+		if input.To != "" {
+			maxHops = 3
+		}
+	}
+	if maxHops < 0 || maxHops > maxTraceHops {
+		return nil, OutputTraceMapping{}, fmt.Errorf("max_hops must be between 1 and %d", maxTraceHops)
+	}
+
+	graph := BuildMappingGraph(index.Artifacts())
+	if len(graph.Documents()) == 0 {
+		return nil, OutputTraceMapping{}, fmt.Errorf("no control catalogs, threat catalogs or guidance documents are indexed: the client must expose file:// roots containing them")
+	}
+	from, err := graph.Resolve(input.From)
+	if err != nil {
+		return nil, OutputTraceMapping{}, err
+	}
+
+	output := OutputTraceMapping{From: from, Documents: graph.Documents()}
+	if input.To != "" {
+		to, err := graph.Resolve(input.To)
+		if err != nil {
+			return nil, OutputTraceMapping{}, err
+		}
+		output.To = &to
+		output.Paths, output.Truncated = graph.PathsTo(from.Ref, to.Ref, maxHops, maxTracePaths)
+		return nil, output, nil
+	}
+
+	output.Paths = graph.BestPaths(from.Ref, maxHops, func(node MappingNode) bool { return input.Kind == "" || node.Kind == input.Kind })
+	if len(output.Paths) > maxTracePaths {
+		output.Paths, output.Truncated = output.Paths[:maxTracePaths], true
+	}
+	return nil, output, nil
+}
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMappingIndex indexes a workspace with the CCC control and threat catalogs and the
+// NIST 800-53 excerpt.
+func newTestMappingIndex(t *testing.T) *WorkspaceIndex {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"good-ccc.yaml", "ccc-threats.yaml", "nist-800-53.yaml"} {
+		content, err := os.ReadFile(filepath.Join("testdata", name))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0o600))
+	}
+
+	index := NewWorkspaceIndex(newTestSchemaLoader(t))
+	changes, err := index.Scan(context.Background(), []string{dir})
+	require.NoError(t, err)
+	require.Equal(t, []string{"CCC", "FINOS-CCC", "NIST-800-53"}, changes.Added)
+	return index
+}
+
+func TestMappingGraph(t *testing.T) {
+	graph := BuildMappingGraph(newTestMappingIndex(t).Artifacts())
+	assert.Equal(t, []string{"CCC", "FINOS-CCC", "NIST-800-53"}, graph.Documents())
+
+	node, err := graph.Resolve("ccc.c01")
+	require.NoError(t, err)
+	assert.Equal(t, MappingNode{Ref: "FINOS-CCC:CCC.C01", Kind: nodeControl, Document: "FINOS-CCC", ID: "CCC.C01", Title: "Prevent Unencrypted Requests", Loaded: true}, node)
+
+	node, err = graph.Resolve("NIST-800-53 SC-8")
+	require.NoError(t, err)
+	assert.Equal(t, "Transmission Confidentiality and Integrity", node.Title)
+
+	node, err = graph.Resolve("CCM:IVS-03")
+	require.NoError(t, err)
+	assert.Equal(t, MappingNode{Ref: "CCM:IVS-03", Kind: nodeGuideline, Document: "CCM", ID: "IVS-03"}, node, "referenced guidelines should be nodes")
+
+	node, err = graph.Resolve("2013 A.13.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, "ISO-27001:2013 A.13.1.1", node.Ref)
+
+	_, err = graph.Resolve("CCC.TH99")
+	assert.ErrorContains(t, err, `"CCC.TH99" is not a control, threat or guideline`)
+
+	graph.AddGuidanceDocument(&GuidanceDocument{Metadata: Metadata{ID: "SP-800-53"}, Guidelines: []Guideline{{ID: "SC-8"}}})
+	_, err = graph.Resolve("SC-8")
+	assert.ErrorContains(t, err, `"SC-8" is ambiguous: use one of NIST-800-53:SC-8, SP-800-53:SC-8`)
+}
+
+func TestMappingGraphDensePaths(t *testing.T) {
+	// Every control satisfies every guideline, so there are millions of paths of five
+	// mappings between two nodes. Only C00 -> G01 -> C01 -> G00 is made of strong mappings.
+	catalog := &ControlCatalog{Metadata: Metadata{ID: "DENSE"}}
+	for c := range 20 {
+		control := Control{ID: fmt.Sprintf("C%02d", c)}
+		mapping := MultiMapping{ReferenceID: "GUIDE"}
+		for g := range 20 {
+			strength := 3
+			if c <= 1 && g == 1 || c == 1 && g == 0 {
+				strength = 9
+			}
+			mapping.Entries = append(mapping.Entries, MappingEntry{ReferenceID: fmt.Sprintf("G%02d", g), Strength: strength})
+		}
+		control.GuidelineMappings = []MultiMapping{mapping}
+		catalog.Controls = append(catalog.Controls, control)
+	}
+	graph := NewMappingGraph()
+	graph.AddControlCatalog(catalog)
+
+	paths := graph.BestPaths("DENSE:C00", maxTraceHops, func(node MappingNode) bool { return node.Kind == nodeGuideline })
+	require.Len(t, paths, 20, "only the best path to each guideline should be returned")
+	assert.Equal(t, "GUIDE:G01", paths[0].Target.Ref)
+	assert.Equal(t, 9, paths[0].Weight)
+	assert.Equal(t, "GUIDE:G00", paths[1].Target.Ref)
+	assert.Equal(t, []string{"DENSE:C00", "GUIDE:G01", "DENSE:C01", "GUIDE:G00"}, paths[1].Nodes)
+	assert.Equal(t, 9, paths[1].Weight, "a stronger indirect path should win over the direct mapping")
+	assert.Equal(t, 3, paths[2].Weight)
+	assert.Len(t, paths[2].Hops, 1)
+
+	paths, truncated := graph.PathsTo("DENSE:C00", "GUIDE:G00", maxTraceHops, maxTracePaths)
+	assert.True(t, truncated, "the search should stop at the path limit")
+	assert.Len(t, paths, maxTracePaths)
+	for _, path := range paths {
+		assert.LessOrEqual(t, len(path.Hops), maxTraceHops)
+		assert.Equal(t, "GUIDE:G00", path.Target.Ref)
+	}
+
+	paths, truncated = graph.PathsTo("DENSE:C00", "GUIDE:G00", 1, maxTracePaths)
+	assert.False(t, truncated)
+	require.Len(t, paths, 1)
+	assert.Equal(t, 3, paths[0].Weight)
+}
+
+func TestTraceMapping(t *testing.T) {
+	index := newTestMappingIndex(t)
+
+	targets := func(paths []MappingPath) []string {
+		refs := []string{}
+		for _, path := range paths {
+			refs = append(refs, path.Target.Ref)
+		}
+		return refs
+	}
+
+	tests := []struct {
+		name           string
+		input          InputTraceMapping
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputTraceMapping)
+	}{
+		{
+			name:  "controls covering a guideline",
+			input: InputTraceMapping{From: "NIST-800-53:SC-8", Kind: nodeControl},
+			validateOutput: func(t *testing.T, output OutputTraceMapping) {
+				assert.Equal(t, "Transmission Confidentiality and Integrity", output.From.Title)
+				require.Equal(t, []string{"FINOS-CCC:CCC.C01"}, targets(output.Paths))
+				assert.Equal(t, []MappingEdge{{From: "FINOS-CCC:CCC.C01", To: "NIST-800-53:SC-8", Relation: relationSatisfies, Strength: 7}}, output.Paths[0].Hops)
+				assert.Equal(t, 7, output.Paths[0].Weight)
+			},
+		},
+		{
+			name:  "guidelines a control satisfies",
+			input: InputTraceMapping{From: "CCC.C01", Kind: nodeGuideline},
+			validateOutput: func(t *testing.T, output OutputTraceMapping) {
+				assert.Equal(t, []string{
+					"CCM:IVS-03", "CCM:IVS-07", "CSF:PR.DS-02", "ISO-27001:2013 A.13.1.1", "NIST-800-53:SC-13", "NIST-800-53:SC-8",
+				}, targets(output.Paths))
+			},
+		},
+		{
+			name:  "all neighbours",
+			input: InputTraceMapping{From: "CCC:CCC.TH04"},
+			validateOutput: func(t *testing.T, output OutputTraceMapping) {
+				assert.Equal(t, []string{"MITRE-ATTACK:T1537", "FINOS-CCC:CCC.C09", "FINOS-CCC:CCC.C10"}, targets(output.Paths), "stronger mappings should come first")
+				assert.Equal(t, relationMapsTo, output.Paths[0].Hops[0].Relation)
+			},
+		},
+		{
+			name:  "guidelines reachable from a threat",
+			input: InputTraceMapping{From: "CCC.TH02", Kind: nodeGuideline, MaxHops: 3},
+			validateOutput: func(t *testing.T, output OutputTraceMapping) {
+				assert.Contains(t, targets(output.Paths), "NIST-800-53:SC-8")
+				assert.Contains(t, targets(output.Paths), "ISO-27001:2013 A.13.2.1", "guideline mappings of guidance documents should be followed")
+				last := output.Paths[len(output.Paths)-1]
+				assert.Equal(t, []string{"CCC:CCC.TH02", "FINOS-CCC:CCC.C01", "NIST-800-53:SC-8", "ISO-27001:2013 A.13.2.1"}, last.Nodes)
+				assert.Equal(t, 7, last.Weight)
+			},
+		},
+		{
+			name:  "paths between two nodes",
+			input: InputTraceMapping{From: "CCC:CCC.TH02", To: "NIST-800-53:SC-8"},
+			validateOutput: func(t *testing.T, output OutputTraceMapping) {
+				require.NotNil(t, output.To)
+				assert.True(t, output.To.Loaded)
+				require.Len(t, output.Paths, 1)
+				assert.Equal(t, []MappingEdge{
+					{From: "FINOS-CCC:CCC.C01", To: "CCC:CCC.TH02", Relation: relationMitigates, Strength: 7, Remarks: "Data is Intercepted in Transit"},
+					{From: "FINOS-CCC:CCC.C01", To: "NIST-800-53:SC-8", Relation: relationSatisfies, Strength: 7},
+				}, output.Paths[0].Hops)
+			},
+		},
+		{
+			name:  "no path within the hop limit",
+			input: InputTraceMapping{From: "CCC:CCC.TH02", To: "NIST-800-53:SC-8", MaxHops: 1},
+			validateOutput: func(t *testing.T, output OutputTraceMapping) {
+				assert.Empty(t, output.Paths)
+			},
+		},
+		{
+			name:        "missing start",
+			input:       InputTraceMapping{},
+			wantErr:     true,
+			errContains: "from is required",
+		},
+		{
+			name:        "unknown node",
+			input:       InputTraceMapping{From: "CCC.C01", To: "NIST-800-53:SC-99"},
+			wantErr:     true,
+			errContains: `"NIST-800-53:SC-99" is not a control, threat or guideline`,
+		},
+		{
+			name:        "unsupported kind",
+			input:       InputTraceMapping{From: "CCC.C01", Kind: "capability"},
+			wantErr:     true,
+			errContains: `unsupported kind "capability"`,
+		},
+		{
+			name:        "too many hops",
+			input:       InputTraceMapping{From: "CCC.C01", MaxHops: 9},
+			wantErr:     true,
+			errContains: "max_hops must be between 1 and 5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := TraceMapping(context.Background(), nil, tt.input, index)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			tt.validateOutput(t, output)
+		})
+	}
+}
+
+func TestTraceMappingEmptyWorkspace(t *testing.T) {
+	_, _, err := TraceMapping(context.Background(), nil, InputTraceMapping{From: "CCC.C01"}, NewWorkspaceIndex(newTestSchemaLoader(t)))
+	assert.ErrorContains(t, err, "no control catalogs, threat catalogs or guidance documents are indexed")
+}
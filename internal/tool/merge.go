@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MetadataMergeCatalogs describes the MergeCatalogs tool.
+var MetadataMergeCatalogs = &mcp.Tool{
+	Name: "merge_catalogs",
+	Description: "Overlay a local catalog onto a base Gemara control catalog, such as company-specific controls onto the FINOS CCC catalog, " +
+		"adding, replacing, patching and removing controls by ID, and validate the merged catalog against #ControlCatalog. " +
+		"The overlay may set metadata fields and the title, add or replace families by ID and list controls under 'add', 'replace', " +
+		"'patch' (fields replacing those of the base control; null removes a field) and 'remove' (control IDs). " +
+		"Conflicting operations fail with every conflict found. The merged catalog is returned, not written anywhere.",
+	InputSchema:  schemaFor[InputMergeCatalogs](),
+	OutputSchema: schemaFor[OutputMergeCatalogs](),
+	Annotations:  readOnlyAnnotations(true),
+}
+
+// InputMergeCatalogs is the input for the MergeCatalogs tool.
+type InputMergeCatalogs struct {
+	BaseContent    string `json:"base_content,omitempty" jsonschema:"YAML or JSON content of the base control catalog"`
+	BaseSource     string `json:"base_source,omitempty" jsonschema:"Location to load the base catalog from instead of base_content (e.g., 'oci://ghcr.io/finos/ccc:v1#catalog.yaml', 'https://...')"`
+	BaseID         string `json:"base_id,omitempty" jsonschema:"Metadata ID of the base catalog in the client's workspace instead of base_content"`
+	OverlayContent string `json:"overlay_content,omitempty" jsonschema:"YAML or JSON content of the overlay"`
+	OverlaySource  string `json:"overlay_source,omitempty" jsonschema:"Location to load the overlay from instead of overlay_content"`
+	OverlayID      string `json:"overlay_id,omitempty" jsonschema:"Metadata ID of the overlay in the client's workspace instead of overlay_content"`
+	Version        string `json:"version,omitempty" jsonschema:"Version of the Gemara module to validate the merged catalog against (default: 'latest')"`
+}
+
+// OutputMergeCatalogs is the output for the MergeCatalogs tool.
+type OutputMergeCatalogs struct {
+	CatalogID string `json:"catalog_id"`
+	Content   string `json:"content"`
+	// Controls counts the controls of the merged catalog.
+	Controls int `json:"controls"`
+	// Added, Replaced, Patched and Removed are the IDs of the controls each operation applied to.
+	Added    []string `json:"added"`
+	Replaced []string `json:"replaced"`
+	Patched  []string `json:"patched"`
+	Removed  []string `json:"removed"`
+	// Families are the IDs of the families the overlay added or replaced.
+	Families []string `json:"families,omitempty"`
+	// Validation is the result of validating the merged catalog against #ControlCatalog.
+	Validation    OutputValidateGemaraArtifact `json:"validation"`
+	BaseSource    string                       `json:"base_source,omitempty"`
+	OverlaySource string                       `json:"overlay_source,omitempty"`
+}
+
+// catalogOverlay is the set of changes an overlay makes to a base control catalog.
+type catalogOverlay struct {
+	// Metadata fields replace those of the base catalog; a null value removes the field.
+	Metadata yaml.MapSlice   `yaml:"metadata"`
+	Title    string          `yaml:"title"`
+	Families []yaml.MapSlice `yaml:"families"`
+	Controls struct {
+		Add     []yaml.MapSlice `yaml:"add"`
+		Replace []yaml.MapSlice `yaml:"replace"`
+		Patch   []yaml.MapSlice `yaml:"patch"`
+		Remove  []string        `yaml:"remove"`
+	} `yaml:"controls"`
+}
+
+// MergeCatalogs overlays a catalog onto a base control catalog and validates the result
+// against the schema version loaded by the specified schema loader.
+func MergeCatalogs(ctx context.Context, req *mcp.CallToolRequest, input InputMergeCatalogs, loader *SchemaLoader) (*mcp.CallToolResult, OutputMergeCatalogs, error) {
+	if input.BaseContent == "" {
+		return nil, OutputMergeCatalogs{}, fmt.Errorf("base_content is required")
+	}
+	if input.OverlayContent == "" {
+		return nil, OutputMergeCatalogs{}, fmt.Errorf("overlay_content is required")
+	}
+	if _, err := ParseControlCatalog([]byte(input.BaseContent)); err != nil {
+		return nil, OutputMergeCatalogs{}, fmt.Errorf("base: %w", err)
+	}
+	var base yaml.MapSlice
+	if err := yaml.UnmarshalWithOptions([]byte(input.BaseContent), &base, yaml.UseOrderedMap()); err != nil {
+		return nil, OutputMergeCatalogs{}, fmt.Errorf("base: failed to parse control catalog: %w", err)
+	}
+	var overlay catalogOverlay
+	if err := yaml.UnmarshalWithOptions([]byte(input.OverlayContent), &overlay, yaml.UseOrderedMap(), yaml.DisallowUnknownField()); err != nil {
+		return nil, OutputMergeCatalogs{}, fmt.Errorf("failed to parse overlay: %w", err)
+	}
+
+	output := OutputMergeCatalogs{
+		Added:    []string{},
+		Replaced: []string{},
+		Patched:  []string{},
+		Removed:  []string{},
+	}
+	merged, conflicts := output.merge(base, overlay)
+	if len(conflicts) > 0 {
+		metadata, _ := mapField(base, "metadata").(yaml.MapSlice)
+		return nil, OutputMergeCatalogs{}, fmt.Errorf("overlay conflicts with base catalog %s: %s", entryValue(metadata, "id"), strings.Join(conflicts, "; "))
+	}
+
+	content, err := yaml.MarshalWithOptions(merged, yaml.IndentSequence(true), yaml.UseLiteralStyleIfMultiline(true))
+	if err != nil {
+		return nil, OutputMergeCatalogs{}, fmt.Errorf("failed to encode merged catalog: %w", err)
+	}
+	output.Content = string(content)
+	metadata, _ := mapField(merged, "metadata").(yaml.MapSlice)
+	output.CatalogID = entryValue(metadata, "id")
+	controls, _ := mapField(merged, "controls").([]any)
+	output.Controls = len(controls)
+
+	_, validation, err := ValidateGemaraArtifact(ctx, req, InputValidateGemaraArtifact{
+		ArtifactContent: output.Content,
+		Definition:      "#ControlCatalog",
+		Version:         input.Version,
+	}, loader)
+	if err != nil {
+		return nil, OutputMergeCatalogs{}, err
+	}
+	output.Validation = validation
+	return nil, output, nil
+}
+
+// merge applies the overlay to the base catalog, recording the applied operations, and
+// returns the merged catalog with the conflicts found. Every operation is checked, so all
+// conflicts are reported at once.
+func (o *OutputMergeCatalogs) merge(base yaml.MapSlice, overlay catalogOverlay) (yaml.MapSlice, []string) {
+	var conflicts []string
+	merged := slices.Clone(base)
+
+	if len(overlay.Metadata) > 0 {
+		metadata, _ := mapField(merged, "metadata").(yaml.MapSlice)
+		merged = setField(merged, "metadata", patchFields(metadata, overlay.Metadata))
+	}
+	if overlay.Title != "" {
+		merged = setField(merged, "title", overlay.Title)
+	}
+
+	families, _ := mapField(merged, "families").([]any)
+	families = slices.Clone(families)
+	for _, family := range overlay.Families {
+		id := entryValue(family, "id")
+		if id == "" {
+			conflicts = append(conflicts, "families: a family has no id")
+			continue
+		}
+		if i := entryIndex(families, id); i >= 0 {
+			families[i] = family
+		} else {
+			families = append(families, family)
+		}
+		o.Families = append(o.Families, id)
+	}
+	if len(overlay.Families) > 0 {
+		merged = setField(merged, "families", families)
+	}
+
+	controls, _ := mapField(merged, "controls").([]any)
+	controls = slices.Clone(controls)
+	operations := make(map[string]string)
+	target := func(operation, id string) bool {
+		if id == "" {
+			conflicts = append(conflicts, operation+": a control has no id")
+			return false
+		}
+		if previous, ok := operations[id]; ok {
+			if previous == operation {
+				conflicts = append(conflicts, fmt.Sprintf("%s lists %s more than once", operation, id))
+			} else {
+				conflicts = append(conflicts, fmt.Sprintf("%s is targeted by both %s and %s", id, previous, operation))
+			}
+			return false
+		}
+		operations[id] = operation
+		exists := entryIndex(controls, id) >= 0
+		switch {
+		case operation == "add" && exists:
+			conflicts = append(conflicts, fmt.Sprintf("add %s: the base catalog already has this control", id))
+			return false
+		case operation != "add" && !exists:
+			conflicts = append(conflicts, fmt.Sprintf("%s %s: the base catalog has no such control", operation, id))
+			return false
+		}
+		return true
+	}
+
+	for _, control := range overlay.Controls.Replace {
+		if id := entryValue(control, "id"); target("replace", id) {
+			controls[entryIndex(controls, id)] = control
+			o.Replaced = append(o.Replaced, id)
+		}
+	}
+	for _, patch := range overlay.Controls.Patch {
+		if id := entryValue(patch, "id"); target("patch", id) {
+			i := entryIndex(controls, id)
+			controls[i] = patchFields(controls[i].(yaml.MapSlice), patch)
+			o.Patched = append(o.Patched, id)
+		}
+	}
+	for _, id := range overlay.Controls.Remove {
+		if target("remove", id) {
+			i := entryIndex(controls, id)
+			controls = slices.Delete(controls, i, i+1)
+			o.Removed = append(o.Removed, id)
+		}
+	}
+	for _, control := range overlay.Controls.Add {
+		if id := entryValue(control, "id"); target("add", id) {
+			controls = append(controls, control)
+			o.Added = append(o.Added, id)
+		}
+	}
+	merged = setField(merged, "controls", controls)
+
+	// Controls the overlay contributes must belong to a family of the merged catalog.
+	defined := make(map[string]bool, len(families))
+	for _, family := range families {
+		if family, ok := family.(yaml.MapSlice); ok {
+			defined[entryValue(family, "id")] = true
+		}
+	}
+	for _, control := range controls {
+		control, ok := control.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		id, family := entryValue(control, "id"), entryValue(control, "family")
+		if operation := operations[id]; operation != "" && family != "" && !defined[family] {
+			conflicts = append(conflicts, fmt.Sprintf("%s %s: family %s is not defined by either catalog", operation, id, family))
+		}
+	}
+	return merged, conflicts
+}
+
+// mapField returns the value of a field of a mapping, or nil.
+func mapField(m yaml.MapSlice, name string) any {
+	for _, item := range m {
+		if item.Key == name {
+			return item.Value
+		}
+	}
+	return nil
+}
+
+// setField returns the mapping with the value of a field replaced, or the field appended.
+func setField(m yaml.MapSlice, name string, value any) yaml.MapSlice {
+	for i, item := range m {
+		if item.Key == name {
+			m[i].Value = value
+			return m
+		}
+	}
+	return append(m, yaml.MapItem{Key: name, Value: value})
+}
+
+// patchFields returns a copy of the mapping with the fields of patch set; null fields are removed.
+func patchFields(m, patch yaml.MapSlice) yaml.MapSlice {
+	patched := slices.Clone(m)
+	for _, item := range patch {
+		if item.Value == nil {
+			patched = slices.DeleteFunc(patched, func(field yaml.MapItem) bool { return field.Key == item.Key })
+			continue
+		}
+		patched = setField(patched, fmt.Sprint(item.Key), item.Value)
+	}
+	return patched
+}
+
+// entryIndex returns the index of the entry with the given ID in a list, or -1.
+func entryIndex(entries []any, id string) int {
+	return slices.IndexFunc(entries, func(entry any) bool {
+		fields, ok := entry.(yaml.MapSlice)
+		return ok && entryValue(fields, "id") == id
+	})
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCatalogOverlay extends the FINOS CCC test catalog with a company-specific family and control.
+const testCatalogOverlay = `metadata:
+  id: ACME-CCC
+  version: "2026.1"
+title: ACME Cloud Control Catalog
+families:
+  - id: identity
+    title: Identity
+    description: Controls for the identities of workloads.
+controls:
+  add:
+    - id: ACME.C01
+      family: identity
+      title: Use Workload Identity Federation
+      objective: Workloads authenticate without long-lived keys.
+      assessment-requirements:
+        - id: ACME.C01.TR01
+          text: Service accounts MUST NOT have user-managed keys.
+          applicability: [tlp_clear, tlp_green]
+  replace:
+    - id: CCC.C06
+      family: data-protection
+      title: Deploy Only to Approved Regions
+      objective: Keep data in the regions approved by ACME.
+      assessment-requirements:
+        - id: ACME.C06.TR01
+          text: Deployments outside eu-west-1 MUST be refused.
+          applicability: [tlp_clear]
+  patch:
+    - id: CCC.C01
+      title: Require TLS 1.3
+      guideline-mappings: null
+  remove:
+    - CCC.C10
+`
+
+func TestMergeCatalogs(t *testing.T) {
+	loader := newTestSchemaLoader(t)
+	base := readTestCatalog(t)
+
+	tests := []struct {
+		name           string
+		input          InputMergeCatalogs
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputMergeCatalogs)
+	}{
+		{
+			name:  "overlay onto the base catalog",
+			input: InputMergeCatalogs{BaseContent: base, OverlayContent: testCatalogOverlay},
+			validateOutput: func(t *testing.T, output OutputMergeCatalogs) {
+				assert.Equal(t, "ACME-CCC", output.CatalogID)
+				assert.Equal(t, 5, output.Controls)
+				assert.Equal(t, []string{"ACME.C01"}, output.Added)
+				assert.Equal(t, []string{"CCC.C06"}, output.Replaced)
+				assert.Equal(t, []string{"CCC.C01"}, output.Patched)
+				assert.Equal(t, []string{"CCC.C10"}, output.Removed)
+				assert.Equal(t, []string{"identity"}, output.Families)
+				assert.True(t, output.Validation.Valid, "merged catalog should be valid: %v", output.Validation.Errors)
+
+				merged, err := ParseControlCatalog([]byte(output.Content))
+				require.NoError(t, err)
+				assert.Equal(t, "ACME Cloud Control Catalog", merged.Title)
+				assert.Equal(t, "2026.1", merged.Metadata.Version)
+				assert.Equal(t, "finos", merged.Metadata.Author.ID, "metadata the overlay does not set should be kept")
+				assert.Len(t, merged.Families, 2)
+
+				var ids []string
+				for _, control := range merged.Controls {
+					ids = append(ids, control.ID)
+				}
+				assert.Equal(t, []string{"CCC.C01", "CCC.C06", "CCC.C08", "CCC.C09", "ACME.C01"}, ids)
+
+				c01, _ := merged.Control("CCC.C01")
+				assert.Equal(t, "Require TLS 1.3", c01.Title)
+				assert.Contains(t, c01.Objective, "encrypted in transit", "fields the patch does not set should be kept")
+				assert.NotEmpty(t, c01.ThreatMappings)
+				assert.Empty(t, c01.GuidelineMappings, "null patch fields should be removed")
+
+				c06, _ := merged.Control("CCC.C06")
+				assert.Equal(t, "Deploy Only to Approved Regions", c06.Title)
+				require.Len(t, c06.AssessmentRequirements, 1)
+				assert.Equal(t, "ACME.C06.TR01", c06.AssessmentRequirements[0].ID)
+			},
+		},
+		{
+			name: "invalid merged catalog",
+			input: InputMergeCatalogs{BaseContent: base, OverlayContent: `controls:
+  patch:
+    - id: CCC.C08
+      objective: null
+`},
+			validateOutput: func(t *testing.T, output OutputMergeCatalogs) {
+				assert.Equal(t, []string{"CCC.C08"}, output.Patched)
+				assert.False(t, output.Validation.Valid)
+				assert.NotEmpty(t, output.Validation.Errors)
+			},
+		},
+		{
+			name: "conflicting operations",
+			input: InputMergeCatalogs{BaseContent: base, OverlayContent: `controls:
+  add:
+    - id: CCC.C01
+      family: data-protection
+      title: Duplicate
+      objective: Duplicate.
+      assessment-requirements: []
+    - id: ACME.C02
+      family: networking
+      title: Private Endpoints
+      objective: Services are reachable over private endpoints only.
+      assessment-requirements: []
+  patch:
+    - id: CCC.C99
+      title: Missing
+    - id: CCC.C08
+      title: Patched
+  remove:
+    - CCC.C08
+    - CCC.C09
+    - CCC.C09
+`},
+			wantErr: true,
+			errContains: "overlay conflicts with base catalog FINOS-CCC: " +
+				"patch CCC.C99: the base catalog has no such control; " +
+				"CCC.C08 is targeted by both patch and remove; " +
+				"remove lists CCC.C09 more than once; " +
+				"add CCC.C01: the base catalog already has this control; " +
+				"add ACME.C02: family networking is not defined by either catalog",
+		},
+		{
+			name:        "unknown overlay operation",
+			input:       InputMergeCatalogs{BaseContent: base, OverlayContent: "controls:\n  rename:\n    - CCC.C01\n"},
+			wantErr:     true,
+			errContains: "failed to parse overlay",
+		},
+		{
+			name:        "base is not a control catalog",
+			input:       InputMergeCatalogs{BaseContent: "owner: security-team\n", OverlayContent: testCatalogOverlay},
+			wantErr:     true,
+			errContains: "base: failed to parse control catalog",
+		},
+		{
+			name:        "missing overlay",
+			input:       InputMergeCatalogs{BaseContent: base},
+			wantErr:     true,
+			errContains: "overlay_content is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := MergeCatalogs(context.Background(), nil, tt.input, loader)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			assert.True(t, strings.HasPrefix(output.Content, "metadata:\n"), "merged catalog should keep the field order of the base")
+			tt.validateOutput(t, output)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/migration"
+)
+
+// MetadataMigrateGemaraArtifact describes the MigrateGemaraArtifact tool.
+var MetadataMigrateGemaraArtifact = &mcp.Tool{
+	Name:         "migrate_gemara_artifact",
+	Description:  "Upgrade a Gemara artifact YAML to a newer schema version by applying the registered migration steps, validate the result against the target version and return it with a unified diff. The artifact is not written anywhere.",
+	InputSchema:  schemaFor[InputMigrateGemaraArtifact](),
+	OutputSchema: schemaFor[OutputMigrateGemaraArtifact](),
+	Annotations:  readOnlyAnnotations(true),
+}
+
+// InputMigrateGemaraArtifact is the input for the MigrateGemaraArtifact tool.
+type InputMigrateGemaraArtifact struct {
+	ArtifactContent string `json:"artifact_content,omitempty" jsonschema:"YAML content of the Gemara artifact to migrate"`
+	ArtifactSource  string `json:"artifact_source,omitempty" jsonschema:"Location to load the artifact from instead of artifact_content (e.g., 'oci://ghcr.io/org/catalogs:v1#ccc.yaml', 'oci-layout://./layout:v1', 'https://...')"`
+	Definition      string `json:"definition" jsonschema:"CUE definition of the artifact (e.g., '#ControlCatalog')"`
+	From            string `json:"from" jsonschema:"Schema version the artifact currently conforms to (e.g., 'v0.1.0')"`
+	To              string `json:"to,omitempty" jsonschema:"Schema version to migrate to (default: 'latest')"`
+}
+
+// OutputMigrateGemaraArtifact is the output for the MigrateGemaraArtifact tool.
+type OutputMigrateGemaraArtifact struct {
+	From    string                  `json:"from"`
+	To      string                  `json:"to"`
+	Content string                  `json:"content"`
+	Diff    string                  `json:"diff"`
+	Applied []migration.AppliedStep `json:"applied"`
+	// Validation is the result of validating the migrated artifact against To.
+	Validation OutputValidateGemaraArtifact `json:"validation"`
+	Source     string                       `json:"source,omitempty"`
+}
+
+// MigrateGemaraArtifact upgrades an artifact with the specified migrator and validates the
+// result against the target version loaded by the specified schema loader.
+func MigrateGemaraArtifact(ctx context.Context, req *mcp.CallToolRequest, input InputMigrateGemaraArtifact, migrator *migration.Migrator, loader *SchemaLoader) (*mcp.CallToolResult, OutputMigrateGemaraArtifact, error) {
+	if input.ArtifactContent == "" {
+		return nil, OutputMigrateGemaraArtifact{}, fmt.Errorf("artifact_content is required")
+	}
+	if input.Definition == "" {
+		return nil, OutputMigrateGemaraArtifact{}, fmt.Errorf("definition is required")
+	}
+	if input.From == "" {
+		return nil, OutputMigrateGemaraArtifact{}, fmt.Errorf("from is required")
+	}
+
+	target, err := loader.Load(ctx, input.To, false)
+	if err != nil {
+		return nil, OutputMigrateGemaraArtifact{}, err
+	}
+
+	r := reporterFrom(ctx)
+	r.step(ctx, "migrating artifact from %s to %s", input.From, target.Version)
+	result, err := migrator.Migrate([]byte(input.ArtifactContent), input.Definition, input.From, target.Version)
+	if err != nil {
+		return nil, OutputMigrateGemaraArtifact{}, fmt.Errorf("failed to migrate artifact: %w", err)
+	}
+	for _, step := range result.Applied {
+		r.log(ctx, logInfo, "%s to %s: %s (%d changes)", step.From, step.To, step.Description, step.Changes)
+	}
+	content := string(result.Content)
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(input.ArtifactContent),
+		B:        difflib.SplitLines(content),
+		FromFile: "artifact@" + input.From,
+		ToFile:   "artifact@" + target.Version,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, OutputMigrateGemaraArtifact{}, fmt.Errorf("failed to diff migrated artifact: %w", err)
+	}
+
+	_, validation, err := ValidateGemaraArtifact(ctx, req, InputValidateGemaraArtifact{
+		ArtifactContent: content,
+		Definition:      input.Definition,
+		Version:         target.Version,
+	}, loader)
+	if err != nil {
+		return nil, OutputMigrateGemaraArtifact{}, err
+	}
+
+	return nil, OutputMigrateGemaraArtifact{
+		From:       input.From,
+		To:         target.Version,
+		Content:    content,
+		Diff:       diff,
+		Applied:    result.Applied,
+		Validation: validation,
+	}, nil
+}
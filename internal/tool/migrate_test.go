@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/migration"
+)
+
+// threatCatalogV010 is valid against v0.1.0 of the stand-in schema but not against v0.2.0.
+const threatCatalogV010 = `title: Object Storage Threats
+metadata:
+  id: OS-THREATS
+  description: Threats to object storage services.
+  author:
+    id: finos
+    name: FINOS
+    uri: https://finos.org
+threats:
+  - id: OS.TH01
+    title: Data Exfiltration
+    description: Data is read by an unauthorized party.
+    external-mappings:
+      - reference-id: MITRE
+        entries:
+          - reference-id: T1530
+            strength: 0
+`
+
+// standInMigrations upgrade artifacts across the breaking changes of the stand-in schema.
+var standInMigrations = []migration.Migration{{
+	From: "v0.1.0",
+	To:   "v0.2.0",
+	Steps: []migration.Step{
+		migration.RemoveField("metadata.author", "uri"),
+		migration.SetDefault("threats[*]", "capabilities", []string{}).For("#ThreatCatalog"),
+		migration.ReplaceValue("threats[*].external-mappings[*].entries[*]", "strength", 0, 1).For("#ThreatCatalog"),
+	},
+}}
+
+func TestMigrateGemaraArtifact(t *testing.T) {
+	loader := newTestSchemaLoader(t)
+
+	tests := []struct {
+		name           string
+		migrations     []migration.Migration
+		input          InputMigrateGemaraArtifact
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputMigrateGemaraArtifact)
+	}{
+		{
+			name:       "upgrade to latest",
+			migrations: standInMigrations,
+			input: InputMigrateGemaraArtifact{
+				ArtifactContent: threatCatalogV010,
+				Definition:      "ThreatCatalog",
+				From:            "v0.1.0",
+			},
+			validateOutput: func(t *testing.T, output OutputMigrateGemaraArtifact) {
+				assert.Equal(t, "v0.2.0", output.To, "to should default to the latest version")
+				assert.Len(t, output.Applied, 3)
+				assert.True(t, output.Validation.Valid, "migrated artifact should be valid: %v", output.Validation.Errors)
+				assert.Equal(t, "v0.2.0", output.Validation.Version)
+
+				assert.NotContains(t, output.Content, "uri:")
+				assert.Contains(t, output.Content, "    capabilities: []\n")
+				assert.Contains(t, output.Diff, "--- artifact@v0.1.0\n+++ artifact@v0.2.0\n")
+				assert.Contains(t, output.Diff, "\n-    uri: https://finos.org\n")
+				assert.Contains(t, output.Diff, "\n-            strength: 0\n+            strength: 1\n")
+			},
+		},
+		{
+			name: "incomplete migration fails validation",
+			migrations: []migration.Migration{{
+				From:  "v0.1.0",
+				To:    "v0.2.0",
+				Steps: []migration.Step{migration.RemoveField("metadata.author", "uri")},
+			}},
+			input: InputMigrateGemaraArtifact{
+				ArtifactContent: threatCatalogV010,
+				Definition:      "#ThreatCatalog",
+				From:            "v0.1.0",
+				To:              "v0.2.0",
+			},
+			validateOutput: func(t *testing.T, output OutputMigrateGemaraArtifact) {
+				assert.Len(t, output.Applied, 1)
+				assert.False(t, output.Validation.Valid, "artifact missing required changes should not validate")
+				assert.NotEmpty(t, output.Validation.Errors)
+			},
+		},
+		{
+			name:       "no migration path",
+			migrations: nil,
+			input: InputMigrateGemaraArtifact{
+				ArtifactContent: threatCatalogV010,
+				Definition:      "#ThreatCatalog",
+				From:            "v0.1.0",
+			},
+			wantErr:     true,
+			errContains: "no migration path from v0.1.0 to v0.2.0",
+		},
+		{
+			name:       "missing from",
+			migrations: standInMigrations,
+			input: InputMigrateGemaraArtifact{
+				ArtifactContent: threatCatalogV010,
+				Definition:      "#ThreatCatalog",
+			},
+			wantErr:     true,
+			errContains: "from is required",
+		},
+		{
+			name:       "invalid YAML",
+			migrations: standInMigrations,
+			input: InputMigrateGemaraArtifact{
+				ArtifactContent: "title: [\n",
+				Definition:      "#ThreatCatalog",
+				From:            "v0.1.0",
+			},
+			wantErr:     true,
+			errContains: "failed to parse YAML",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := MigrateGemaraArtifact(context.Background(), nil, tt.input, migration.NewMigrator(tt.migrations...), loader)
+
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			if tt.validateOutput != nil {
+				tt.validateOutput(t, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// Document is a YAML artifact being migrated. Edits are applied to its syntax tree,
+// so comments and formatting outside the edited fields are preserved.
+type Document struct {
+	file *ast.File
+}
+
+// ParseDocument parses a single YAML document whose root is a mapping.
+func ParseDocument(data []byte) (*Document, error) {
+	file, err := parser.ParseBytes(data, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if len(file.Docs) != 1 {
+		return nil, fmt.Errorf("expected a single YAML document, found %d", len(file.Docs))
+	}
+
+	doc := &Document{file: file}
+	if _, err := doc.Mappings(""); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Bytes renders the document.
+func (d *Document) Bytes() []byte {
+	out := d.file.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	return []byte(out)
+}
+
+// Mappings returns the mappings selected by path. A path is a dot-separated list of
+// field names from the document root, where a "[*]" suffix selects every item of a
+// list, e.g. "controls[*].assessment-requirements[*]". The empty path selects the root.
+// Fields along the path that are missing select nothing.
+func (d *Document) Mappings(path string) ([]*Mapping, error) {
+	doc := d.file.Docs[0]
+	root, err := asMapping(doc.Body, func(n ast.Node) { doc.Body = n })
+	if err != nil {
+		return nil, fmt.Errorf("document root: %w", err)
+	}
+	mappings := []*Mapping{root}
+	if path == "" {
+		return mappings, nil
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		name, each := strings.CutSuffix(segment, "[*]")
+		var next []*Mapping
+		for _, m := range mappings {
+			field := m.field(name)
+			if field == nil {
+				continue
+			}
+			if !each {
+				child, err := asMapping(field.Value, func(n ast.Node) { field.Value = n })
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", segment, err)
+				}
+				next = append(next, child)
+				continue
+			}
+
+			seq, ok := field.Value.(*ast.SequenceNode)
+			if !ok {
+				return nil, fmt.Errorf("%s: expected a list, found %s", segment, field.Value.Type())
+			}
+			for i := range seq.Values {
+				child, err := asMapping(seq.Values[i], func(n ast.Node) { seq.Values[i] = n })
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", segment, err)
+				}
+				next = append(next, child)
+			}
+		}
+		mappings = next
+	}
+	return mappings, nil
+}
+
+// asMapping returns node as a mapping. A mapping with a single field is parsed as a
+// bare key/value node, which is wrapped and stored back with replace so fields can be
+// added to it.
+func asMapping(node ast.Node, replace func(ast.Node)) (*Mapping, error) {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		if n.IsFlowStyle {
+			return nil, fmt.Errorf("flow-style mappings are not supported")
+		}
+		return &Mapping{node: n}, nil
+	case *ast.MappingValueNode:
+		m := ast.Mapping(n.GetToken().Clone(), false, n)
+		replace(m)
+		return &Mapping{node: m}, nil
+	case nil:
+		return nil, fmt.Errorf("expected a mapping, found an empty document")
+	}
+	return nil, fmt.Errorf("expected a mapping, found %s", node.Type())
+}
+
+// Mapping is a YAML mapping within a Document.
+type Mapping struct {
+	node *ast.MappingNode
+}
+
+func (m *Mapping) field(name string) *ast.MappingValueNode {
+	for _, value := range m.node.Values {
+		if keyName(value) == name {
+			return value
+		}
+	}
+	return nil
+}
+
+func keyName(value *ast.MappingValueNode) string {
+	if key, ok := value.Key.(*ast.StringNode); ok {
+		return key.Value
+	}
+	return value.Key.String()
+}
+
+// Has reports whether the mapping contains the field.
+func (m *Mapping) Has(name string) bool {
+	return m.field(name) != nil
+}
+
+// Get decodes the value of a field into v and reports whether the field exists.
+func (m *Mapping) Get(name string, v any) (bool, error) {
+	field := m.field(name)
+	if field == nil {
+		return false, nil
+	}
+	if err := yaml.NodeToValue(field.Value, v); err != nil {
+		return true, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// Rename renames a field, keeping its value and position. It reports whether the field
+// was found; renaming to the name of an existing field is an error.
+func (m *Mapping) Rename(from, to string) (bool, error) {
+	field := m.field(from)
+	if field == nil {
+		return false, nil
+	}
+	if m.Has(to) {
+		return false, fmt.Errorf("cannot rename %s to %s: field already exists", from, to)
+	}
+	key, ok := field.Key.(*ast.StringNode)
+	if !ok {
+		return false, fmt.Errorf("cannot rename %s: unsupported key type %s", from, field.Key.Type())
+	}
+	// The printer renders the token value; the origin keeps the source text, including
+	// any trivia around the key, and is left alone.
+	key.Value = to
+	key.Token.Value = to
+	return true, nil
+}
+
+// Move moves a field to another place within the mapping. Both names are dot-separated
+// field paths relative to the mapping, so a field can be nested ("family" to
+// "classification.family") or lifted out of a nested mapping. Mappings missing along the
+// destination path are created. It reports whether the field was found; moving onto an
+// existing field is an error. The moved value is re-encoded, so comments inside it are lost.
+func (m *Mapping) Move(from, to string) (bool, error) {
+	if from == to || strings.HasPrefix(to, from+".") {
+		return false, fmt.Errorf("cannot move %s to %s", from, to)
+	}
+	source, name, err := m.parent(from)
+	if err != nil || source == nil {
+		return false, err
+	}
+	field := source.field(name)
+	if field == nil {
+		return false, nil
+	}
+	var value any
+	if err := yaml.NodeToValue(field.Value, &value, yaml.UseOrderedMap()); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", from, err)
+	}
+
+	// Walk the destination path as far as it exists and nest the value in new mappings
+	// for the rest.
+	target := m
+	segments := strings.Split(to, ".")
+	for i, segment := range segments[:len(segments)-1] {
+		child := target.field(segment)
+		if child == nil {
+			for j := len(segments) - 1; j > i; j-- {
+				value = yaml.MapSlice{{Key: segments[j], Value: value}}
+			}
+			segments = segments[:i+1]
+			break
+		}
+		target, err = asMapping(child.Value, func(n ast.Node) { child.Value = n })
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", segment, err)
+		}
+	}
+	last := segments[len(segments)-1]
+	if target.Has(last) {
+		return false, fmt.Errorf("cannot move %s to %s: field already exists", from, to)
+	}
+	if err := target.Set(last, value); err != nil {
+		return false, err
+	}
+	source.Remove(name)
+	return true, nil
+}
+
+// parent returns the mapping holding the field at a dot-separated path and the field's
+// name, or a nil mapping when a mapping along the path is missing.
+func (m *Mapping) parent(path string) (*Mapping, string, error) {
+	segments := strings.Split(path, ".")
+	current := m
+	for _, segment := range segments[:len(segments)-1] {
+		field := current.field(segment)
+		if field == nil {
+			return nil, "", nil
+		}
+		child, err := asMapping(field.Value, func(n ast.Node) { field.Value = n })
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %w", segment, err)
+		}
+		current = child
+	}
+	return current, segments[len(segments)-1], nil
+}
+
+// Remove deletes a field and reports whether it was found.
+func (m *Mapping) Remove(name string) bool {
+	for i, value := range m.node.Values {
+		if keyName(value) == name {
+			m.node.Values = append(m.node.Values[:i], m.node.Values[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Set replaces the value of a field, or appends the field if it does not exist.
+func (m *Mapping) Set(name string, v any) error {
+	field := m.field(name)
+	column := m.node.Start.Position.Column
+	switch {
+	case field != nil:
+		column = field.Key.GetToken().Position.Column
+	case len(m.node.Values) > 0:
+		column = m.node.Values[0].Key.GetToken().Position.Column
+	}
+
+	created, err := fieldNode(name, v, column)
+	if err != nil {
+		return err
+	}
+	if field != nil {
+		field.Value = created.Value
+		return nil
+	}
+	m.node.Values = append(m.node.Values, created)
+	return nil
+}
+
+// fieldNode encodes a field with its key at the given column. The field is rendered at
+// its final indentation so multi-line values keep their layout when printed.
+func fieldNode(name string, v any, column int) (*ast.MappingValueNode, error) {
+	data, err := yaml.MarshalWithOptions(map[string]any{name: v}, yaml.IndentSequence(true), yaml.UseLiteralStyleIfMultiline(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	indent := strings.Repeat(" ", column-1)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+
+	file, err := parser.ParseBytes([]byte(strings.Join(lines, "\n")+"\n"), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	switch n := file.Docs[0].Body.(type) {
+	case *ast.MappingValueNode:
+		return n, nil
+	case *ast.MappingNode:
+		return n.Values[0], nil
+	default:
+		return nil, fmt.Errorf("failed to encode %s: unexpected %s", name, n.Type())
+	}
+}
@@ -0,0 +1,327 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleCatalog = `# Catalog maintained by the platform team.
+title: Example Catalog
+metadata:
+  id: EX
+  description: |
+    Controls for the example platform.
+  author:
+    id: platform
+    name: Platform Team
+    uri: https://example.com
+controls:
+  - id: EX.C01
+    family: data
+
+    # Requirements are reviewed quarterly.
+    assessment-requirements:
+      - id: EX.C01.TR01
+        text: Encrypt data at rest.
+  - id: EX.C02
+`
+
+func TestDocumentEdits(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		edit    func(t *testing.T, m *Mapping)
+		want    string
+		wantErr string
+	}{
+		{
+			name: "rename keeps comments and blank lines",
+			path: "controls[*]",
+			edit: func(t *testing.T, m *Mapping) {
+				_, err := m.Rename("family", "family-id")
+				require.NoError(t, err)
+			},
+			want: `# Catalog maintained by the platform team.
+title: Example Catalog
+metadata:
+  id: EX
+  description: |
+    Controls for the example platform.
+  author:
+    id: platform
+    name: Platform Team
+    uri: https://example.com
+controls:
+  - id: EX.C01
+    family-id: data
+
+    # Requirements are reviewed quarterly.
+    assessment-requirements:
+      - id: EX.C01.TR01
+        text: Encrypt data at rest.
+  - id: EX.C02
+`,
+		},
+		{
+			name: "remove",
+			path: "metadata.author",
+			edit: func(t *testing.T, m *Mapping) {
+				assert.True(t, m.Remove("uri"))
+				assert.False(t, m.Remove("uri"), "second removal should find nothing")
+			},
+			want: `# Catalog maintained by the platform team.
+title: Example Catalog
+metadata:
+  id: EX
+  description: |
+    Controls for the example platform.
+  author:
+    id: platform
+    name: Platform Team
+controls:
+  - id: EX.C01
+    family: data
+
+    # Requirements are reviewed quarterly.
+    assessment-requirements:
+      - id: EX.C01.TR01
+        text: Encrypt data at rest.
+  - id: EX.C02
+`,
+		},
+		{
+			name: "set on single-field mapping and multi-line value",
+			path: "controls[*]",
+			edit: func(t *testing.T, m *Mapping) {
+				var id string
+				_, err := m.Get("id", &id)
+				require.NoError(t, err)
+				if id == "EX.C02" {
+					require.NoError(t, m.Set("objective", "Limit access.\nReview grants.\n"))
+					require.NoError(t, m.Set("tags", []string{"access"}))
+				}
+			},
+			want: `# Catalog maintained by the platform team.
+title: Example Catalog
+metadata:
+  id: EX
+  description: |
+    Controls for the example platform.
+  author:
+    id: platform
+    name: Platform Team
+    uri: https://example.com
+controls:
+  - id: EX.C01
+    family: data
+
+    # Requirements are reviewed quarterly.
+    assessment-requirements:
+      - id: EX.C01.TR01
+        text: Encrypt data at rest.
+  - id: EX.C02
+    objective: |
+      Limit access.
+      Review grants.
+    tags:
+      - access
+`,
+		},
+		{
+			name: "replace value",
+			path: "controls[*].assessment-requirements[*]",
+			edit: func(t *testing.T, m *Mapping) {
+				require.NoError(t, m.Set("text", "Encrypt all data at rest."))
+			},
+			want: `# Catalog maintained by the platform team.
+title: Example Catalog
+metadata:
+  id: EX
+  description: |
+    Controls for the example platform.
+  author:
+    id: platform
+    name: Platform Team
+    uri: https://example.com
+controls:
+  - id: EX.C01
+    family: data
+
+    # Requirements are reviewed quarterly.
+    assessment-requirements:
+      - id: EX.C01.TR01
+        text: Encrypt all data at rest.
+  - id: EX.C02
+`,
+		},
+		{
+			name: "rename a key after a comment",
+			path: "controls[*]",
+			edit: func(t *testing.T, m *Mapping) {
+				_, err := m.Rename("assessment-requirements", "requirements")
+				require.NoError(t, err)
+			},
+			want: `# Catalog maintained by the platform team.
+title: Example Catalog
+metadata:
+  id: EX
+  description: |
+    Controls for the example platform.
+  author:
+    id: platform
+    name: Platform Team
+    uri: https://example.com
+controls:
+  - id: EX.C01
+    family: data
+
+    # Requirements are reviewed quarterly.
+    requirements:
+      - id: EX.C01.TR01
+        text: Encrypt data at rest.
+  - id: EX.C02
+`,
+		},
+		{
+			name: "move into a new nested mapping",
+			path: "controls[*]",
+			edit: func(t *testing.T, m *Mapping) {
+				_, err := m.Move("family", "classification.family")
+				require.NoError(t, err)
+			},
+			want: `# Catalog maintained by the platform team.
+title: Example Catalog
+metadata:
+  id: EX
+  description: |
+    Controls for the example platform.
+  author:
+    id: platform
+    name: Platform Team
+    uri: https://example.com
+controls:
+  - id: EX.C01
+
+    # Requirements are reviewed quarterly.
+    assessment-requirements:
+      - id: EX.C01.TR01
+        text: Encrypt data at rest.
+    classification:
+      family: data
+  - id: EX.C02
+`,
+		},
+		{
+			name: "move out of a nested mapping",
+			path: "metadata",
+			edit: func(t *testing.T, m *Mapping) {
+				moved, err := m.Move("author.uri", "homepage")
+				require.NoError(t, err)
+				assert.True(t, moved)
+				moved, err = m.Move("author.email", "contact")
+				require.NoError(t, err)
+				assert.False(t, moved, "missing fields should not be moved")
+			},
+			want: `# Catalog maintained by the platform team.
+title: Example Catalog
+metadata:
+  id: EX
+  description: |
+    Controls for the example platform.
+  author:
+    id: platform
+    name: Platform Team
+  homepage: https://example.com
+controls:
+  - id: EX.C01
+    family: data
+
+    # Requirements are reviewed quarterly.
+    assessment-requirements:
+      - id: EX.C01.TR01
+        text: Encrypt data at rest.
+  - id: EX.C02
+`,
+		},
+		{
+			name: "move onto existing field",
+			path: "metadata",
+			edit: func(t *testing.T, m *Mapping) {
+				_, err := m.Move("author", "id")
+				assert.ErrorContains(t, err, "field already exists")
+				_, err = m.Move("author", "author.previous")
+				assert.Error(t, err, "a field cannot be moved into itself")
+			},
+			want: sampleCatalog,
+		},
+		{
+			name: "rename onto existing field",
+			path: "metadata",
+			edit: func(t *testing.T, m *Mapping) {
+				_, err := m.Rename("id", "description")
+				assert.ErrorContains(t, err, "field already exists")
+			},
+			want: sampleCatalog,
+		},
+		{
+			name:    "path through a scalar",
+			path:    "title.id",
+			wantErr: "title: expected a mapping",
+		},
+		{
+			name:    "wildcard on a mapping",
+			path:    "metadata[*]",
+			wantErr: "expected a list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ParseDocument([]byte(sampleCatalog))
+			require.NoError(t, err)
+
+			mappings, err := doc.Mappings(tt.path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, mappings, "path should select mappings")
+			for _, m := range mappings {
+				tt.edit(t, m)
+			}
+			assert.Equal(t, tt.want, string(doc.Bytes()))
+		})
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "mapping", content: "title: x\n"},
+		{name: "list root", content: "- a\n", wantErr: "expected a mapping"},
+		{name: "multiple documents", content: "a: 1\n---\nb: 2\n", wantErr: "single YAML document"},
+		{name: "flow mapping", content: "{a: 1}\n", wantErr: "flow-style"},
+		{name: "invalid YAML", content: "a: [\n", wantErr: "failed to parse YAML"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDocument([]byte(tt.content))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
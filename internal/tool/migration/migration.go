@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package migration upgrades Gemara artifacts between schema versions by applying
+// versioned transformation steps to their YAML documents.
+package migration
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Step is a single transformation of an artifact.
+type Step struct {
+	// Description explains the change in terms of the schema.
+	Description string
+	// Definitions limits the step to artifacts of these root definitions. A step
+	// without definitions applies to all artifacts.
+	Definitions []string
+	// Apply edits the document and returns the number of changes it made.
+	Apply func(doc *Document) (int, error)
+}
+
+// For limits the step to artifacts of the given root definitions.
+func (s Step) For(definitions ...string) Step {
+	s.Definitions = make([]string, len(definitions))
+	for i, definition := range definitions {
+		s.Definitions[i] = normalizeDefinition(definition)
+	}
+	return s
+}
+
+func (s Step) appliesTo(definition string) bool {
+	return len(s.Definitions) == 0 || slices.Contains(s.Definitions, normalizeDefinition(definition))
+}
+
+// Migration upgrades artifacts from one schema version to the next.
+type Migration struct {
+	From  string
+	To    string
+	Steps []Step
+}
+
+// AppliedStep records a step that changed an artifact.
+type AppliedStep struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Description string `json:"description"`
+	Changes     int    `json:"changes"`
+}
+
+// Result is a migrated artifact.
+type Result struct {
+	Content []byte
+	// Applied lists the steps that changed the artifact, in order.
+	Applied []AppliedStep
+}
+
+// Migrator upgrades artifacts using a set of migrations.
+type Migrator struct {
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator from migrations between consecutive schema versions.
+func NewMigrator(migrations ...Migration) *Migrator {
+	return &Migrator{migrations: migrations}
+}
+
+// Plan returns the chain of migrations that upgrades artifacts from one version to another.
+func (m *Migrator) Plan(from, to string) ([]Migration, error) {
+	for _, v := range []string{from, to} {
+		if !semver.IsValid(v) {
+			return nil, fmt.Errorf("invalid schema version %q", v)
+		}
+	}
+	if semver.Compare(from, to) > 0 {
+		return nil, fmt.Errorf("cannot migrate from %s to older version %s", from, to)
+	}
+
+	var plan []Migration
+	for current := from; current != to; {
+		next, ok := m.next(current, to)
+		if !ok {
+			return nil, fmt.Errorf("no migration path from %s to %s: no migration from %s", from, to, current)
+		}
+		plan = append(plan, next)
+		current = next.To
+	}
+	return plan, nil
+}
+
+// next returns the migration from version that advances furthest without passing target.
+func (m *Migrator) next(version, target string) (Migration, bool) {
+	var (
+		best  Migration
+		found bool
+	)
+	for _, migration := range m.migrations {
+		if migration.From != version || semver.Compare(migration.To, target) > 0 || semver.Compare(migration.To, version) <= 0 {
+			continue
+		}
+		if !found || semver.Compare(migration.To, best.To) > 0 {
+			best, found = migration, true
+		}
+	}
+	return best, found
+}
+
+// Migrate upgrades an artifact of the given root definition from one version to another.
+func (m *Migrator) Migrate(content []byte, definition, from, to string) (*Result, error) {
+	plan, err := m.Plan(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := ParseDocument(content)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Applied: []AppliedStep{}}
+	for _, migration := range plan {
+		for _, step := range migration.Steps {
+			if !step.appliesTo(definition) {
+				continue
+			}
+			changes, err := step.Apply(doc)
+			if err != nil {
+				return nil, fmt.Errorf("%s to %s: %s: %w", migration.From, migration.To, step.Description, err)
+			}
+			if changes > 0 {
+				result.Applied = append(result.Applied, AppliedStep{
+					From:        migration.From,
+					To:          migration.To,
+					Description: step.Description,
+					Changes:     changes,
+				})
+			}
+		}
+	}
+
+	result.Content = content
+	if len(result.Applied) > 0 {
+		result.Content = doc.Bytes()
+	}
+	return result, nil
+}
+
+// RenameField renames a field in every mapping selected by path.
+func RenameField(path, from, to string) Step {
+	return Step{
+		Description: fmt.Sprintf("rename %s to %s", fieldPath(path, from), to),
+		Apply: func(doc *Document) (int, error) {
+			return eachMapping(doc, path, func(m *Mapping) (bool, error) {
+				return m.Rename(from, to)
+			})
+		},
+	}
+}
+
+// RemoveField deletes a field from every mapping selected by path.
+func RemoveField(path, name string) Step {
+	return Step{
+		Description: fmt.Sprintf("remove %s", fieldPath(path, name)),
+		Apply: func(doc *Document) (int, error) {
+			return eachMapping(doc, path, func(m *Mapping) (bool, error) {
+				return m.Remove(name), nil
+			})
+		},
+	}
+}
+
+// MoveField moves a field within every mapping selected by path. from and to are
+// dot-separated field paths relative to the selected mappings, so the step can nest a field
+// in a new mapping, lift it out of one or move it between nested mappings.
+func MoveField(path, from, to string) Step {
+	return Step{
+		Description: fmt.Sprintf("move %s to %s", fieldPath(path, from), fieldPath(path, to)),
+		Apply: func(doc *Document) (int, error) {
+			return eachMapping(doc, path, func(m *Mapping) (bool, error) {
+				return m.Move(from, to)
+			})
+		},
+	}
+}
+
+// SetDefault adds a field with value to every mapping selected by path that lacks it.
+func SetDefault(path, name string, value any) Step {
+	return Step{
+		Description: fmt.Sprintf("set missing %s to %v", fieldPath(path, name), value),
+		Apply: func(doc *Document) (int, error) {
+			return eachMapping(doc, path, func(m *Mapping) (bool, error) {
+				if m.Has(name) {
+					return false, nil
+				}
+				return true, m.Set(name, value)
+			})
+		},
+	}
+}
+
+// ReplaceValue replaces a field's value where it equals old in every mapping selected by path.
+func ReplaceValue(path, name string, old, value any) Step {
+	return Step{
+		Description: fmt.Sprintf("replace %s %v with %v", fieldPath(path, name), old, value),
+		Apply: func(doc *Document) (int, error) {
+			return eachMapping(doc, path, func(m *Mapping) (bool, error) {
+				var current any
+				found, err := m.Get(name, &current)
+				if err != nil || !found || !equalValues(current, old) {
+					return false, err
+				}
+				return true, m.Set(name, value)
+			})
+		},
+	}
+}
+
+// eachMapping applies edit to every mapping selected by path and counts the edits.
+func eachMapping(doc *Document, path string, edit func(*Mapping) (bool, error)) (int, error) {
+	mappings, err := doc.Mappings(path)
+	if err != nil {
+		return 0, err
+	}
+	var changes int
+	for _, m := range mappings {
+		changed, err := edit(m)
+		if err != nil {
+			return changes, err
+		}
+		if changed {
+			changes++
+		}
+	}
+	return changes, nil
+}
+
+// equalValues compares a decoded YAML value with a Go value, ignoring numeric type differences.
+func equalValues(decoded, want any) bool {
+	dv, wv := reflect.ValueOf(decoded), reflect.ValueOf(want)
+	if dv.CanInt() && wv.CanInt() {
+		return dv.Int() == wv.Int()
+	}
+	if dv.CanUint() && wv.CanUint() {
+		return dv.Uint() == wv.Uint()
+	}
+	if (dv.CanInt() || dv.CanUint()) && (wv.CanInt() || wv.CanUint()) {
+		return fmt.Sprint(decoded) == fmt.Sprint(want)
+	}
+	return reflect.DeepEqual(decoded, want)
+}
+
+func fieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func normalizeDefinition(definition string) string {
+	if !strings.HasPrefix(definition, "#") {
+		return "#" + definition
+	}
+	return definition
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	migrator := NewMigrator(
+		Migration{From: "v0.1.0", To: "v0.2.0"},
+		Migration{From: "v0.2.0", To: "v0.3.0"},
+		Migration{From: "v0.3.0", To: "v0.3.1"},
+		Migration{From: "v0.2.0", To: "v0.3.1"},
+	)
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		want    []string
+		wantErr string
+	}{
+		{name: "single step", from: "v0.1.0", to: "v0.2.0", want: []string{"v0.2.0"}},
+		{name: "chained", from: "v0.1.0", to: "v0.3.0", want: []string{"v0.2.0", "v0.3.0"}},
+		{name: "prefers the furthest migration", from: "v0.1.0", to: "v0.3.1", want: []string{"v0.2.0", "v0.3.1"}},
+		{name: "same version", from: "v0.2.0", to: "v0.2.0", want: nil},
+		{name: "no path", from: "v0.0.1", to: "v0.2.0", wantErr: "no migration path from v0.0.1 to v0.2.0"},
+		{name: "downgrade", from: "v0.3.0", to: "v0.2.0", wantErr: "older version"},
+		{name: "invalid version", from: "0.1", to: "v0.2.0", wantErr: `invalid schema version "0.1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := migrator.Plan(tt.from, tt.to)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var got []string
+			for _, m := range plan {
+				got = append(got, m.To)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	migrator := NewMigrator(
+		Migration{From: "v0.1.0", To: "v0.2.0", Steps: []Step{
+			RemoveField("metadata.author", "uri"),
+			RenameField("controls[*]", "family", "family-id").For("#ControlCatalog"),
+			RenameField("threats[*]", "capabilities", "features").For("ThreatCatalog"),
+		}},
+		Migration{From: "v0.2.0", To: "v0.3.0", Steps: []Step{
+			SetDefault("controls[*]", "assessment-requirements", []string{}),
+			ReplaceValue("controls[*]", "family-id", "data", "data-protection"),
+			MoveField("metadata", "author.name", "maintainer"),
+		}},
+	)
+
+	result, err := migrator.Migrate([]byte(sampleCatalog), "ControlCatalog", "v0.1.0", "v0.3.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, []AppliedStep{
+		{From: "v0.1.0", To: "v0.2.0", Description: "remove metadata.author.uri", Changes: 1},
+		{From: "v0.1.0", To: "v0.2.0", Description: "rename controls[*].family to family-id", Changes: 1},
+		{From: "v0.2.0", To: "v0.3.0", Description: "set missing controls[*].assessment-requirements to []", Changes: 1},
+		{From: "v0.2.0", To: "v0.3.0", Description: "replace controls[*].family-id data with data-protection", Changes: 1},
+		{From: "v0.2.0", To: "v0.3.0", Description: "move metadata.author.name to metadata.maintainer", Changes: 1},
+	}, result.Applied, "steps for other definitions and steps without changes should not be reported")
+
+	doc, err := ParseDocument(result.Content)
+	require.NoError(t, err)
+	controls, err := doc.Mappings("controls[*]")
+	require.NoError(t, err)
+	require.Len(t, controls, 2)
+
+	var family string
+	found, err := controls[0].Get("family-id", &family)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "data-protection", family)
+	assert.True(t, controls[1].Has("assessment-requirements"))
+
+	metadata, err := doc.Mappings("metadata")
+	require.NoError(t, err)
+	var maintainer string
+	found, err = metadata[0].Get("maintainer", &maintainer)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "Platform Team", maintainer)
+
+	unchanged, err := migrator.Migrate([]byte(sampleCatalog), "#ControlCatalog", "v0.2.0", "v0.2.0")
+	require.NoError(t, err)
+	assert.Empty(t, unchanged.Applied)
+	assert.Equal(t, sampleCatalog, string(unchanged.Content), "content should be returned unchanged")
+}
+
+func TestMigrateStepError(t *testing.T) {
+	migrator := NewMigrator(Migration{From: "v0.1.0", To: "v0.2.0", Steps: []Step{
+		RenameField("metadata", "id", "description"),
+	}})
+
+	_, err := migrator.Migrate([]byte(sampleCatalog), "#ControlCatalog", "v0.1.0", "v0.2.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "v0.1.0 to v0.2.0: rename metadata.id to description")
+}
@@ -0,0 +1,9 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package migration
+
+// Registered lists the migrations between published Gemara versions. Add a migration
+// here when a release changes the schema in a way that requires existing artifacts to
+// be rewritten, with one step per renamed, removed, moved or restructured field. Until
+// then, migrate_gemara_artifact reports that there is no migration path between versions.
+var Registered []Migration
@@ -3,17 +3,22 @@
 package tool
 
 import (
+	"cmp"
 	"context"
+	"fmt"
 	"time"
 
+	"cuelang.org/go/mod/modconfig"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
+	"github.com/gemaraproj/gemara-mcp/internal/tool/migration"
 )
 
 const (
-	httpTimeout          = 30 * time.Second
 	defaultSchemaVersion = "latest"
+	// DefaultLexiconURL is the location of the published Gemara Lexicon.
+	DefaultLexiconURL = "https://raw.githubusercontent.com/gemaraproj/gemara/main/docs/lexicon.yaml"
 )
 
 // Mode represents the operational mode of the MCP server.
@@ -28,18 +33,88 @@ type Mode interface {
 
 // AdvisoryMode defines tools and resources for operating in a read-only query mode
 type AdvisoryMode struct {
-	cache             *fetcher.Cache
-	lexiconURL        string
-	schemaDocsBaseURL string
+	cache   *fetcher.Cache
+	client  *fetcher.Client
+	lexicon Source
+	// artifacts verifies the artifacts tools fetch from their sources.
+	artifacts SourceChecks
+	schemas   *SchemaLoader
+	migrator  *migration.Migrator
+	// workspace indexes the artifacts under the roots of connected clients.
+	workspace    *WorkspaceIndex
+	pollInterval time.Duration
+	watcher      *workspaceWatcher
+	// logLevel is the level log messages are sent at until a client sets its own.
+	logLevel mcp.LoggingLevel
 }
 
-// NewAdvisoryMode creates a new AdvisoryMode with the provided cache and default URLs.
-func NewAdvisoryMode(cache *fetcher.Cache) *AdvisoryMode {
-	return &AdvisoryMode{
-		cache:             cache,
-		lexiconURL:        "https://raw.githubusercontent.com/gemaraproj/gemara/main/docs/lexicon.yaml",
-		schemaDocsBaseURL: "https://registry.cue.works/docs/github.com/gemaraproj/gemara@",
+// AdvisoryOption configures optional behavior of an AdvisoryMode.
+type AdvisoryOption func(*AdvisoryMode)
+
+// WithLexiconSource overrides the default lexicon location and declares how it is verified.
+func WithLexiconSource(source Source) AdvisoryOption {
+	return func(a *AdvisoryMode) {
+		a.lexicon = source
+	}
+}
+
+// WithArtifactChecks declares how artifacts fetched from the sources given to tools are verified.
+func WithArtifactChecks(checks SourceChecks) AdvisoryOption {
+	return func(a *AdvisoryMode) {
+		a.artifacts = checks
+	}
+}
+
+// WithSchemaLoader overrides the loader used to retrieve the Gemara CUE module.
+func WithSchemaLoader(loader *SchemaLoader) AdvisoryOption {
+	return func(a *AdvisoryMode) {
+		a.schemas = loader
+	}
+}
+
+// WithMigrator overrides the migrator used to upgrade artifacts between schema versions.
+func WithMigrator(migrator *migration.Migrator) AdvisoryOption {
+	return func(a *AdvisoryMode) {
+		a.migrator = migrator
+	}
+}
+
+// WithWorkspacePollInterval overrides how often indexed workspace files are checked for changes.
+func WithWorkspacePollInterval(interval time.Duration) AdvisoryOption {
+	return func(a *AdvisoryMode) {
+		a.pollInterval = interval
+	}
+}
+
+// WithLogLevel sends clients log messages at or above level before they set a level with
+// logging/setLevel. Without it, no log messages are sent until they do.
+func WithLogLevel(level mcp.LoggingLevel) AdvisoryOption {
+	return func(a *AdvisoryMode) {
+		a.logLevel = level
+	}
+}
+
+// NewAdvisoryMode creates a new AdvisoryMode with the provided cache, shared HTTP client and default URLs.
+func NewAdvisoryMode(cache *fetcher.Cache, client *fetcher.Client, opts ...AdvisoryOption) *AdvisoryMode {
+	a := &AdvisoryMode{
+		cache:        cache,
+		client:       client,
+		lexicon:      Source{URL: DefaultLexiconURL},
+		pollInterval: defaultWorkspacePollInterval,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.schemas == nil {
+		// Share the proxy and TLS configuration of the HTTP client with the CUE registry client.
+		a.schemas = NewSchemaLoader(&modconfig.Config{Transport: client.Transport()})
+	}
+	if a.migrator == nil {
+		a.migrator = migration.NewMigrator(migration.Registered...)
 	}
+	a.workspace = NewWorkspaceIndex(a.schemas)
+	a.watcher = newWorkspaceWatcher(a.workspace, a.pollInterval)
+	return a
 }
 
 func (a AdvisoryMode) Name() string {
@@ -51,32 +126,408 @@ func (a AdvisoryMode) Description() string {
 }
 
 func (a AdvisoryMode) Register(server *mcp.Server) {
+	// Progress and log reporting for all requests, so loads and validations can report their stages
+	server.AddReceivingMiddleware(reportRequests)
+
 	// Lexicon tool - provides information about Gemara terms
 	mcp.AddTool(server, MetadataGetLexicon, a.getLexicon)
 
-	// Validation tool - validates artifacts without modifying them
-	mcp.AddTool(server, MetadataValidateGemaraArtifact, ValidateGemaraArtifact)
+	// Validation tools - validate one artifact or a batch of artifacts without modifying them
+	mcp.AddTool(server, MetadataValidateGemaraArtifact, a.validateGemaraArtifact)
+	mcp.AddTool(server, MetadataValidateGemaraArtifacts, a.validateGemaraArtifacts)
+
+	// Migration tool - rewrites artifacts for a newer schema version and returns the result without saving it
+	mcp.AddTool(server, MetadataMigrateGemaraArtifact, a.migrateGemaraArtifact)
+
+	// Catalog merge tool - overlays a local catalog onto a base catalog and returns the result without saving it
+	mcp.AddTool(server, MetadataMergeCatalogs, a.mergeCatalogs)
+
+	// Workspace tool - detects and validates artifacts under the client's roots without modifying them
+	mcp.AddTool(server, MetadataListWorkspaceArtifacts, a.listWorkspaceArtifacts)
 
-	// Schema documentation tool - retrieves schema documentation from CUE registry
+	// Catalog query tools - filter the controls and assessment requirements of a control catalog
+	mcp.AddTool(server, MetadataQueryControls, a.queryControls)
+	mcp.AddTool(server, MetadataListAssessmentRequirements, a.listAssessmentRequirements)
+
+	// Policy tool - resolves a policy's imports against the workspace into the controls in scope
+	mcp.AddTool(server, MetadataResolvePolicy, a.resolvePolicy)
+
+	// Evaluation tools - summarize the results of an evaluation log against its catalog, and
+	// compare the results of two logs
+	mcp.AddTool(server, MetadataSummarizeEvaluationLog, a.summarizeEvaluationLog)
+	mcp.AddTool(server, MetadataDiffEvaluationLogs, a.diffEvaluationLogs)
+
+	// Artifact diff tool - compares two versions of an artifact entry by entry instead of line by line
+	mcp.AddTool(server, MetadataDiffArtifacts, a.diffArtifacts)
+
+	// Coverage tools - measure how much of a framework a control catalog covers, and which
+	// threats of the workspace no control mitigates
+	mcp.AddTool(server, MetadataAnalyzeCoverage, a.analyzeCoverage)
+	mcp.AddTool(server, MetadataAnalyzeThreatCoverage, a.analyzeThreatCoverage)
+
+	// Mapping tool - traces controls, threats and guidelines across the workspace artifacts
+	mcp.AddTool(server, MetadataTraceMapping, a.traceMapping)
+
+	// Schema documentation tool - documents definitions of the Gemara CUE module
 	mcp.AddTool(server, MetadataGetSchemaDocs, a.getSchemaDocs)
+
+	// Schema discovery tools - list definitions and published versions of the Gemara CUE module
+	mcp.AddTool(server, MetadataListDefinitions, a.listDefinitions)
+	mcp.AddTool(server, MetadataListSchemaVersions, a.listSchemaVersions)
+
+	// Schema diff tool - reports changes between two versions of the Gemara CUE module
+	mcp.AddTool(server, MetadataDiffSchemaVersions, a.diffSchemaVersions)
+
+	// JSON Schema export tool - converts definitions for tools that do not understand CUE
+	mcp.AddTool(server, MetadataExportJSONSchema, a.exportJSONSchema)
+
+	// Prompts for common workflows, embedding lexicon entries and schema excerpts
+	server.AddPrompt(PromptDraftControlCatalog, a.withPromptReferences(DraftControlCatalog))
+	server.AddPrompt(PromptWriteAssessmentRequirements, a.withPromptReferences(WriteAssessmentRequirements))
+	server.AddPrompt(PromptExplainEvaluationLog, a.withPromptReferences(ExplainEvaluationLog))
+
+	// Resource templates for lexicon terms and schema definitions
+	server.AddResourceTemplate(ResourceLexiconTerm, a.readLexiconTerm)
+	server.AddResourceTemplate(ResourceSchemaDefinition, a.readSchemaDefinition)
+
+	// Workspace artifacts and their controls as resources, kept current as files change
+	a.watcher.register(server)
+}
+
+// ServerOptions returns the options for servers the mode is registered with, which
+// provide completions and watch the workspaces of connected clients.
+func (a AdvisoryMode) ServerOptions() *mcp.ServerOptions {
+	return &mcp.ServerOptions{
+		Instructions:            a.Description(),
+		CompletionHandler:       a.Complete,
+		InitializedHandler:      a.watcher.initialized,
+		RootsListChangedHandler: a.watcher.rootsListChanged,
+		SubscribeHandler:        a.watcher.subscribe,
+		UnsubscribeHandler:      a.watcher.unsubscribe,
+	}
+}
+
+// SessionOptions returns the options for the sessions of servers the mode is registered
+// with, which send log messages at the mode's default level.
+func (a AdvisoryMode) SessionOptions() *mcp.ServerSessionOptions {
+	if a.logLevel == "" {
+		return nil
+	}
+	return &mcp.ServerSessionOptions{State: &mcp.ServerSessionState{LogLevel: a.logLevel}}
+}
+
+// Complete wraps Complete with cache access and configuration.
+func (a AdvisoryMode) Complete(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	cf, err := a.lexiconFetcher()
+	if err != nil {
+		return nil, err
+	}
+	return Complete(ctx, req, cf, a.schemas, a.workspace)
 }
 
 // getLexicon wraps GetLexicon with cache access and configuration.
 func (a AdvisoryMode) getLexicon(ctx context.Context, req *mcp.CallToolRequest, input InputGetLexicon) (*mcp.CallToolResult, OutputGetLexicon, error) {
-	source := a.lexiconURL
-	f := fetcher.NewHTTPFetcher(source, httpTimeout)
-	cf := fetcher.NewCachedFetcher(f, a.cache, source)
+	cf, err := a.lexiconFetcher()
+	if err != nil {
+		return nil, OutputGetLexicon{}, err
+	}
 	return GetLexicon(ctx, req, input, cf)
 }
 
-// getSchemaDocs wraps GetSchemaDocs with cache access and configuration.
+// lexiconFetcher returns a cached fetcher for the configured lexicon source.
+func (a AdvisoryMode) lexiconFetcher() (*fetcher.CachedFetcher, error) {
+	source := a.lexicon.URL
+	verifiers, err := a.lexicon.verifiers(a.client)
+	if err != nil {
+		return nil, err
+	}
+	f, err := fetcher.NewForURL(source, a.client)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.NewCachedFetcher(f, a.cache, source).WithVerifiers(verifiers...), nil
+}
+
+// validateGemaraArtifact wraps ValidateGemaraArtifact, loading the artifact from its source when one is given.
+func (a AdvisoryMode) validateGemaraArtifact(ctx context.Context, req *mcp.CallToolRequest, input InputValidateGemaraArtifact) (*mcp.CallToolResult, OutputValidateGemaraArtifact, error) {
+	content, sourceID, err := a.loadArtifact(ctx, "artifact", input.ArtifactContent, input.ArtifactSource)
+	if err != nil {
+		return nil, OutputValidateGemaraArtifact{}, err
+	}
+
+	input.ArtifactContent = content
+	result, output, err := ValidateGemaraArtifact(ctx, req, input, a.schemas)
+	output.Source = sourceID
+	return result, output, err
+}
+
+// validateGemaraArtifacts wraps ValidateGemaraArtifacts, loading the artifacts given by source.
+func (a AdvisoryMode) validateGemaraArtifacts(ctx context.Context, req *mcp.CallToolRequest, input InputValidateGemaraArtifacts) (*mcp.CallToolResult, OutputValidateGemaraArtifacts, error) {
+	artifacts := make([]ArtifactToValidate, len(input.Artifacts))
+	for i, artifact := range input.Artifacts {
+		if artifact.Source != "" {
+			if artifact.Content != "" {
+				return nil, OutputValidateGemaraArtifacts{}, fmt.Errorf("artifacts[%d]: content and source are mutually exclusive", i)
+			}
+			content, sourceID, err := a.loadArtifact(ctx, "artifact", "", artifact.Source)
+			if err != nil {
+				return nil, OutputValidateGemaraArtifacts{}, fmt.Errorf("artifacts[%d]: %w", i, err)
+			}
+			artifact.Name = cmp.Or(artifact.Name, artifact.Source)
+			artifact.Content, artifact.Source = content, sourceID
+		}
+		artifacts[i] = artifact
+	}
+
+	input.Artifacts = artifacts
+	return ValidateGemaraArtifacts(ctx, req, input, a.schemas)
+}
+
+// migrateGemaraArtifact wraps MigrateGemaraArtifact, loading the artifact from its source when one is given.
+func (a AdvisoryMode) migrateGemaraArtifact(ctx context.Context, req *mcp.CallToolRequest, input InputMigrateGemaraArtifact) (*mcp.CallToolResult, OutputMigrateGemaraArtifact, error) {
+	content, sourceID, err := a.loadArtifact(ctx, "artifact", input.ArtifactContent, input.ArtifactSource)
+	if err != nil {
+		return nil, OutputMigrateGemaraArtifact{}, err
+	}
+
+	input.ArtifactContent = content
+	result, output, err := MigrateGemaraArtifact(ctx, req, input, a.migrator, a.schemas)
+	output.Source = sourceID
+	return result, output, err
+}
+
+// mergeCatalogs wraps MergeCatalogs, resolving the base catalog and overlay from their sources or workspace IDs when given.
+func (a AdvisoryMode) mergeCatalogs(ctx context.Context, req *mcp.CallToolRequest, input InputMergeCatalogs) (*mcp.CallToolResult, OutputMergeCatalogs, error) {
+	base, baseSourceID, err := a.resolveArtifact(ctx, "base", input.BaseContent, input.BaseSource, input.BaseID)
+	if err != nil {
+		return nil, OutputMergeCatalogs{}, err
+	}
+	overlay, overlaySourceID, err := a.resolveArtifact(ctx, "overlay", input.OverlayContent, input.OverlaySource, input.OverlayID)
+	if err != nil {
+		return nil, OutputMergeCatalogs{}, err
+	}
+
+	input.BaseContent, input.OverlayContent = base, overlay
+	result, output, err := MergeCatalogs(ctx, req, input, a.schemas)
+	output.BaseSource, output.OverlaySource = baseSourceID, overlaySourceID
+	return result, output, err
+}
+
+// loadArtifact returns inline artifact content as is, or fetches the artifact from source
+// and returns it with its source identifier. The field prefix names the input fields in errors.
+func (a AdvisoryMode) loadArtifact(ctx context.Context, field, content, source string) (string, string, error) {
+	if source == "" {
+		return content, "", nil
+	}
+	if content != "" {
+		return "", "", fmt.Errorf("%s_content and %s_source are mutually exclusive", field, field)
+	}
+
+	f, err := a.artifacts.Source(source).fetcher(a.client)
+	if err != nil {
+		return "", "", err
+	}
+	reporterFrom(ctx).step(ctx, "fetching artifact from %s", source)
+	data, sourceID, err := f.Fetch(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load artifact: %w", err)
+	}
+	return string(data), sourceID, nil
+}
+
+// resolveArtifact returns the content of an artifact given inline, by source or by the
+// metadata ID of an artifact in the workspace index, with its source identifier. The field
+// prefix names the input fields in errors.
+func (a AdvisoryMode) resolveArtifact(ctx context.Context, field, content, source, id string) (string, string, error) {
+	if id == "" {
+		return a.loadArtifact(ctx, field, content, source)
+	}
+	if content != "" || source != "" {
+		return "", "", fmt.Errorf("%s_id cannot be combined with %s_content or %s_source", field, field, field)
+	}
+	artifact, ok := a.workspace.Artifact(id)
+	if !ok {
+		return "", "", fmt.Errorf("artifact %s is not in the workspace index", id)
+	}
+	return string(artifact.Content), artifact.Path, nil
+}
+
+// listWorkspaceArtifacts wraps ListWorkspaceArtifacts with access to the schema loader.
+func (a AdvisoryMode) listWorkspaceArtifacts(ctx context.Context, req *mcp.CallToolRequest, input InputListWorkspaceArtifacts) (*mcp.CallToolResult, OutputListWorkspaceArtifacts, error) {
+	return ListWorkspaceArtifacts(ctx, req, input, a.schemas)
+}
+
+// queryControls wraps QueryControls, resolving the catalog from its source or workspace ID when one is given.
+func (a AdvisoryMode) queryControls(ctx context.Context, req *mcp.CallToolRequest, input InputQueryControls) (*mcp.CallToolResult, OutputQueryControls, error) {
+	content, sourceID, err := a.resolveArtifact(ctx, "artifact", input.ArtifactContent, input.ArtifactSource, input.ArtifactID)
+	if err != nil {
+		return nil, OutputQueryControls{}, err
+	}
+
+	input.ArtifactContent = content
+	result, output, err := QueryControls(ctx, req, input)
+	output.Source = sourceID
+	return result, output, err
+}
+
+// listAssessmentRequirements wraps ListAssessmentRequirements, resolving the catalog from its source or workspace ID when one is given.
+func (a AdvisoryMode) listAssessmentRequirements(ctx context.Context, req *mcp.CallToolRequest, input InputListAssessmentRequirements) (*mcp.CallToolResult, OutputListAssessmentRequirements, error) {
+	content, sourceID, err := a.resolveArtifact(ctx, "artifact", input.ArtifactContent, input.ArtifactSource, input.ArtifactID)
+	if err != nil {
+		return nil, OutputListAssessmentRequirements{}, err
+	}
+
+	input.ArtifactContent = content
+	result, output, err := ListAssessmentRequirements(ctx, req, input)
+	output.Source = sourceID
+	return result, output, err
+}
+
+// resolvePolicy wraps ResolvePolicy, resolving the policy from its source or workspace ID when one is given.
+func (a AdvisoryMode) resolvePolicy(ctx context.Context, req *mcp.CallToolRequest, input InputResolvePolicy) (*mcp.CallToolResult, OutputResolvePolicy, error) {
+	content, sourceID, err := a.resolveArtifact(ctx, "artifact", input.ArtifactContent, input.ArtifactSource, input.ArtifactID)
+	if err != nil {
+		return nil, OutputResolvePolicy{}, err
+	}
+
+	input.ArtifactContent = content
+	result, output, err := ResolvePolicy(ctx, req, input, a.workspace)
+	output.Source = sourceID
+	return result, output, err
+}
+
+// summarizeEvaluationLog wraps SummarizeEvaluationLog, resolving the log and catalog from their sources or workspace IDs when given.
+func (a AdvisoryMode) summarizeEvaluationLog(ctx context.Context, req *mcp.CallToolRequest, input InputSummarizeEvaluationLog) (*mcp.CallToolResult, OutputSummarizeEvaluationLog, error) {
+	content, sourceID, err := a.resolveArtifact(ctx, "artifact", input.ArtifactContent, input.ArtifactSource, input.ArtifactID)
+	if err != nil {
+		return nil, OutputSummarizeEvaluationLog{}, err
+	}
+	// A catalog given by workspace ID alone is looked up by SummarizeEvaluationLog, which
+	// links its controls as resources.
+	var catalogSourceID string
+	if input.CatalogContent != "" || input.CatalogSource != "" {
+		input.CatalogContent, catalogSourceID, err = a.resolveArtifact(ctx, "catalog", input.CatalogContent, input.CatalogSource, input.CatalogID)
+		if err != nil {
+			return nil, OutputSummarizeEvaluationLog{}, err
+		}
+	}
+
+	input.ArtifactContent = content
+	result, output, err := SummarizeEvaluationLog(ctx, req, input, a.workspace)
+	output.Source = sourceID
+	if catalogSourceID != "" {
+		output.CatalogSource = catalogSourceID
+	}
+	return result, output, err
+}
+
+// diffEvaluationLogs wraps DiffEvaluationLogs, resolving both logs from their sources or workspace IDs when given.
+func (a AdvisoryMode) diffEvaluationLogs(ctx context.Context, req *mcp.CallToolRequest, input InputDiffEvaluationLogs) (*mcp.CallToolResult, OutputDiffEvaluationLogs, error) {
+	from, fromSourceID, err := a.resolveArtifact(ctx, "from", input.FromContent, input.FromSource, input.FromID)
+	if err != nil {
+		return nil, OutputDiffEvaluationLogs{}, err
+	}
+	to, toSourceID, err := a.resolveArtifact(ctx, "to", input.ToContent, input.ToSource, input.ToID)
+	if err != nil {
+		return nil, OutputDiffEvaluationLogs{}, err
+	}
+
+	input.FromContent, input.ToContent = from, to
+	result, output, err := DiffEvaluationLogs(ctx, req, input)
+	output.FromSource, output.ToSource = fromSourceID, toSourceID
+	return result, output, err
+}
+
+// diffArtifacts wraps DiffArtifacts, resolving both versions from their sources or workspace IDs when given.
+func (a AdvisoryMode) diffArtifacts(ctx context.Context, req *mcp.CallToolRequest, input InputDiffArtifacts) (*mcp.CallToolResult, OutputDiffArtifacts, error) {
+	from, fromSourceID, err := a.resolveArtifact(ctx, "from", input.FromContent, input.FromSource, input.FromID)
+	if err != nil {
+		return nil, OutputDiffArtifacts{}, err
+	}
+	to, toSourceID, err := a.resolveArtifact(ctx, "to", input.ToContent, input.ToSource, input.ToID)
+	if err != nil {
+		return nil, OutputDiffArtifacts{}, err
+	}
+
+	input.FromContent, input.ToContent = from, to
+	result, output, err := DiffArtifacts(ctx, req, input, a.schemas)
+	output.FromSource, output.ToSource = fromSourceID, toSourceID
+	return result, output, err
+}
+
+// analyzeCoverage wraps AnalyzeCoverage, resolving the catalog and guidance document from their sources or workspace IDs when given.
+func (a AdvisoryMode) analyzeCoverage(ctx context.Context, req *mcp.CallToolRequest, input InputAnalyzeCoverage) (*mcp.CallToolResult, OutputAnalyzeCoverage, error) {
+	content, sourceID, err := a.resolveArtifact(ctx, "artifact", input.ArtifactContent, input.ArtifactSource, input.ArtifactID)
+	if err != nil {
+		return nil, OutputAnalyzeCoverage{}, err
+	}
+	guidance, guidanceSourceID, err := a.resolveArtifact(ctx, "guidance", input.GuidanceContent, input.GuidanceSource, input.GuidanceID)
+	if err != nil {
+		return nil, OutputAnalyzeCoverage{}, err
+	}
+
+	input.ArtifactContent, input.GuidanceContent = content, guidance
+	result, output, err := AnalyzeCoverage(ctx, req, input)
+	output.Source, output.GuidanceSource = sourceID, guidanceSourceID
+	return result, output, err
+}
+
+// analyzeThreatCoverage wraps AnalyzeThreatCoverage with access to the workspace index.
+func (a AdvisoryMode) analyzeThreatCoverage(ctx context.Context, req *mcp.CallToolRequest, input InputAnalyzeThreatCoverage) (*mcp.CallToolResult, OutputAnalyzeThreatCoverage, error) {
+	return AnalyzeThreatCoverage(ctx, req, input, a.workspace)
+}
+
+// traceMapping wraps TraceMapping with access to the workspace index.
+func (a AdvisoryMode) traceMapping(ctx context.Context, req *mcp.CallToolRequest, input InputTraceMapping) (*mcp.CallToolResult, OutputTraceMapping, error) {
+	return TraceMapping(ctx, req, input, a.workspace)
+}
+
+// getSchemaDocs wraps GetSchemaDocs with access to the schema loader.
 func (a AdvisoryMode) getSchemaDocs(ctx context.Context, req *mcp.CallToolRequest, input InputGetSchemaDocs) (*mcp.CallToolResult, OutputGetSchemaDocs, error) {
-	version := input.Version
-	if version == "" {
-		version = defaultSchemaVersion
-	}
-	source := a.schemaDocsBaseURL + version
-	f := fetcher.NewHTTPFetcher(source, httpTimeout)
-	cf := fetcher.NewCachedFetcher(f, a.cache, source)
-	return GetSchemaDocs(ctx, req, input, cf)
+	return GetSchemaDocs(ctx, req, input, a.schemas)
+}
+
+// listDefinitions wraps ListDefinitions with access to the schema loader.
+func (a AdvisoryMode) listDefinitions(ctx context.Context, req *mcp.CallToolRequest, input InputListDefinitions) (*mcp.CallToolResult, OutputListDefinitions, error) {
+	return ListDefinitions(ctx, req, input, a.schemas)
+}
+
+// listSchemaVersions wraps ListSchemaVersions with access to the schema loader.
+func (a AdvisoryMode) listSchemaVersions(ctx context.Context, req *mcp.CallToolRequest, input InputListSchemaVersions) (*mcp.CallToolResult, OutputListSchemaVersions, error) {
+	return ListSchemaVersions(ctx, req, input, a.schemas)
+}
+
+// exportJSONSchema wraps ExportJSONSchema with access to the schema loader.
+func (a AdvisoryMode) exportJSONSchema(ctx context.Context, req *mcp.CallToolRequest, input InputExportJSONSchema) (*mcp.CallToolResult, OutputExportJSONSchema, error) {
+	return ExportJSONSchema(ctx, req, input, a.schemas)
+}
+
+// diffSchemaVersions wraps DiffSchemaVersions with access to the schema loader.
+func (a AdvisoryMode) diffSchemaVersions(ctx context.Context, req *mcp.CallToolRequest, input InputDiffSchemaVersions) (*mcp.CallToolResult, OutputDiffSchemaVersions, error) {
+	return DiffSchemaVersions(ctx, req, input, a.schemas)
+}
+
+// withPromptReferences adapts a prompt builder to an mcp.PromptHandler with access to the lexicon and schema loader.
+func (a AdvisoryMode) withPromptReferences(build func(context.Context, *mcp.GetPromptRequest, *fetcher.CachedFetcher, *SchemaLoader) (*mcp.GetPromptResult, error)) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		cf, err := a.lexiconFetcher()
+		if err != nil {
+			return nil, err
+		}
+		return build(ctx, req, cf, a.schemas)
+	}
+}
+
+// readLexiconTerm wraps ReadLexiconTerm with cache access and configuration.
+func (a AdvisoryMode) readLexiconTerm(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	cf, err := a.lexiconFetcher()
+	if err != nil {
+		return nil, err
+	}
+	return ReadLexiconTerm(ctx, req, cf)
+}
+
+// readSchemaDefinition wraps ReadSchemaDefinition with access to the schema loader.
+func (a AdvisoryMode) readSchemaDefinition(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	return ReadSchemaDefinition(ctx, req, a.schemas)
 }
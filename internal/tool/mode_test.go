@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
+	"github.com/gemaraproj/gemara-mcp/internal/tool/migration"
+)
+
+// newTestAdvisoryMode creates an advisory mode that serves sampleLexicon from a local
+// HTTP server and loads schemas from the stand-in registry.
+func newTestAdvisoryMode(t *testing.T, opts ...AdvisoryOption) *AdvisoryMode {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(sampleLexicon))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := fetcher.NewClient(fetcher.ClientOptions{Timeout: time.Second})
+	require.NoError(t, err)
+
+	opts = append([]AdvisoryOption{
+		WithLexiconSource(Source{URL: server.URL + "/lexicon.yaml"}),
+		WithSchemaLoader(newTestSchemaLoader(t)),
+	}, opts...)
+	return NewAdvisoryMode(fetcher.NewCache(time.Hour), client, opts...)
+}
+
+// newTestSession connects a client to a server with the mode registered.
+func newTestSession(t *testing.T, mode *AdvisoryMode) *mcp.ClientSession {
+	t.Helper()
+	return newTestClientSession(t, mode, nil)
+}
+
+// newTestClientSession connects a client with the given options and roots to a server with the mode registered.
+func newTestClientSession(t *testing.T, mode *AdvisoryMode, opts *mcp.ClientOptions, roots ...*mcp.Root) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gemara-mcp", Version: "test"}, mode.ServerOptions())
+	mode.Register(server)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, mode.SessionOptions())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, opts)
+	client.AddRoots(roots...)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func TestAdvisoryModeRegister(t *testing.T) {
+	session := newTestSession(t, newTestAdvisoryMode(t, WithMigrator(migration.NewMigrator(standInMigrations...))))
+	ctx := context.Background()
+
+	tools, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	var toolNames []string
+	for _, tool := range tools.Tools {
+		toolNames = append(toolNames, tool.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"get_lexicon",
+		"validate_gemara_artifact",
+		"validate_gemara_artifacts",
+		"migrate_gemara_artifact",
+		"merge_catalogs",
+		"list_workspace_artifacts",
+		"query_controls",
+		"list_assessment_requirements",
+		"resolve_policy",
+		"summarize_evaluation_log",
+		"diff_evaluation_logs",
+		"diff_artifacts",
+		"analyze_coverage",
+		"analyze_threat_coverage",
+		"trace_mapping",
+		"get_schema_docs",
+		"list_definitions",
+		"list_schema_versions",
+		"diff_schema_versions",
+		"export_json_schema",
+	}, toolNames)
+
+	prompts, err := session.ListPrompts(ctx, nil)
+	require.NoError(t, err)
+	var promptNames []string
+	for _, prompt := range prompts.Prompts {
+		promptNames = append(promptNames, prompt.Name)
+	}
+	assert.ElementsMatch(t, []string{"draft_control_catalog", "write_assessment_requirements", "explain_evaluation_log"}, promptNames)
+}
+
+func TestAdvisoryModeWithoutMigrations(t *testing.T) {
+	session := newTestSession(t, newTestAdvisoryMode(t, WithMigrator(migration.NewMigrator())))
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "migrate_gemara_artifact",
+		Arguments: map[string]any{"artifact_content": threatCatalogV010, "definition": "#ThreatCatalog", "from": "v0.1.0"},
+	})
+	require.NoError(t, err)
+	require.True(t, result.IsError, "migrating without migrations should fail")
+	require.Len(t, result.Content, 1)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "no migration path from v0.1.0 to v0.2.0")
+}
+
+func TestToolSchemas(t *testing.T) {
+	catalog, err := os.ReadFile(filepath.Join("testdata", "good-ccc.yaml"))
+	require.NoError(t, err)
+
+	testdata, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+	root := &mcp.Root{URI: "file://" + filepath.ToSlash(testdata)}
+	session := newTestClientSession(t, newTestAdvisoryMode(t, WithMigrator(migration.NewMigrator(standInMigrations...))), nil, root)
+	ctx := context.Background()
+
+	// Arguments of at least one successful call per tool.
+	calls := map[string][]map[string]any{
+		"get_lexicon": {{}},
+		"validate_gemara_artifact": {
+			{"artifact_content": string(catalog), "definition": "#ControlCatalog"},
+			{"artifact_content": threatCatalogV010, "definition": "#ThreatCatalog"},
+		},
+		"validate_gemara_artifacts": {
+			{"artifacts": []map[string]any{
+				{"name": "good-ccc.yaml", "content": string(catalog)},
+				{"content": threatCatalogV010, "definition": "#ThreatCatalog"},
+			}, "definition": "#ControlCatalog"},
+		},
+		"migrate_gemara_artifact": {
+			{"artifact_content": threatCatalogV010, "definition": "#ThreatCatalog", "from": "v0.1.0"},
+		},
+		"merge_catalogs": {
+			{"base_id": "FINOS-CCC", "overlay_content": testCatalogOverlay},
+		},
+		"get_schema_docs": {
+			{"definition": "#Control"},
+			{"definition": "#Control", "format": "json"},
+		},
+		"list_definitions":     {{}, {"layer": 2}},
+		"list_schema_versions": {{}},
+		"diff_schema_versions": {
+			{"from": "v0.1.0"},
+			{"from": "v0.2.0"},
+		},
+		"export_json_schema":       {{}, {"definition": "#Control"}},
+		"list_workspace_artifacts": {{}},
+		"query_controls": {
+			{"artifact_content": string(catalog)},
+			{"artifact_content": string(catalog), "family": "data-protection", "text": "logs"},
+		},
+		"list_assessment_requirements": {
+			{"artifact_content": string(catalog), "applicability": []string{"tlp_amber"}},
+			{"artifact_content": string(catalog), "applicability": []string{"tlp_clear"}, "format": "markdown"},
+			{"artifact_content": string(catalog), "applicability": []string{"tlp_clear"}, "format": "csv"},
+		},
+		"resolve_policy": {{"artifact_content": testPolicy}},
+		"summarize_evaluation_log": {
+			{"artifact_content": testEvaluationLog},
+			{"artifact_content": evaluationLogJSON, "catalog_content": string(catalog), "format": "markdown"},
+		},
+		"diff_evaluation_logs": {
+			{"from_content": evaluationLogJSON, "to_content": testEvaluationLog},
+			{"from_content": testEvaluationLog, "to_content": evaluationLogJSON, "format": "markdown"},
+		},
+		"diff_artifacts": {
+			{"from_content": string(catalog), "to_id": "FINOS-CCC"},
+			{"from_content": threatCatalogV010, "to_content": threatCatalogV010, "format": "markdown"},
+		},
+		"analyze_coverage": {
+			{"artifact_id": "FINOS-CCC", "guidance_id": "NIST-800-53"},
+			{"artifact_content": string(catalog), "framework": "CCM", "guidelines": []string{"IVS-03", "DSP-17"}, "format": "markdown"},
+		},
+		"analyze_threat_coverage": {{}, {"threat_catalog": "CCC", "format": "markdown"}},
+		"trace_mapping": {
+			{"from": "CCC.C01"},
+			{"from": "CCC:CCC.TH02", "to": "NIST-800-53:SC-8"},
+		},
+	}
+	// The workspace is indexed in the background once the session is initialized.
+	require.Eventually(t, func() bool { return len(resourceURIs(t, session)) > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	tools, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	for _, tool := range tools.Tools {
+		t.Run(tool.Name, func(t *testing.T) {
+			require.NotNil(t, tool.Annotations, "tool should be annotated")
+			assert.True(t, tool.Annotations.ReadOnlyHint, "tool should be read-only")
+			assert.True(t, tool.Annotations.IdempotentHint, "tool should be idempotent")
+			require.NotNil(t, tool.Annotations.OpenWorldHint, "tool should declare whether it is open world")
+			for name := range resolveSchema(t, tool.InputSchema).Schema().Properties {
+				if strings.HasSuffix(name, "_source") {
+					assert.True(t, *tool.Annotations.OpenWorldHint, "tool loading %s from arbitrary locations should be open world", name)
+				}
+			}
+
+			require.NotNil(t, tool.OutputSchema, "tool should declare an output schema")
+			outputSchema := resolveSchema(t, tool.OutputSchema)
+
+			require.Contains(t, calls, tool.Name, "tool needs test arguments")
+			for _, args := range calls[tool.Name] {
+				result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: tool.Name, Arguments: args})
+				require.NoError(t, err)
+				require.False(t, result.IsError, "call with %v should succeed: %v", args, result.Content)
+				assert.NoError(t, outputSchema.Validate(result.StructuredContent), "output for %v should conform to the output schema", args)
+			}
+		})
+	}
+}
+
+func TestToolInputSchemas(t *testing.T) {
+	session := newTestSession(t, newTestAdvisoryMode(t))
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		tool        string
+		args        map[string]any
+		errContains string
+	}{
+		{
+			name:        "missing required argument",
+			tool:        "diff_schema_versions",
+			args:        map[string]any{},
+			errContains: "from",
+		},
+		{
+			name:        "unsupported enum value",
+			tool:        "get_schema_docs",
+			args:        map[string]any{"format": "html"},
+			errContains: "format",
+		},
+		{
+			name:        "wrong argument type",
+			tool:        "list_definitions",
+			args:        map[string]any{"layer": "two"},
+			errContains: "layer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := session.CallTool(ctx, &mcp.CallToolParams{Name: tt.tool, Arguments: tt.args})
+			require.Error(t, err, "arguments should be rejected by the input schema")
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+// resolveSchema converts a schema received from the server for validation.
+func resolveSchema(t *testing.T, schema any) *jsonschema.Resolved {
+	t.Helper()
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	var s jsonschema.Schema
+	require.NoError(t, json.Unmarshal(data, &s))
+	resolved, err := s.Resolve(nil)
+	require.NoError(t, err)
+	return resolved
+}
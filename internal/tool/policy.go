@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Policy selects and scopes controls from catalogs for an organization.
+type Policy struct {
+	Title    string   `json:"title" yaml:"title"`
+	Metadata Metadata `json:"metadata" yaml:"metadata"`
+	Scope    Scope    `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Imports  Imports  `json:"imports" yaml:"imports"`
+}
+
+// Scope limits a policy to the applicability categories it covers.
+type Scope struct {
+	Applicability []string `json:"applicability,omitempty" yaml:"applicability,omitempty"`
+}
+
+// Imports lists the catalogs and guidance a policy draws from.
+type Imports struct {
+	Catalogs []PolicyImport `json:"catalogs,omitempty" yaml:"catalogs,omitempty"`
+	Guidance []PolicyImport `json:"guidance,omitempty" yaml:"guidance,omitempty"`
+}
+
+// PolicyImport references a control catalog or guidance document and lists the control or
+// guideline IDs excluded from the policy.
+type PolicyImport struct {
+	ReferenceID string   `json:"reference-id" yaml:"reference-id"`
+	Exclusions  []string `json:"exclusions,omitempty" yaml:"exclusions,omitempty"`
+}
+
+// ParsePolicy decodes a policy from YAML or JSON content without validating it.
+func ParsePolicy(content []byte) (*Policy, error) {
+	return parseArtifact(content, "policy", "imports", func(p *Policy) bool {
+		return p.Metadata.ID != "" || len(p.Imports.Catalogs) > 0 || len(p.Imports.Guidance) > 0
+	})
+}
+
+// MetadataResolvePolicy describes the ResolvePolicy tool.
+var MetadataResolvePolicy = &mcp.Tool{
+	Name:         "resolve_policy",
+	Description:  "Resolve a Gemara policy against the control catalogs and guidance documents in the client's workspace, producing the effective controls and assessment requirements in scope after the policy's applicability scope and exclusions, and failing with every import that cannot be resolved.",
+	InputSchema:  schemaFor[InputResolvePolicy](),
+	OutputSchema: schemaFor[OutputResolvePolicy](),
+	Annotations:  readOnlyAnnotations(true),
+}
+
+// InputResolvePolicy is the input for the ResolvePolicy tool.
+type InputResolvePolicy struct {
+	ArtifactContent string `json:"artifact_content,omitempty" jsonschema:"YAML content of the policy"`
+	ArtifactSource  string `json:"artifact_source,omitempty" jsonschema:"Location to load the policy from instead of artifact_content (e.g., 'oci://ghcr.io/org/policies:v1#storage.yaml', 'https://...')"`
+	ArtifactID      string `json:"artifact_id,omitempty" jsonschema:"Metadata ID of a policy in the client's workspace instead of artifact_content"`
+}
+
+// OutputResolvePolicy is the output for the ResolvePolicy tool.
+type OutputResolvePolicy struct {
+	PolicyID string `json:"policy_id"`
+	Title    string `json:"title"`
+	// Applicability are the categories in scope; empty when the policy covers all of them.
+	Applicability []string `json:"applicability,omitempty"`
+	// Controls and Requirements count the controls and assessment requirements in scope.
+	Controls     int                `json:"controls"`
+	Requirements int                `json:"requirements"`
+	Catalogs     []ResolvedCatalog  `json:"catalogs"`
+	Guidance     []ResolvedGuidance `json:"guidance"`
+	// Warnings report exclusions and scope categories the imported artifacts do not declare.
+	Warnings []string `json:"warnings,omitempty"`
+	Source   string   `json:"source,omitempty"`
+}
+
+// ResolvedCatalog is an imported control catalog narrowed to the policy.
+type ResolvedCatalog struct {
+	ID       string               `json:"id"`
+	Title    string               `json:"title"`
+	Path     string               `json:"path"`
+	Controls []RequirementControl `json:"controls"`
+	// Excluded are the controls the policy excludes, OutOfScope those without a requirement
+	// in the policy's applicability scope.
+	Excluded   []string `json:"excluded,omitempty"`
+	OutOfScope []string `json:"out_of_scope,omitempty"`
+}
+
+// ResolvedGuidance is an imported guidance document narrowed to the policy.
+type ResolvedGuidance struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Path       string   `json:"path"`
+	Guidelines []string `json:"guidelines"`
+	Excluded   []string `json:"excluded,omitempty"`
+}
+
+// ResolvePolicy resolves the imports of a policy against the workspace index.
+func ResolvePolicy(_ context.Context, _ *mcp.CallToolRequest, input InputResolvePolicy, index *WorkspaceIndex) (*mcp.CallToolResult, OutputResolvePolicy, error) {
+	if input.ArtifactContent == "" {
+		return nil, OutputResolvePolicy{}, fmt.Errorf("artifact_content is required")
+	}
+	policy, err := ParsePolicy([]byte(input.ArtifactContent))
+	if err != nil {
+		return nil, OutputResolvePolicy{}, err
+	}
+
+	// Resolve every import before narrowing any, so all unresolved imports are reported at once.
+	var unresolved []string
+	catalogs := make([]IndexedArtifact, len(policy.Imports.Catalogs))
+	for i, imp := range policy.Imports.Catalogs {
+		catalogs[i], err = resolveImport(index, imp.ReferenceID, "#ControlCatalog")
+		if err != nil {
+			unresolved = append(unresolved, "catalog "+err.Error())
+		}
+	}
+	guidance := make([]IndexedArtifact, len(policy.Imports.Guidance))
+	for i, imp := range policy.Imports.Guidance {
+		guidance[i], err = resolveImport(index, imp.ReferenceID, "#GuidanceDocument")
+		if err != nil {
+			unresolved = append(unresolved, "guidance "+err.Error())
+		}
+	}
+	if len(unresolved) > 0 {
+		return nil, OutputResolvePolicy{}, fmt.Errorf("policy %s has unresolved imports: %s", policy.Metadata.ID, strings.Join(unresolved, "; "))
+	}
+
+	output := OutputResolvePolicy{
+		PolicyID:      policy.Metadata.ID,
+		Title:         policy.Title,
+		Applicability: policy.Scope.Applicability,
+		Catalogs:      []ResolvedCatalog{},
+		Guidance:      []ResolvedGuidance{},
+	}
+	for i, imp := range policy.Imports.Catalogs {
+		resolved, warnings, err := resolveCatalogImport(catalogs[i], imp, policy.Scope.Applicability)
+		if err != nil {
+			return nil, OutputResolvePolicy{}, err
+		}
+		output.Catalogs = append(output.Catalogs, resolved)
+		output.Warnings = append(output.Warnings, warnings...)
+		for _, control := range resolved.Controls {
+			output.Controls++
+			output.Requirements += len(control.Requirements)
+		}
+	}
+	for i, imp := range policy.Imports.Guidance {
+		resolved, warnings, err := resolveGuidanceImport(guidance[i], imp)
+		if err != nil {
+			return nil, OutputResolvePolicy{}, err
+		}
+		output.Guidance = append(output.Guidance, resolved)
+		output.Warnings = append(output.Warnings, warnings...)
+	}
+	return nil, output, nil
+}
+
+// resolveImport finds the indexed artifact a policy import references.
+func resolveImport(index *WorkspaceIndex, id, definition string) (IndexedArtifact, error) {
+	artifact, ok := index.Artifact(id)
+	if !ok {
+		return IndexedArtifact{}, fmt.Errorf("%s is not in the workspace index", id)
+	}
+	if artifact.Definition != definition {
+		return IndexedArtifact{}, fmt.Errorf("%s at %s is a %s, not a %s", id, artifact.Path, artifact.Definition, definition)
+	}
+	return artifact, nil
+}
+
+// resolveCatalogImport narrows an imported catalog to the controls and requirements the
+// policy keeps.
+func resolveCatalogImport(artifact IndexedArtifact, imp PolicyImport, scope []string) (ResolvedCatalog, []string, error) {
+	catalog, err := ParseControlCatalog(artifact.Content)
+	if err != nil {
+		return ResolvedCatalog{}, nil, fmt.Errorf("%s: %w", artifact.Path, err)
+	}
+
+	var warnings []string
+	for _, id := range imp.Exclusions {
+		if _, ok := catalog.Control(id); !ok {
+			warnings = append(warnings, fmt.Sprintf("exclusion %s is not a control of catalog %s", id, catalog.Metadata.ID))
+		}
+	}
+	if len(catalog.Metadata.ApplicabilityCategories) > 0 {
+		for _, category := range scope {
+			if !slices.ContainsFunc(catalog.Metadata.ApplicabilityCategories, func(c Category) bool { return strings.EqualFold(c.ID, category) }) {
+				warnings = append(warnings, fmt.Sprintf("scope category %s is not declared by catalog %s", category, catalog.Metadata.ID))
+			}
+		}
+	}
+
+	resolved := ResolvedCatalog{
+		ID:       catalog.Metadata.ID,
+		Title:    catalog.Title,
+		Path:     artifact.Path,
+		Controls: []RequirementControl{},
+	}
+	for _, control := range catalog.Controls {
+		if slices.Contains(imp.Exclusions, control.ID) {
+			resolved.Excluded = append(resolved.Excluded, control.ID)
+			continue
+		}
+		var requirements []AssessmentRequirement
+		for _, requirement := range control.AssessmentRequirements {
+			if len(scope) == 0 || slices.ContainsFunc(scope, func(category string) bool { return containsFold(requirement.Applicability, category) }) {
+				requirement.Text = compactText(requirement.Text)
+				requirements = append(requirements, requirement)
+			}
+		}
+		if len(requirements) == 0 {
+			resolved.OutOfScope = append(resolved.OutOfScope, control.ID)
+			continue
+		}
+		resolved.Controls = append(resolved.Controls, RequirementControl{
+			ID:           control.ID,
+			Title:        compactText(control.Title),
+			Requirements: requirements,
+		})
+	}
+	return resolved, warnings, nil
+}
+
+// resolveGuidanceImport narrows an imported guidance document to the guidelines the policy keeps.
+func resolveGuidanceImport(artifact IndexedArtifact, imp PolicyImport) (ResolvedGuidance, []string, error) {
+	document, err := ParseGuidanceDocument(artifact.Content)
+	if err != nil {
+		return ResolvedGuidance{}, nil, fmt.Errorf("%s: %w", artifact.Path, err)
+	}
+
+	var warnings []string
+	for _, id := range imp.Exclusions {
+		if _, ok := document.Guideline(id); !ok {
+			warnings = append(warnings, fmt.Sprintf("exclusion %s is not a guideline of guidance %s", id, document.Metadata.ID))
+		}
+	}
+
+	resolved := ResolvedGuidance{
+		ID:         document.Metadata.ID,
+		Title:      document.Title,
+		Path:       artifact.Path,
+		Guidelines: []string{},
+	}
+	for _, guideline := range document.Guidelines {
+		if slices.Contains(imp.Exclusions, guideline.ID) {
+			resolved.Excluded = append(resolved.Excluded, guideline.ID)
+			continue
+		}
+		resolved.Guidelines = append(resolved.Guidelines, guideline.ID)
+	}
+	return resolved, warnings, nil
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `title: Public Data Storage Policy
+metadata:
+  id: ACME-STORAGE
+  description: Storage of public data in cloud object storage.
+  author:
+    id: acme
+    name: ACME Security
+scope:
+  applicability: [tlp_clear]
+imports:
+  catalogs:
+    - reference-id: FINOS-CCC
+      exclusions: [CCC.C06]
+  guidance:
+    - reference-id: NIST-800-53
+      exclusions: [AC-17]
+`
+
+func TestResolvePolicy(t *testing.T) {
+	index := newTestMappingIndex(t)
+
+	tests := []struct {
+		name           string
+		policy         string
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputResolvePolicy)
+	}{
+		{
+			name:   "scope and exclusions",
+			policy: testPolicy,
+			validateOutput: func(t *testing.T, output OutputResolvePolicy) {
+				assert.Equal(t, "ACME-STORAGE", output.PolicyID)
+				assert.Equal(t, []string{"tlp_clear"}, output.Applicability)
+				assert.Equal(t, 2, output.Controls)
+				assert.Equal(t, 5, output.Requirements)
+				assert.Empty(t, output.Warnings)
+
+				require.Len(t, output.Catalogs, 1)
+				catalog := output.Catalogs[0]
+				assert.Equal(t, "FINOS-CCC", catalog.ID)
+				assert.True(t, strings.HasSuffix(catalog.Path, "good-ccc.yaml"), catalog.Path)
+				var controls []string
+				for _, control := range catalog.Controls {
+					controls = append(controls, control.ID)
+				}
+				assert.Equal(t, []string{"CCC.C01", "CCC.C09"}, controls)
+				assert.Equal(t, []string{"CCC.C06"}, catalog.Excluded)
+				assert.Equal(t, []string{"CCC.C08", "CCC.C10"}, catalog.OutOfScope)
+
+				require.Len(t, output.Guidance, 1)
+				assert.Equal(t, "NIST-800-53", output.Guidance[0].ID)
+				assert.Len(t, output.Guidance[0].Guidelines, 9)
+				assert.NotContains(t, output.Guidance[0].Guidelines, "AC-17")
+				assert.Equal(t, []string{"AC-17"}, output.Guidance[0].Excluded)
+			},
+		},
+		{
+			name:   "no scope",
+			policy: strings.Replace(testPolicy, "scope:\n  applicability: [tlp_clear]\n", "", 1),
+			validateOutput: func(t *testing.T, output OutputResolvePolicy) {
+				assert.Nil(t, output.Applicability)
+				assert.Equal(t, 4, output.Controls)
+				assert.Equal(t, 8, output.Requirements)
+				assert.Empty(t, output.Catalogs[0].OutOfScope)
+			},
+		},
+		{
+			name: "unknown exclusions and categories",
+			policy: strings.NewReplacer(
+				"[tlp_clear]", "[tlp_clear, tlp_white]",
+				"[CCC.C06]", "[CCC.C06, CCC.C99]",
+				"[AC-17]", "[AC-99]",
+			).Replace(testPolicy),
+			validateOutput: func(t *testing.T, output OutputResolvePolicy) {
+				assert.Equal(t, []string{
+					"exclusion CCC.C99 is not a control of catalog FINOS-CCC",
+					"scope category tlp_white is not declared by catalog FINOS-CCC",
+					"exclusion AC-99 is not a guideline of guidance NIST-800-53",
+				}, output.Warnings)
+			},
+		},
+		{
+			name: "unresolved imports",
+			policy: strings.NewReplacer(
+				"reference-id: FINOS-CCC", "reference-id: ACME-CCC",
+				"reference-id: NIST-800-53", "reference-id: CCC",
+			).Replace(testPolicy),
+			wantErr:     true,
+			errContains: "policy ACME-STORAGE has unresolved imports: catalog ACME-CCC is not in the workspace index; guidance CCC at ",
+		},
+		{
+			name:        "not a policy",
+			policy:      "owner: security-team\n",
+			wantErr:     true,
+			errContains: "failed to parse policy: no metadata or imports found",
+		},
+		{
+			name:        "missing policy",
+			wantErr:     true,
+			errContains: "artifact_content is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := ResolvePolicy(context.Background(), nil, InputResolvePolicy{ArtifactContent: tt.policy}, index)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			tt.validateOutput(t, output)
+		})
+	}
+}
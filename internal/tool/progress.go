@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// loggerName identifies the server in log messages sent to clients.
+	loggerName = "gemara-mcp"
+
+	logDebug   mcp.LoggingLevel = "debug"
+	logInfo    mcp.LoggingLevel = "info"
+	logWarning mcp.LoggingLevel = "warning"
+)
+
+// reporter sends progress notifications and log messages about a request to the client
+// that made it. Progress is only sent when the client asked for it with a progress token,
+// and log messages only at or above the level the client set, or the default level of
+// the session (see WithLogLevel) until it sets one. A nil reporter, as used outside MCP
+// sessions, reports nothing.
+type reporter struct {
+	session *mcp.ServerSession
+	token   any
+
+	mu       sync.Mutex
+	progress float64
+	total    float64
+}
+
+type reporterKey struct{}
+
+// reportedMethods are the requests that load schemas or artifacts and may take long.
+var reportedMethods = map[string]bool{
+	"tools/call":          true,
+	"prompts/get":         true,
+	"resources/read":      true,
+	"completion/complete": true,
+}
+
+// reportRequests is a receiving middleware that attaches a reporter for the client of
+// each reported request to the request's context.
+func reportRequests(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if session, ok := req.GetSession().(*mcp.ServerSession); ok && reportedMethods[method] {
+			r := &reporter{session: session}
+			if params, ok := req.GetParams().(mcp.RequestParams); ok {
+				r.token = params.GetProgressToken()
+			}
+			ctx = context.WithValue(ctx, reporterKey{}, r)
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// reporterFrom returns the reporter of the request ctx belongs to, or nil.
+func reporterFrom(ctx context.Context) *reporter {
+	r, _ := ctx.Value(reporterKey{}).(*reporter)
+	return r
+}
+
+// expect announces n further steps, so clients can show how far a batch has come.
+func (r *reporter) expect(n int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.total = r.progress + float64(n)
+}
+
+// step reports that the request has moved on to the stage described by message.
+// Progress notifications and log messages are best effort; failures to send them are ignored.
+func (r *reporter) step(ctx context.Context, format string, args ...any) {
+	if r == nil {
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+
+	r.mu.Lock()
+	r.progress++
+	params := &mcp.ProgressNotificationParams{
+		ProgressToken: r.token,
+		Message:       message,
+		Progress:      r.progress,
+	}
+	if r.total > 0 {
+		params.Total = max(r.total, r.progress)
+	}
+	r.mu.Unlock()
+
+	if r.token != nil {
+		_ = r.session.NotifyProgress(ctx, params)
+	}
+	r.log(ctx, logDebug, "%s", message)
+}
+
+// log sends a log message at level.
+func (r *reporter) log(ctx context.Context, level mcp.LoggingLevel, format string, args ...any) {
+	if r == nil {
+		return
+	}
+	_ = r.session.Log(ctx, &mcp.LoggingMessageParams{
+		Level:  level,
+		Logger: loggerName,
+		Data:   fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notifications records the progress notifications and log messages a client receives.
+type notifications struct {
+	mu       sync.Mutex
+	progress []*mcp.ProgressNotificationParams
+	logs     []*mcp.LoggingMessageParams
+}
+
+func (n *notifications) clientOptions() *mcp.ClientOptions {
+	return &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			n.progress = append(n.progress, req.Params)
+		},
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			n.logs = append(n.logs, req.Params)
+		},
+	}
+}
+
+func (n *notifications) messages() ([]string, []string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var progress, logs []string
+	for _, p := range n.progress {
+		progress = append(progress, p.Message)
+	}
+	for _, l := range n.logs {
+		logs = append(logs, string(l.Level)+": "+l.Data.(string))
+	}
+	return progress, logs
+}
+
+func TestValidationReporting(t *testing.T) {
+	catalog, err := os.ReadFile(filepath.Join("testdata", "good-ccc.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		defaultLevel  mcp.LoggingLevel
+		logLevel      mcp.LoggingLevel
+		progressToken any
+		wantProgress  []string
+		wantLogs      []string
+	}{
+		{
+			name:          "progress and debug logs",
+			logLevel:      "debug",
+			progressToken: "validate-1",
+			wantProgress: []string{
+				"resolving latest version of github.com/gemaraproj/gemara",
+				"loading module github.com/gemaraproj/gemara@v0.2.0",
+				"building schema github.com/gemaraproj/gemara@v0.2.0",
+				"validating artifact against #ControlCatalog of github.com/gemaraproj/gemara@v0.2.0",
+			},
+			wantLogs: []string{
+				"debug: resolving latest version of github.com/gemaraproj/gemara",
+				"info: resolved latest version of github.com/gemaraproj/gemara to v0.2.0",
+				"debug: loading module github.com/gemaraproj/gemara@v0.2.0",
+				"debug: building schema github.com/gemaraproj/gemara@v0.2.0",
+				"debug: validating artifact against #ControlCatalog of github.com/gemaraproj/gemara@v0.2.0",
+				"info: artifact is a valid #ControlCatalog",
+			},
+		},
+		{
+			name:     "logs at or above the client's level without progress token",
+			logLevel: "info",
+			wantLogs: []string{
+				"info: resolved latest version of github.com/gemaraproj/gemara to v0.2.0",
+				"info: artifact is a valid #ControlCatalog",
+			},
+		},
+		{
+			name:         "logs at the server's default level before the client sets one",
+			defaultLevel: "info",
+			wantLogs: []string{
+				"info: resolved latest version of github.com/gemaraproj/gemara to v0.2.0",
+				"info: artifact is a valid #ControlCatalog",
+			},
+		},
+		{
+			name:         "the client's level overrides the server's default",
+			defaultLevel: "debug",
+			logLevel:     "warning",
+		},
+		{
+			name: "nothing before the client sets a level",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received notifications
+			session := newTestClientSession(t, newTestAdvisoryMode(t, WithLogLevel(tt.defaultLevel)), received.clientOptions())
+			ctx := context.Background()
+			if tt.logLevel != "" {
+				require.NoError(t, session.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: tt.logLevel}))
+			}
+
+			params := &mcp.CallToolParams{
+				Name:      "validate_gemara_artifact",
+				Arguments: map[string]any{"artifact_content": string(catalog), "definition": "#ControlCatalog"},
+			}
+			if tt.progressToken != nil {
+				params.Meta = mcp.Meta{"progressToken": tt.progressToken}
+			}
+			result, err := session.CallTool(ctx, params)
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+
+			// Notifications are delivered asynchronously; ping to let them arrive.
+			require.NoError(t, session.Ping(ctx, nil))
+			assert.Eventually(t, func() bool {
+				progress, logs := received.messages()
+				return len(progress) == len(tt.wantProgress) && len(logs) == len(tt.wantLogs)
+			}, time.Second, 10*time.Millisecond)
+
+			progress, logs := received.messages()
+			assert.Equal(t, tt.wantProgress, progress)
+			assert.Equal(t, tt.wantLogs, logs)
+			received.mu.Lock()
+			defer received.mu.Unlock()
+			for i, p := range received.progress {
+				assert.Equal(t, tt.progressToken, p.ProgressToken)
+				assert.Equal(t, float64(i+1), p.Progress, "progress should increase with every step")
+			}
+		})
+	}
+}
+
+func TestBatchValidationReporting(t *testing.T) {
+	catalog, err := os.ReadFile(filepath.Join("testdata", "good-ccc.yaml"))
+	require.NoError(t, err)
+
+	var received notifications
+	session := newTestClientSession(t, newTestAdvisoryMode(t, WithLogLevel("info")), received.clientOptions())
+	ctx := context.Background()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Meta: mcp.Meta{"progressToken": "batch-1"},
+		Name: "validate_gemara_artifacts",
+		Arguments: map[string]any{
+			"artifacts": []map[string]any{
+				{"name": "ccc.yaml", "content": string(catalog)},
+				{"name": "broken.yaml", "content": "title: Broken"},
+				{"name": "threats.yaml", "content": threatCatalogV010, "definition": "#ThreatCatalog"},
+			},
+			"definition": "#ControlCatalog",
+			"version":    "v0.1.0",
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	wantProgress := []string{
+		"loading module github.com/gemaraproj/gemara@v0.1.0",
+		"building schema github.com/gemaraproj/gemara@v0.1.0",
+		"validating ccc.yaml against #ControlCatalog of github.com/gemaraproj/gemara@v0.1.0",
+		"validating broken.yaml against #ControlCatalog of github.com/gemaraproj/gemara@v0.1.0",
+		"validating threats.yaml against #ThreatCatalog of github.com/gemaraproj/gemara@v0.1.0",
+	}
+	require.NoError(t, session.Ping(ctx, nil))
+	assert.Eventually(t, func() bool {
+		progress, logs := received.messages()
+		return len(progress) == len(wantProgress) && len(logs) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	progress, logs := received.messages()
+	assert.Equal(t, wantProgress, progress)
+	assert.Equal(t, "info: ccc.yaml is a valid #ControlCatalog", logs[0])
+	assert.Contains(t, logs[1], "warning: broken.yaml is not a valid #ControlCatalog")
+	assert.Equal(t, "info: threats.yaml is a valid #ThreatCatalog", logs[2])
+	received.mu.Lock()
+	defer received.mu.Unlock()
+	for _, p := range received.progress[2:] {
+		assert.Equal(t, float64(len(wantProgress)), p.Total, "per-file progress should count toward the announced total")
+	}
+}
+
+func TestValidationCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := ValidateGemaraArtifact(ctx, nil, InputValidateGemaraArtifact{
+		ArtifactContent: "title: Catalog",
+		Definition:      "#ControlCatalog",
+		Version:         "v0.1.0",
+	}, newTestSchemaLoader(t))
+	require.Error(t, err, "cancelled validation should fail")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNilReporter(t *testing.T) {
+	var nilReporter *reporter
+	assert.NotPanics(t, func() {
+		nilReporter.step(context.Background(), "step")
+		nilReporter.log(context.Background(), logInfo, "message")
+	}, "a nil reporter should report nothing")
+}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
+)
+
+// PromptDraftControlCatalog describes the DraftControlCatalog prompt.
+var PromptDraftControlCatalog = &mcp.Prompt{
+	Name:        "draft_control_catalog",
+	Title:       "Draft a control catalog from a guidance document",
+	Description: "Draft a Gemara Layer 2 control catalog that implements the guidelines of a Layer 1 guidance document.",
+	Arguments: []*mcp.PromptArgument{
+		{
+			Name:        "guidance",
+			Description: "Guidance document YAML, or the title and guidelines of the guidance to implement",
+			Required:    true,
+		},
+		{
+			Name:        "technology",
+			Description: "Technology the catalog's controls apply to (e.g., 'object storage')",
+		},
+	},
+}
+
+// PromptWriteAssessmentRequirements describes the WriteAssessmentRequirements prompt.
+var PromptWriteAssessmentRequirements = &mcp.Prompt{
+	Name:        "write_assessment_requirements",
+	Title:       "Write assessment requirements for a control",
+	Description: "Write tightly scoped, verifiable assessment requirements for a Gemara control.",
+	Arguments: []*mcp.PromptArgument{
+		{
+			Name:        "control",
+			Description: "Control YAML, or the control's title and objective",
+			Required:    true,
+		},
+		{
+			Name:        "applicability",
+			Description: "Comma-separated applicability categories the requirements may be scoped to (e.g., 'tlp_green,tlp_amber')",
+		},
+	},
+}
+
+// PromptExplainEvaluationLog describes the ExplainEvaluationLog prompt.
+var PromptExplainEvaluationLog = &mcp.Prompt{
+	Name:        "explain_evaluation_log",
+	Title:       "Explain an evaluation log",
+	Description: "Explain the results of a Gemara Layer 5 evaluation log and the follow-up they call for.",
+	Arguments: []*mcp.PromptArgument{
+		{
+			Name:        "evaluation_log",
+			Description: "Evaluation log YAML to explain",
+			Required:    true,
+		},
+	},
+}
+
+// promptReference selects the lexicon terms and schema definitions embedded in a prompt.
+type promptReference struct {
+	terms       []string
+	definitions []string
+}
+
+var (
+	draftControlCatalogReference = promptReference{
+		terms:       []string{"Guidance", "Guideline", "Control", "Control Catalog", "Family", "Assessment Requirement"},
+		definitions: []string{"#ControlCatalog", "#Control", "#AssessmentRequirement", "#Family", "#MultiMapping"},
+	}
+	writeAssessmentRequirementsReference = promptReference{
+		terms:       []string{"Control", "Assessment Requirement", "Applicability", "Assessment"},
+		definitions: []string{"#Control", "#AssessmentRequirement"},
+	}
+	explainEvaluationLogReference = promptReference{
+		terms:       []string{"Evaluation", "Assessment", "Control", "Assessment Requirement"},
+		definitions: []string{"#EvaluationLog", "#ControlEvaluation", "#AssessmentLog", "#Result"},
+	}
+)
+
+// DraftControlCatalog builds the DraftControlCatalog prompt, embedding lexicon entries from
+// the specified cached fetcher and schema excerpts from the specified schema loader.
+func DraftControlCatalog(ctx context.Context, req *mcp.GetPromptRequest, lexicon *fetcher.CachedFetcher, loader *SchemaLoader) (*mcp.GetPromptResult, error) {
+	guidance, err := promptArgument(req, "guidance")
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("Draft a Gemara control catalog (#ControlCatalog) that implements the guidance below.\n\n")
+	if technology := req.Params.Arguments["technology"]; technology != "" {
+		fmt.Fprintf(&b, "The controls apply to %s; make every objective and requirement specific to it.\n\n", technology)
+	}
+	b.WriteString("Group the controls into families. For each control, state its objective, map it to the guidelines it implements in guideline-mappings, and give it at least one assessment requirement. ")
+	b.WriteString("Return the catalog as YAML that validates against the schema excerpt, and check it with the validate_gemara_artifact tool.\n\n")
+	writeSection(&b, "guidance", guidance)
+
+	return buildPrompt(ctx, PromptDraftControlCatalog, b.String(), draftControlCatalogReference, lexicon, loader)
+}
+
+// WriteAssessmentRequirements builds the WriteAssessmentRequirements prompt, embedding lexicon
+// entries from the specified cached fetcher and schema excerpts from the specified schema loader.
+func WriteAssessmentRequirements(ctx context.Context, req *mcp.GetPromptRequest, lexicon *fetcher.CachedFetcher, loader *SchemaLoader) (*mcp.GetPromptResult, error) {
+	control, err := promptArgument(req, "control")
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("Write assessment requirements (#AssessmentRequirement) for the control below.\n\n")
+	b.WriteString("Each requirement must be a single condition that an assessor or tool can verify as met or not met, without restating the control's objective. ")
+	b.WriteString("Give each requirement an ID derived from the control ID, and add a recommendation where the way to meet it is not obvious.\n\n")
+	if applicability := req.Params.Arguments["applicability"]; applicability != "" {
+		fmt.Fprintf(&b, "Scope each requirement to one or more of these applicability categories: %s.\n\n", applicability)
+	}
+	writeSection(&b, "control", control)
+
+	return buildPrompt(ctx, PromptWriteAssessmentRequirements, b.String(), writeAssessmentRequirementsReference, lexicon, loader)
+}
+
+// ExplainEvaluationLog builds the ExplainEvaluationLog prompt, embedding lexicon entries from
+// the specified cached fetcher and schema excerpts from the specified schema loader.
+func ExplainEvaluationLog(ctx context.Context, req *mcp.GetPromptRequest, lexicon *fetcher.CachedFetcher, loader *SchemaLoader) (*mcp.GetPromptResult, error) {
+	log, err := promptArgument(req, "evaluation_log")
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("Explain the evaluation log below (#EvaluationLog) to the owner of the evaluated system.\n\n")
+	b.WriteString("Summarize how many controls passed, failed, need review or were not run. ")
+	b.WriteString("For every control that did not pass, name the failing assessment requirements, quote their messages and recommendations, and suggest what to fix first.\n\n")
+	writeSection(&b, "evaluation-log", log)
+
+	return buildPrompt(ctx, PromptExplainEvaluationLog, b.String(), explainEvaluationLogReference, lexicon, loader)
+}
+
+// buildPrompt appends the referenced lexicon entries and schema definitions to the
+// instructions and returns them as a single user message.
+func buildPrompt(ctx context.Context, prompt *mcp.Prompt, instructions string, ref promptReference, lexicon *fetcher.CachedFetcher, loader *SchemaLoader) (*mcp.GetPromptResult, error) {
+	data, _, err := lexicon.Fetch(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lexicon: %w", err)
+	}
+	entries, err := parseLexicon(data)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := loader.Load(ctx, defaultSchemaVersion, false)
+	if err != nil {
+		return nil, err
+	}
+	defs := make([]DefinitionDoc, 0, len(ref.definitions))
+	for _, name := range ref.definitions {
+		v, name, err := lookupDefinition(schema.Value, name)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, describeDefinition(name, v))
+	}
+
+	var b strings.Builder
+	b.WriteString(instructions)
+	if terms := lexiconTerms(entries, ref.terms); terms != "" {
+		writeSection(&b, "gemara-lexicon", terms)
+	}
+	writeSection(&b, "gemara-schema", renderSchemaDocs(schema.Version, defs))
+
+	return &mcp.GetPromptResult{
+		Description: prompt.Description,
+		Messages: []*mcp.PromptMessage{{
+			Role:    "user",
+			Content: &mcp.TextContent{Text: b.String()},
+		}},
+	}, nil
+}
+
+// lexiconTerms lists the definitions of the given terms, skipping terms the lexicon does not define.
+func lexiconTerms(entries []LexiconEntry, terms []string) string {
+	var b strings.Builder
+	for _, term := range terms {
+		for _, entry := range entries {
+			if strings.EqualFold(entry.Term, term) {
+				fmt.Fprintf(&b, "- **%s**: %s\n", entry.Term, strings.TrimSpace(entry.Definition))
+				break
+			}
+		}
+	}
+	return b.String()
+}
+
+// writeSection appends content delimited by tag, keeping embedded documents apart from the instructions.
+func writeSection(b *strings.Builder, tag, content string) {
+	fmt.Fprintf(b, "<%s>\n%s\n</%s>\n\n", tag, strings.TrimSpace(content), tag)
+}
+
+// promptArgument returns a required prompt argument.
+func promptArgument(req *mcp.GetPromptRequest, name string) (string, error) {
+	value := strings.TrimSpace(req.Params.Arguments[name])
+	if value == "" {
+		return "", fmt.Errorf("%s is required", name)
+	}
+	return value, nil
+}
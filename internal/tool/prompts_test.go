@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
+)
+
+// promptText returns the text of a prompt's single user message.
+func promptText(t *testing.T, result *mcp.GetPromptResult) string {
+	t.Helper()
+	require.Len(t, result.Messages, 1, "prompt should have a single message")
+	assert.Equal(t, mcp.Role("user"), result.Messages[0].Role)
+	content, ok := result.Messages[0].Content.(*mcp.TextContent)
+	require.True(t, ok, "prompt message should be text")
+	return content.Text
+}
+
+func TestPrompts(t *testing.T) {
+	loader := newTestSchemaLoader(t)
+
+	tests := []struct {
+		name        string
+		build       func(context.Context, *mcp.GetPromptRequest, *fetcher.CachedFetcher, *SchemaLoader) (*mcp.GetPromptResult, error)
+		arguments   map[string]string
+		wantErr     bool
+		errContains string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:      "draft control catalog",
+			build:     DraftControlCatalog,
+			arguments: map[string]string{"guidance": "title: Cloud Security Guidelines", "technology": "object storage"},
+			contains: []string{
+				"The controls apply to object storage",
+				"<guidance>\ntitle: Cloud Security Guidelines\n</guidance>",
+				"- **Control**: Safeguard or countermeasure",
+				"## #ControlCatalog (Layer 2)",
+				"## #MultiMapping",
+			},
+			notContains: []string{"**Assessment**:", "#EvaluationLog"},
+		},
+		{
+			name:      "write assessment requirements",
+			build:     WriteAssessmentRequirements,
+			arguments: map[string]string{"control": "id: CCC.C01\ntitle: Prevent Unencrypted Requests", "applicability": "tlp_green,tlp_amber"},
+			contains: []string{
+				"applicability categories: tlp_green,tlp_amber.",
+				"<control>\nid: CCC.C01\ntitle: Prevent Unencrypted Requests\n</control>",
+				"- **Assessment**: Atomic process",
+				"## #AssessmentRequirement (Layer 2)",
+			},
+		},
+		{
+			name:      "explain evaluation log",
+			build:     ExplainEvaluationLog,
+			arguments: map[string]string{"evaluation_log": "evaluations: []"},
+			contains: []string{
+				"<evaluation-log>\nevaluations: []\n</evaluation-log>",
+				"## #AssessmentLog (Layer 5)",
+				"## #Result (Layer 5)",
+				"# github.com/gemaraproj/gemara@v0.2.0",
+			},
+		},
+		{
+			name:        "missing required argument",
+			build:       ExplainEvaluationLog,
+			arguments:   map[string]string{"evaluation_log": "  "},
+			wantErr:     true,
+			errContains: "evaluation_log is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lexicon := fetcher.NewCachedFetcher(&mockFetcher{data: []byte(sampleLexicon), source: "mock://lexicon.yaml"}, fetcher.NewCache(time.Hour), "mock://lexicon.yaml")
+			req := &mcp.GetPromptRequest{Params: &mcp.GetPromptParams{Arguments: tt.arguments}}
+
+			result, err := tt.build(context.Background(), req, lexicon, loader)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			text := promptText(t, result)
+			for _, s := range tt.contains {
+				assert.Contains(t, text, s)
+			}
+			for _, s := range tt.notContains {
+				assert.NotContains(t, text, s)
+			}
+		})
+	}
+}
+
+func TestPromptsLexiconError(t *testing.T) {
+	lexicon := fetcher.NewCachedFetcher(&mockFetcher{err: assert.AnError}, fetcher.NewCache(time.Hour), "mock://lexicon.yaml")
+	req := &mcp.GetPromptRequest{Params: &mcp.GetPromptParams{Arguments: map[string]string{"control": "id: C01"}}}
+
+	_, err := WriteAssessmentRequirements(context.Background(), req, lexicon, newTestSchemaLoader(t))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load lexicon")
+}
+
+func TestPromptsOverSession(t *testing.T) {
+	session := newTestSession(t, newTestAdvisoryMode(t))
+
+	result, err := session.GetPrompt(context.Background(), &mcp.GetPromptParams{
+		Name:      "explain_evaluation_log",
+		Arguments: map[string]string{"evaluation_log": "evaluations: []"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, PromptExplainEvaluationLog.Description, result.Description)
+	assert.Contains(t, promptText(t, result), "- **Assessment**: Atomic process", "lexicon should be fetched from the configured source")
+}
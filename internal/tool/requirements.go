@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const formatCSV = "csv"
+
+// MetadataListAssessmentRequirements describes the ListAssessmentRequirements tool.
+var MetadataListAssessmentRequirements = &mcp.Tool{
+	Name:         "list_assessment_requirements",
+	Description:  "List the assessment requirements of a Gemara control catalog that apply in one or more applicability categories (e.g., every requirement that applies at TLP:Amber), grouped by family and control, as JSON, a markdown checklist or CSV.",
+	InputSchema:  withEnum(schemaFor[InputListAssessmentRequirements](), "format", formatJSON, formatMarkdown, formatCSV),
+	OutputSchema: schemaFor[OutputListAssessmentRequirements](),
+	Annotations:  readOnlyAnnotations(true),
+}
+
+// InputListAssessmentRequirements is the input for the ListAssessmentRequirements tool.
+type InputListAssessmentRequirements struct {
+	ArtifactContent string   `json:"artifact_content,omitempty" jsonschema:"YAML content of the control catalog"`
+	ArtifactSource  string   `json:"artifact_source,omitempty" jsonschema:"Location to load the control catalog from instead of artifact_content (e.g., 'oci://ghcr.io/org/catalogs:v1#ccc.yaml', 'https://...')"`
+	ArtifactID      string   `json:"artifact_id,omitempty" jsonschema:"Metadata ID of a control catalog in the client's workspace instead of artifact_content (e.g., 'FINOS-CCC')"`
+	Applicability   []string `json:"applicability" jsonschema:"IDs of applicability categories from metadata.applicability-categories; requirements that apply in any of them are listed (e.g., ['tlp_amber'])"`
+	Format          string   `json:"format,omitempty" jsonschema:"Output format (default: 'json')"`
+}
+
+// OutputListAssessmentRequirements is the output for the ListAssessmentRequirements tool.
+type OutputListAssessmentRequirements struct {
+	CatalogID string `json:"catalog_id"`
+	// Applicability are the requested categories as the catalog declares them.
+	Applicability []Category          `json:"applicability"`
+	Total         int                 `json:"total"`
+	Families      []RequirementFamily `json:"families,omitempty"`
+	// Document holds the requirements as a markdown checklist or CSV in those formats.
+	Document string `json:"document,omitempty"`
+	Source   string `json:"source,omitempty"`
+}
+
+// RequirementFamily holds the controls of a family with applicable requirements.
+type RequirementFamily struct {
+	ID       string               `json:"id"`
+	Title    string               `json:"title,omitempty"`
+	Controls []RequirementControl `json:"controls"`
+}
+
+// RequirementControl holds the applicable requirements of a control.
+type RequirementControl struct {
+	ID           string                  `json:"id"`
+	Title        string                  `json:"title"`
+	Requirements []AssessmentRequirement `json:"requirements"`
+}
+
+// ListAssessmentRequirements selects the assessment requirements of a control catalog
+// that apply in the requested applicability categories.
+func ListAssessmentRequirements(_ context.Context, _ *mcp.CallToolRequest, input InputListAssessmentRequirements) (*mcp.CallToolResult, OutputListAssessmentRequirements, error) {
+	format := input.Format
+	if format == "" {
+		format = formatJSON
+	}
+	if format != formatJSON && format != formatMarkdown && format != formatCSV {
+		return nil, OutputListAssessmentRequirements{}, fmt.Errorf("unsupported format %q: use %q, %q or %q", format, formatJSON, formatMarkdown, formatCSV)
+	}
+	if input.ArtifactContent == "" {
+		return nil, OutputListAssessmentRequirements{}, fmt.Errorf("artifact_content is required")
+	}
+	if len(input.Applicability) == 0 {
+		return nil, OutputListAssessmentRequirements{}, fmt.Errorf("applicability is required")
+	}
+
+	catalog, err := ParseControlCatalog([]byte(input.ArtifactContent))
+	if err != nil {
+		return nil, OutputListAssessmentRequirements{}, err
+	}
+	categories, err := applicabilityCategories(catalog, input.Applicability)
+	if err != nil {
+		return nil, OutputListAssessmentRequirements{}, err
+	}
+
+	output := OutputListAssessmentRequirements{
+		CatalogID:     catalog.Metadata.ID,
+		Applicability: categories,
+	}
+	families := applicableRequirements(catalog, input.Applicability)
+	for _, family := range families {
+		for _, control := range family.Controls {
+			output.Total += len(control.Requirements)
+		}
+	}
+
+	switch format {
+	case formatJSON:
+		output.Families = families
+	case formatMarkdown:
+		output.Document = renderRequirementChecklist(catalog, categories, families)
+	case formatCSV:
+		output.Document, err = renderRequirementCSV(families)
+		if err != nil {
+			return nil, OutputListAssessmentRequirements{}, err
+		}
+	}
+	return nil, output, nil
+}
+
+// applicabilityCategories looks up the requested categories in the catalog metadata.
+// Catalogs that declare no categories accept any category ID.
+func applicabilityCategories(catalog *ControlCatalog, ids []string) ([]Category, error) {
+	declared := catalog.Metadata.ApplicabilityCategories
+	var categories []Category
+	for _, id := range ids {
+		if len(declared) == 0 {
+			categories = append(categories, Category{ID: id})
+			continue
+		}
+		found := false
+		for _, category := range declared {
+			if strings.EqualFold(category.ID, id) {
+				categories = append(categories, category)
+				found = true
+				break
+			}
+		}
+		if !found {
+			known := make([]string, 0, len(declared))
+			for _, category := range declared {
+				known = append(known, category.ID)
+			}
+			return nil, fmt.Errorf("unknown applicability category %q: the catalog declares %s", id, strings.Join(known, ", "))
+		}
+	}
+	return categories, nil
+}
+
+// applicableRequirements groups the requirements that apply in any of the categories by
+// family and control, in catalog order.
+func applicableRequirements(catalog *ControlCatalog, categories []string) []RequirementFamily {
+	families := []RequirementFamily{}
+	index := make(map[string]int)
+	for _, control := range catalog.Controls {
+		var requirements []AssessmentRequirement
+		for _, requirement := range control.AssessmentRequirements {
+			for _, category := range categories {
+				if containsFold(requirement.Applicability, category) {
+					requirement.Text = compactText(requirement.Text)
+					requirements = append(requirements, requirement)
+					break
+				}
+			}
+		}
+		if len(requirements) == 0 {
+			continue
+		}
+
+		i, ok := index[control.Family]
+		if !ok {
+			family, _ := catalog.Family(control.Family)
+			i = len(families)
+			index[control.Family] = i
+			families = append(families, RequirementFamily{ID: control.Family, Title: family.Title})
+		}
+		families[i].Controls = append(families[i].Controls, RequirementControl{
+			ID:           control.ID,
+			Title:        compactText(control.Title),
+			Requirements: requirements,
+		})
+	}
+	return families
+}
+
+// renderRequirementChecklist renders requirements as a markdown checklist.
+func renderRequirementChecklist(catalog *ControlCatalog, categories []Category, families []RequirementFamily) string {
+	names := make([]string, 0, len(categories))
+	for _, category := range categories {
+		name := category.ID
+		if category.Title != "" {
+			name = category.Title
+		}
+		names = append(names, name)
+	}
+
+	var b strings.Builder
+	title := catalog.Title
+	if title == "" {
+		title = catalog.Metadata.ID
+	}
+	fmt.Fprintf(&b, "# %s: assessment requirements for %s\n", title, strings.Join(names, ", "))
+	if len(families) == 0 {
+		b.WriteString("\nNo assessment requirements apply.\n")
+	}
+	for _, family := range families {
+		b.WriteString("\n## ")
+		if family.Title != "" {
+			fmt.Fprintf(&b, "%s (%s)", family.Title, family.ID)
+		} else {
+			b.WriteString(family.ID)
+		}
+		b.WriteString("\n")
+		for _, control := range family.Controls {
+			fmt.Fprintf(&b, "\n### %s: %s\n\n", control.ID, control.Title)
+			for _, requirement := range control.Requirements {
+				fmt.Fprintf(&b, "- [ ] **%s** %s\n", requirement.ID, requirement.Text)
+			}
+		}
+	}
+	return b.String()
+}
+
+// renderRequirementCSV renders requirements as CSV with one row per requirement.
+func renderRequirementCSV(families []RequirementFamily) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	records := [][]string{{"family", "control", "control_title", "requirement", "text", "applicability"}}
+	for _, family := range families {
+		for _, control := range family.Controls {
+			for _, requirement := range control.Requirements {
+				records = append(records, []string{
+					family.ID,
+					control.ID,
+					control.Title,
+					requirement.ID,
+					requirement.Text,
+					strings.Join(requirement.Applicability, ";"),
+				})
+			}
+		}
+	}
+	if err := w.WriteAll(records); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return b.String(), nil
+}
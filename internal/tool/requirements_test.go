@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAssessmentRequirements(t *testing.T) {
+	catalog := readTestCatalog(t)
+
+	tests := []struct {
+		name           string
+		input          InputListAssessmentRequirements
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputListAssessmentRequirements)
+	}{
+		{
+			name:  "requirements of a category grouped by family and control",
+			input: InputListAssessmentRequirements{Applicability: []string{"tlp_clear"}},
+			validateOutput: func(t *testing.T, output OutputListAssessmentRequirements) {
+				assert.Equal(t, "FINOS-CCC", output.CatalogID)
+				assert.Equal(t, []Category{{ID: "tlp_clear", Title: "TLP:Clear", Description: "Information may be shared without restriction.\n"}}, output.Applicability)
+				assert.Equal(t, 7, output.Total)
+				require.Len(t, output.Families, 1)
+				assert.Equal(t, "Data Protection", output.Families[0].Title)
+
+				var controls []string
+				for _, control := range output.Families[0].Controls {
+					controls = append(controls, control.ID)
+				}
+				assert.Equal(t, []string{"CCC.C01", "CCC.C06", "CCC.C09"}, controls)
+				assert.Equal(t, AssessmentRequirement{
+					ID:            "CCC.C01.TR01",
+					Text:          "When a port is exposed for non-SSH network traffic, all traffic MUST include a TLS handshake AND be encrypted using TLS 1.2 or higher.",
+					Applicability: []string{"tlp_clear", "tlp_green", "tlp_amber", "tlp_red"},
+				}, output.Families[0].Controls[0].Requirements[0])
+				assert.Empty(t, output.Document)
+			},
+		},
+		{
+			name:  "requirements of any of several categories",
+			input: InputListAssessmentRequirements{Applicability: []string{"tlp_clear", "TLP_GREEN"}},
+			validateOutput: func(t *testing.T, output OutputListAssessmentRequirements) {
+				assert.Equal(t, 10, output.Total, "every requirement applies in one of the categories")
+				assert.Len(t, output.Families[0].Controls, 5)
+			},
+		},
+		{
+			name:  "markdown checklist",
+			input: InputListAssessmentRequirements{Applicability: []string{"tlp_amber"}, Format: formatMarkdown},
+			validateOutput: func(t *testing.T, output OutputListAssessmentRequirements) {
+				assert.Equal(t, 10, output.Total)
+				assert.Nil(t, output.Families)
+				assert.True(t, strings.HasPrefix(output.Document, "# FINOS Cloud Control Catalog: assessment requirements for TLP:Amber\n\n## Data Protection (data-protection)\n\n### CCC.C01: Prevent Unencrypted Requests\n\n- [ ] **CCC.C01.TR01** When a port"), output.Document)
+				assert.Contains(t, output.Document, "### CCC.C10: Prevent Data Replication to Destinations Outside of Defined Trust Perimeter\n")
+				assert.Equal(t, 10, strings.Count(output.Document, "- [ ] "))
+			},
+		},
+		{
+			name:  "CSV",
+			input: InputListAssessmentRequirements{Applicability: []string{"tlp_clear"}, Format: formatCSV},
+			validateOutput: func(t *testing.T, output OutputListAssessmentRequirements) {
+				records, err := csv.NewReader(strings.NewReader(output.Document)).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, records, 8, "header and one row per requirement")
+				assert.Equal(t, []string{"family", "control", "control_title", "requirement", "text", "applicability"}, records[0])
+				assert.Equal(t, "CCC.C06.TR02", records[4][3])
+				assert.Equal(t, "tlp_clear;tlp_green;tlp_amber;tlp_red", records[4][5])
+			},
+		},
+		{
+			name:        "unknown category",
+			input:       InputListAssessmentRequirements{Applicability: []string{"tlp_white"}},
+			wantErr:     true,
+			errContains: `unknown applicability category "tlp_white": the catalog declares tlp_clear, tlp_green, tlp_amber, tlp_red`,
+		},
+		{
+			name:        "missing category",
+			input:       InputListAssessmentRequirements{},
+			wantErr:     true,
+			errContains: "applicability is required",
+		},
+		{
+			name:        "unsupported format",
+			input:       InputListAssessmentRequirements{Applicability: []string{"tlp_red"}, Format: "xlsx"},
+			wantErr:     true,
+			errContains: `unsupported format "xlsx"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			input.ArtifactContent = catalog
+			_, output, err := ListAssessmentRequirements(context.Background(), nil, input)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			tt.validateOutput(t, output)
+		})
+	}
+}
+
+func TestListAssessmentRequirementsUndeclaredCategories(t *testing.T) {
+	catalog := `title: Minimal
+metadata:
+  id: MIN
+controls:
+  - id: MIN.C01
+    family: ops
+    title: Patch
+    objective: Keep systems patched.
+    assessment-requirements:
+      - id: MIN.C01.TR01
+        text: Patches MUST be applied within 30 days.
+        applicability: [prod]
+`
+	_, output, err := ListAssessmentRequirements(context.Background(), nil, InputListAssessmentRequirements{
+		ArtifactContent: catalog,
+		Applicability:   []string{"prod"},
+		Format:          formatMarkdown,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "# Minimal: assessment requirements for prod\n\n## ops\n\n### MIN.C01: Patch\n\n- [ ] **MIN.C01.TR01** Patches MUST be applied within 30 days.\n", output.Document)
+
+	_, output, err = ListAssessmentRequirements(context.Background(), nil, InputListAssessmentRequirements{
+		ArtifactContent: catalog,
+		Applicability:   []string{"dev"},
+		Format:          formatMarkdown,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, output.Total)
+	assert.Contains(t, output.Document, "No assessment requirements apply.")
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
+)
+
+const (
+	lexiconTermURIPrefix      = "gemara://lexicon/"
+	schemaDefinitionURIPrefix = "gemara://schema/"
+	artifactURIPrefix         = "gemara://artifact/"
+	mimeTypeMarkdown          = "text/markdown"
+	mimeTypeYAML              = "application/yaml"
+	mimeTypeJSON              = "application/json"
+)
+
+// ResourceLexiconTerm describes the ReadLexiconTerm resource template.
+var ResourceLexiconTerm = &mcp.ResourceTemplate{
+	Name:        "lexicon_term",
+	Title:       "Gemara lexicon term",
+	URITemplate: lexiconTermURIPrefix + "{term}",
+	Description: "Definition and references of a term of the Gemara Lexicon (e.g., 'gemara://lexicon/Control%20Catalog').",
+	MIMEType:    mimeTypeMarkdown,
+}
+
+// ResourceSchemaDefinition describes the ReadSchemaDefinition resource template.
+var ResourceSchemaDefinition = &mcp.ResourceTemplate{
+	Name:        "schema_definition",
+	Title:       "Gemara schema definition",
+	URITemplate: schemaDefinitionURIPrefix + "{version}/{definition}",
+	Description: "Documentation of a definition of the Gemara CUE schema at a module version or 'latest', named without its leading '#' (e.g., 'gemara://schema/latest/ControlCatalog').",
+	MIMEType:    mimeTypeMarkdown,
+}
+
+// ResourceWorkspaceArtifact describes the ReadWorkspaceArtifact resource template for whole artifacts.
+var ResourceWorkspaceArtifact = &mcp.ResourceTemplate{
+	Name:        "workspace_artifact",
+	Title:       "Gemara workspace artifact",
+	URITemplate: artifactURIPrefix + "{id}",
+	Description: "Content of a Gemara artifact in the client's workspace, addressed by its metadata ID (e.g., 'gemara://artifact/FINOS-CCC').",
+	MIMEType:    mimeTypeYAML,
+}
+
+// ResourceWorkspaceControl describes the ReadWorkspaceArtifact resource template for single controls.
+var ResourceWorkspaceControl = &mcp.ResourceTemplate{
+	Name:        "workspace_control",
+	Title:       "Control of a Gemara workspace artifact",
+	URITemplate: artifactURIPrefix + "{id}/controls/{control_id}",
+	Description: "A single control of a Gemara artifact in the client's workspace as YAML (e.g., 'gemara://artifact/FINOS-CCC/controls/CCC.C01').",
+	MIMEType:    mimeTypeYAML,
+}
+
+// ReadLexiconTerm reads a lexicon term resource using the specified cached fetcher.
+func ReadLexiconTerm(ctx context.Context, req *mcp.ReadResourceRequest, lexicon *fetcher.CachedFetcher) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	segments, err := uriSegments(uri, lexiconTermURIPrefix, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _, err := lexicon.Fetch(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lexicon: %w", err)
+	}
+	entries, err := parseLexicon(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !strings.EqualFold(entry.Term, segments[0]) {
+			continue
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %s\n\n%s\n", entry.Term, strings.TrimSpace(entry.Definition))
+		if len(entry.References) > 0 {
+			b.WriteString("\nReferences:\n\n")
+			for _, ref := range entry.References {
+				fmt.Fprintf(&b, "- %s\n", ref)
+			}
+		}
+		return markdownResource(uri, b.String()), nil
+	}
+	return nil, mcp.ResourceNotFoundError(uri)
+}
+
+// ReadSchemaDefinition reads a schema definition resource using the specified schema loader.
+func ReadSchemaDefinition(ctx context.Context, req *mcp.ReadResourceRequest, loader *SchemaLoader) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	segments, err := uriSegments(uri, schemaDefinitionURIPrefix, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := loader.Load(ctx, segments[0], false)
+	if err != nil {
+		return nil, err
+	}
+	v, name, err := lookupDefinition(schema.Value, segments[1])
+	if err != nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	return markdownResource(uri, renderSchemaDocs(schema.Version, []DefinitionDoc{describeDefinition(name, v)})), nil
+}
+
+// ReadWorkspaceArtifact reads an artifact or control resource from the specified workspace index.
+func ReadWorkspaceArtifact(_ context.Context, req *mcp.ReadResourceRequest, index *WorkspaceIndex) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	segments, err := uriSegments(uri, artifactURIPrefix, 1)
+	if err != nil {
+		segments, err = uriSegments(uri, artifactURIPrefix, 3)
+		if err != nil || segments[1] != "controls" {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+	}
+
+	artifact, ok := index.Artifact(segments[0])
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	if len(segments) == 1 {
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: artifactMIMEType(artifact.Path), Text: string(artifact.Content)}},
+		}, nil
+	}
+
+	control, ok := artifact.Control(segments[2])
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: mimeTypeYAML, Text: string(control)}},
+	}, nil
+}
+
+// artifactURI returns the URI of an artifact resource.
+func artifactURI(id string) string {
+	return artifactURIPrefix + url.PathEscape(id)
+}
+
+// controlURI returns the URI of the resource of a control of an artifact.
+func controlURI(id, controlID string) string {
+	return artifactURI(id) + "/controls/" + url.PathEscape(controlID)
+}
+
+// artifactMIMEType returns the media type of an artifact file.
+func artifactMIMEType(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return mimeTypeJSON
+	}
+	return mimeTypeYAML
+}
+
+// uriSegments returns the n unescaped path segments that follow prefix in uri.
+func uriSegments(uri, prefix string, n int) ([]string, error) {
+	rest, ok := strings.CutPrefix(uri, prefix)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	segments := strings.Split(rest, "/")
+	if len(segments) != n {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil || unescaped == "" {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		segments[i] = unescaped
+	}
+	return segments, nil
+}
+
+func markdownResource(uri, text string) *mcp.ReadResourceResult {
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: mimeTypeMarkdown, Text: text}},
+	}
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceTemplates(t *testing.T) {
+	session := newTestSession(t, newTestAdvisoryMode(t))
+	ctx := context.Background()
+
+	templates, err := session.ListResourceTemplates(ctx, nil)
+	require.NoError(t, err)
+	var uriTemplates []string
+	for _, template := range templates.ResourceTemplates {
+		uriTemplates = append(uriTemplates, template.URITemplate)
+	}
+	assert.ElementsMatch(t, []string{
+		"gemara://lexicon/{term}",
+		"gemara://schema/{version}/{definition}",
+		"gemara://artifact/{id}",
+		"gemara://artifact/{id}/controls/{control_id}",
+	}, uriTemplates)
+
+	tests := []struct {
+		name         string
+		uri          string
+		wantNotFound bool
+		wantErr      bool
+		contains     []string
+	}{
+		{
+			name:     "lexicon term",
+			uri:      "gemara://lexicon/control",
+			contains: []string{"# Control\n\nSafeguard or countermeasure\n", "- Layer 2"},
+		},
+		{
+			name:         "unknown lexicon term",
+			uri:          "gemara://lexicon/Policy",
+			wantNotFound: true,
+		},
+		{
+			name:     "schema definition",
+			uri:      "gemara://schema/v0.1.0/ControlCatalog",
+			contains: []string{"# github.com/gemaraproj/gemara@v0.1.0", "## #ControlCatalog (Layer 2)", "| `controls` |"},
+		},
+		{
+			name:     "escaped schema definition of the latest version",
+			uri:      "gemara://schema/latest/%23EvaluationPlan",
+			contains: []string{"# github.com/gemaraproj/gemara@v0.2.0", "## #EvaluationPlan (Layer 4)"},
+		},
+		{
+			name:         "unknown schema definition",
+			uri:          "gemara://schema/v0.0.1/ThreatCatalog",
+			wantNotFound: true,
+		},
+		{
+			name:         "artifact outside the workspace index",
+			uri:          "gemara://artifact/FINOS-CCC",
+			wantNotFound: true,
+		},
+		{
+			name:    "invalid schema version",
+			uri:     "gemara://schema/main/Control",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: tt.uri})
+			if tt.wantNotFound || tt.wantErr {
+				require.Error(t, err, "should return error")
+				if tt.wantNotFound {
+					assert.Contains(t, err.Error(), "Resource not found")
+				}
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			require.Len(t, result.Contents, 1)
+			assert.Equal(t, tt.uri, result.Contents[0].URI)
+			assert.Equal(t, "text/markdown", result.Contents[0].MIMEType)
+			for _, s := range tt.contains {
+				assert.Contains(t, result.Contents[0].Text, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"cuelang.org/go/cue"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	changeAdded   = "added"
+	changeRemoved = "removed"
+	changeChanged = "changed"
+)
+
+// MetadataDiffSchemaVersions describes the DiffSchemaVersions tool.
+var MetadataDiffSchemaVersions = &mcp.Tool{
+	Name:         "diff_schema_versions",
+	Description:  "Compare two versions of the Gemara CUE schema and report added, removed and changed definitions, fields and constraints, flagging changes that break existing artifacts.",
+	InputSchema:  schemaFor[InputDiffSchemaVersions](),
+	OutputSchema: schemaFor[OutputDiffSchemaVersions](),
+	Annotations:  readOnlyAnnotations(false),
+}
+
+// InputDiffSchemaVersions is the input for the DiffSchemaVersions tool.
+type InputDiffSchemaVersions struct {
+	From         string `json:"from" jsonschema:"Version of the Gemara module to compare from (e.g., 'v0.1.0')"`
+	To           string `json:"to,omitempty" jsonschema:"Version of the Gemara module to compare to (default: 'latest')"`
+	BreakingOnly bool   `json:"breaking_only,omitempty" jsonschema:"Only report breaking changes (default: false)"`
+}
+
+// OutputDiffSchemaVersions is the output for the DiffSchemaVersions tool.
+type OutputDiffSchemaVersions struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Breaking reports whether any artifact valid against From may be rejected by To.
+	Breaking    bool             `json:"breaking"`
+	Definitions []DefinitionDiff `json:"definitions"`
+}
+
+// DefinitionDiff lists the changes to a single definition.
+type DefinitionDiff struct {
+	Name     string         `json:"name"`
+	Layer    int            `json:"layer,omitempty"`
+	Change   string         `json:"change"`
+	Breaking bool           `json:"breaking"`
+	Changes  []SchemaChange `json:"changes,omitempty"`
+}
+
+// SchemaChange describes a change to a definition or one of its fields.
+type SchemaChange struct {
+	// Field is empty for changes to the definition's own constraint.
+	Field    string `json:"field,omitempty"`
+	Change   string `json:"change"`
+	Breaking bool   `json:"breaking"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+	Detail   string `json:"detail"`
+}
+
+// DiffSchemaVersions compares two versions of the module loaded by the specified schema loader.
+func DiffSchemaVersions(ctx context.Context, _ *mcp.CallToolRequest, input InputDiffSchemaVersions, loader *SchemaLoader) (*mcp.CallToolResult, OutputDiffSchemaVersions, error) {
+	if input.From == "" {
+		return nil, OutputDiffSchemaVersions{}, fmt.Errorf("from is required")
+	}
+
+	schemas, err := loader.LoadVersions(ctx, input.From, input.To)
+	if err != nil {
+		return nil, OutputDiffSchemaVersions{}, err
+	}
+	before, after := schemas[0], schemas[1]
+
+	diffs, err := diffSchemas(before.Value, after.Value)
+	if err != nil {
+		return nil, OutputDiffSchemaVersions{}, err
+	}
+
+	output := OutputDiffSchemaVersions{
+		From:        before.Version,
+		To:          after.Version,
+		Definitions: []DefinitionDiff{},
+	}
+	for _, diff := range diffs {
+		output.Breaking = output.Breaking || diff.Breaking
+		if input.BreakingOnly {
+			if !diff.Breaking {
+				continue
+			}
+			diff.Changes = breakingChanges(diff.Changes)
+		}
+		output.Definitions = append(output.Definitions, diff)
+	}
+	return nil, output, nil
+}
+
+// diffSchemas compares the definitions of two schemas built in the same CUE context.
+func diffSchemas(before, after cue.Value) ([]DefinitionDiff, error) {
+	beforeDefs, err := schemaDefinitions(before)
+	if err != nil {
+		return nil, err
+	}
+	afterDefs, err := schemaDefinitions(after)
+	if err != nil {
+		return nil, err
+	}
+
+	afterLayers := make(map[string]int, len(afterDefs))
+	for _, def := range afterDefs {
+		afterLayers[def.Name] = def.Layer
+	}
+
+	var diffs []DefinitionDiff
+	seen := make(map[string]bool, len(beforeDefs))
+	for _, def := range beforeDefs {
+		seen[def.Name] = true
+		if _, ok := afterLayers[def.Name]; !ok {
+			diffs = append(diffs, DefinitionDiff{
+				Name:     def.Name,
+				Layer:    def.Layer,
+				Change:   changeRemoved,
+				Breaking: true,
+			})
+			continue
+		}
+
+		path := cue.ParsePath(def.Name)
+		changes := diffDefinition(before.LookupPath(path), after.LookupPath(path))
+		if len(changes) == 0 {
+			continue
+		}
+		diff := DefinitionDiff{
+			Name:    def.Name,
+			Layer:   afterLayers[def.Name],
+			Change:  changeChanged,
+			Changes: changes,
+		}
+		for _, c := range changes {
+			diff.Breaking = diff.Breaking || c.Breaking
+		}
+		diffs = append(diffs, diff)
+	}
+	for _, def := range afterDefs {
+		if !seen[def.Name] {
+			diffs = append(diffs, DefinitionDiff{
+				Name:   def.Name,
+				Layer:  def.Layer,
+				Change: changeAdded,
+			})
+		}
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].Layer != diffs[j].Layer {
+			return diffs[i].Layer < diffs[j].Layer
+		}
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs, nil
+}
+
+// diffDefinition compares two versions of a definition field by field. Fields that keep
+// referencing the same definition are not compared, as changes to the referenced
+// definition are reported on their own.
+func diffDefinition(before, after cue.Value) []SchemaChange {
+	if before.IncompleteKind() != cue.StructKind || after.IncompleteKind() != cue.StructKind {
+		beforeExpr, afterExpr := sourceExpr(before), sourceExpr(after)
+		if beforeExpr == afterExpr {
+			return nil
+		}
+		return []SchemaChange{constraintChange("", before, after, beforeExpr, afterExpr)}
+	}
+
+	beforeFields := structFields(before)
+	afterFields := structFields(after)
+
+	var changes []SchemaChange
+	for _, name := range beforeFields.names {
+		b := beforeFields.values[name]
+		a, ok := afterFields.values[name]
+		if !ok {
+			changes = append(changes, SchemaChange{
+				Field:    name,
+				Change:   changeRemoved,
+				Breaking: true,
+				Before:   b.doc.Type,
+				Detail:   "field is no longer allowed; artifacts that set it are rejected",
+			})
+			continue
+		}
+
+		if b.doc.Required != a.doc.Required {
+			change := SchemaChange{
+				Field:  name,
+				Change: changeChanged,
+				Before: requiredness(b.doc.Required),
+				After:  requiredness(a.doc.Required),
+				Detail: "field is now optional",
+			}
+			if a.doc.Required {
+				change.Breaking = a.doc.Default == ""
+				change.Detail = "field is now required"
+			}
+			changes = append(changes, change)
+		}
+
+		beforeExpr, afterExpr := sourceExpr(b.value), sourceExpr(a.value)
+		if beforeExpr != afterExpr {
+			changes = append(changes, constraintChange(name, b.value, a.value, beforeExpr, afterExpr))
+		}
+	}
+	for _, name := range afterFields.names {
+		if _, ok := beforeFields.values[name]; ok {
+			continue
+		}
+		a := afterFields.values[name]
+		change := SchemaChange{
+			Field:  name,
+			Change: changeAdded,
+			After:  a.doc.Type,
+			Detail: "new optional field",
+		}
+		if a.doc.Required {
+			change.Breaking = a.doc.Default == ""
+			change.Detail = "new required field"
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// constraintChange describes a changed declaration, which breaks existing artifacts
+// unless the new declaration accepts every value the old one did.
+func constraintChange(field string, before, after cue.Value, beforeExpr, afterExpr string) SchemaChange {
+	change := SchemaChange{
+		Field:  field,
+		Change: changeChanged,
+		Before: beforeExpr,
+		After:  afterExpr,
+		Detail: "constraint accepts all previously valid values",
+	}
+	if err := after.Subsume(before); err != nil {
+		change.Breaking = true
+		change.Detail = "constraint rejects previously valid values"
+	}
+	return change
+}
+
+func requiredness(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
+
+func breakingChanges(changes []SchemaChange) []SchemaChange {
+	var breaking []SchemaChange
+	for _, c := range changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// structField is a field of a definition with its documentation.
+type structField struct {
+	value cue.Value
+	doc   FieldDoc
+}
+
+// fieldSet holds the fields of a definition in declaration order.
+type fieldSet struct {
+	names  []string
+	values map[string]structField
+}
+
+func structFields(v cue.Value) fieldSet {
+	fields := fieldSet{values: make(map[string]structField)}
+	it, err := v.Fields(cue.Optional(true))
+	if err != nil {
+		return fields
+	}
+	for it.Next() {
+		doc := describeField(it.Selector(), it.Value())
+		fields.names = append(fields.names, doc.Name)
+		fields.values[doc.Name] = structField{value: it.Value(), doc: doc}
+	}
+	return fields
+}
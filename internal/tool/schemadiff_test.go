@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findDefinitionDiff returns the diff for the named definition, if reported.
+func findDefinitionDiff(diffs []DefinitionDiff, name string) (DefinitionDiff, bool) {
+	for _, diff := range diffs {
+		if diff.Name == name {
+			return diff, true
+		}
+	}
+	return DefinitionDiff{}, false
+}
+
+func TestDiffSchemaVersions(t *testing.T) {
+	loader := newTestSchemaLoader(t)
+
+	tests := []struct {
+		name           string
+		input          InputDiffSchemaVersions
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputDiffSchemaVersions)
+	}{
+		{
+			name:  "upgrade to latest",
+			input: InputDiffSchemaVersions{From: "v0.1.0"},
+			validateOutput: func(t *testing.T, output OutputDiffSchemaVersions) {
+				assert.Equal(t, "v0.1.0", output.From)
+				assert.Equal(t, "v0.2.0", output.To, "to should default to the latest version")
+				assert.True(t, output.Breaking)
+
+				removed, ok := findDefinitionDiff(output.Definitions, "#Capability")
+				require.True(t, ok, "removed definition should be reported")
+				assert.Equal(t, DefinitionDiff{Name: "#Capability", Layer: 2, Change: "removed", Breaking: true}, removed)
+
+				added, ok := findDefinitionDiff(output.Definitions, "#EvaluationPlan")
+				require.True(t, ok, "added definition should be reported")
+				assert.Equal(t, DefinitionDiff{Name: "#EvaluationPlan", Layer: 4, Change: "added"}, added)
+
+				_, ok = findDefinitionDiff(output.Definitions, "#ControlCatalog")
+				assert.False(t, ok, "unchanged definitions should be omitted")
+			},
+		},
+		{
+			name:  "field changes",
+			input: InputDiffSchemaVersions{From: "v0.1.0", To: "v0.2.0"},
+			validateOutput: func(t *testing.T, output OutputDiffSchemaVersions) {
+				tests := []struct {
+					definition string
+					want       SchemaChange
+				}{
+					{
+						definition: "#Actor",
+						want:       SchemaChange{Field: "uri", Change: "removed", Breaking: true, Before: "#URL", Detail: "field is no longer allowed; artifacts that set it are rejected"},
+					},
+					{
+						definition: "#Metadata",
+						want:       SchemaChange{Field: "lexicon", Change: "added", After: "#URL", Detail: "new optional field"},
+					},
+					{
+						definition: "#Threat",
+						want:       SchemaChange{Field: "capabilities", Change: "changed", Breaking: true, Before: "optional", After: "required", Detail: "field is now required"},
+					},
+					{
+						definition: "#MappingReference",
+						want:       SchemaChange{Field: "version", Change: "changed", Before: "required", After: "optional", Detail: "field is now optional"},
+					},
+					{
+						definition: "#MappingEntry",
+						want:       SchemaChange{Field: "strength", Change: "changed", Breaking: true, Before: "int & >=0 & <=10 | *0", After: "int & >=1 & <=10 | *1", Detail: "constraint rejects previously valid values"},
+					},
+					{
+						definition: "#ThreatCatalog",
+						want:       SchemaChange{Field: "capabilities", Change: "changed", Before: "[...#Capability]", After: "[...#Feature]", Detail: "constraint accepts all previously valid values"},
+					},
+				}
+				for _, tt := range tests {
+					diff, ok := findDefinitionDiff(output.Definitions, tt.definition)
+					require.True(t, ok, "%s should be reported", tt.definition)
+					assert.Equal(t, []SchemaChange{tt.want}, diff.Changes, "changes to %s", tt.definition)
+					assert.Equal(t, tt.want.Breaking, diff.Breaking, "%s breaking", tt.definition)
+				}
+			},
+		},
+		{
+			name:  "widened enum is not breaking",
+			input: InputDiffSchemaVersions{From: "v0.1.0", To: "v0.2.0"},
+			validateOutput: func(t *testing.T, output OutputDiffSchemaVersions) {
+				diff, ok := findDefinitionDiff(output.Definitions, "#Result")
+				require.True(t, ok)
+				require.Len(t, diff.Changes, 1)
+				assert.Empty(t, diff.Changes[0].Field, "constraint of the definition itself should have no field")
+				assert.False(t, diff.Breaking)
+			},
+		},
+		{
+			name:  "downgrade reverses breaking changes",
+			input: InputDiffSchemaVersions{From: "v0.2.0", To: "v0.1.0"},
+			validateOutput: func(t *testing.T, output OutputDiffSchemaVersions) {
+				result, ok := findDefinitionDiff(output.Definitions, "#Result")
+				require.True(t, ok)
+				assert.True(t, result.Breaking, "narrowed enum should be breaking")
+
+				entry, ok := findDefinitionDiff(output.Definitions, "#MappingEntry")
+				require.True(t, ok)
+				assert.False(t, entry.Breaking, "widened range should not be breaking")
+			},
+		},
+		{
+			name:  "breaking only",
+			input: InputDiffSchemaVersions{From: "v0.1.0", BreakingOnly: true},
+			validateOutput: func(t *testing.T, output OutputDiffSchemaVersions) {
+				var names []string
+				for _, diff := range output.Definitions {
+					names = append(names, diff.Name)
+					for _, c := range diff.Changes {
+						assert.True(t, c.Breaking, "only breaking changes should be reported")
+					}
+				}
+				assert.Equal(t, []string{"#Actor", "#MappingEntry", "#Capability", "#Threat"}, names)
+			},
+		},
+		{
+			name:  "same version",
+			input: InputDiffSchemaVersions{From: "v0.2.0", To: "latest"},
+			validateOutput: func(t *testing.T, output OutputDiffSchemaVersions) {
+				assert.False(t, output.Breaking)
+				assert.Empty(t, output.Definitions)
+			},
+		},
+		{
+			name:        "missing from",
+			input:       InputDiffSchemaVersions{To: "v0.2.0"},
+			wantErr:     true,
+			errContains: "from is required",
+		},
+		{
+			name:        "unknown version",
+			input:       InputDiffSchemaVersions{From: "v9.9.9"},
+			wantErr:     true,
+			errContains: "failed to load module",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := DiffSchemaVersions(context.Background(), nil, tt.input, loader)
+
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			if tt.validateOutput != nil {
+				tt.validateOutput(t, output)
+			}
+		})
+	}
+}
@@ -4,53 +4,155 @@ package tool
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
-	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+const (
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
 // OutputGetSchemaDocs is the output for the GetSchemaDocs tool.
 type OutputGetSchemaDocs struct {
-	Documentation string `json:"documentation"`
-	URL           string `json:"url"`
+	Version       string          `json:"version"`
+	Definitions   []DefinitionDoc `json:"definitions,omitempty"`
+	Documentation string          `json:"documentation,omitempty"`
+	// Digest is the SHA-256 digest of the file listing of the documented module version,
+	// which identifies its content.
+	Digest string `json:"digest"`
 }
 
 // MetadataGetSchemaDocs describes the GetSchemaDocs tool.
 var MetadataGetSchemaDocs = &mcp.Tool{
-	Name:        "get_schema_docs",
-	Description: "Retrieve schema documentation for the Gemara CUE module from the CUE registry.",
-	InputSchema: map[string]interface{}{
-		"type": "object",
-		"properties": map[string]interface{}{
-			"refresh": map[string]interface{}{
-				"type":        "boolean",
-				"description": "Force refresh of schema docs cache (default: false)",
-			},
-			"version": map[string]interface{}{
-				"type":        "string",
-				"description": "Version of the Gemara module (default: 'latest')",
-			},
-		},
-	},
+	Name:         "get_schema_docs",
+	Description:  "Document the definitions of the Gemara CUE schema: fields, types, constraints, defaults and doc comments.",
+	InputSchema:  withEnum(schemaFor[InputGetSchemaDocs](), "format", formatMarkdown, formatJSON),
+	OutputSchema: schemaFor[OutputGetSchemaDocs](),
+	Annotations:  readOnlyAnnotations(false),
 }
 
 // InputGetSchemaDocs is the input for the GetSchemaDocs tool.
 type InputGetSchemaDocs struct {
-	Refresh bool   `json:"refresh"`
-	Version string `json:"version"`
+	Refresh    bool   `json:"refresh,omitempty" jsonschema:"Reload the schema module from the registry (default: false)"`
+	Version    string `json:"version,omitempty" jsonschema:"Version of the Gemara module (default: 'latest')"`
+	Definition string `json:"definition,omitempty" jsonschema:"Only document this definition (e.g., '#ControlCatalog')"`
+	Field      string `json:"field,omitempty" jsonschema:"Only document fields with this name"`
+	Format     string `json:"format,omitempty" jsonschema:"Output format (default: 'markdown')"`
 }
 
-// GetSchemaDocs retrieves schema documentation using the specified cached fetcher.
-func GetSchemaDocs(ctx context.Context, _ *mcp.CallToolRequest, input InputGetSchemaDocs, cachedFetcher *fetcher.CachedFetcher) (*mcp.CallToolResult, OutputGetSchemaDocs, error) {
-	data, sourceID, err := cachedFetcher.Fetch(ctx, input.Refresh)
+// GetSchemaDocs generates schema documentation from the module loaded by the specified schema loader.
+func GetSchemaDocs(ctx context.Context, _ *mcp.CallToolRequest, input InputGetSchemaDocs, loader *SchemaLoader) (*mcp.CallToolResult, OutputGetSchemaDocs, error) {
+	format := input.Format
+	if format == "" {
+		format = formatMarkdown
+	}
+	if format != formatMarkdown && format != formatJSON {
+		return nil, OutputGetSchemaDocs{}, fmt.Errorf("unsupported format %q: use %q or %q", format, formatMarkdown, formatJSON)
+	}
+
+	schema, err := loader.Load(ctx, input.Version, input.Refresh)
 	if err != nil {
 		return nil, OutputGetSchemaDocs{}, err
 	}
 
-	output := OutputGetSchemaDocs{
-		Documentation: string(data),
-		URL:           sourceID,
+	var defs []DefinitionDoc
+	if input.Definition != "" {
+		v, name, err := lookupDefinition(schema.Value, input.Definition)
+		if err != nil {
+			return nil, OutputGetSchemaDocs{}, err
+		}
+		defs = []DefinitionDoc{describeDefinition(name, v)}
+	} else {
+		defs, err = schemaDefinitions(schema.Value)
+		if err != nil {
+			return nil, OutputGetSchemaDocs{}, err
+		}
 	}
 
+	if input.Field != "" {
+		defs = filterFields(defs, input.Field)
+		if len(defs) == 0 {
+			return nil, OutputGetSchemaDocs{}, fmt.Errorf("field %q not found in schema", input.Field)
+		}
+	}
+
+	output := OutputGetSchemaDocs{Version: schema.Version, Digest: schema.Digest}
+	if format == formatJSON {
+		output.Definitions = defs
+	} else {
+		output.Documentation = renderSchemaDocs(schema.Version, defs)
+	}
 	return nil, output, nil
 }
+
+// filterFields keeps only fields with the given name, dropping definitions without them.
+func filterFields(defs []DefinitionDoc, field string) []DefinitionDoc {
+	var filtered []DefinitionDoc
+	for _, def := range defs {
+		var fields []FieldDoc
+		for _, f := range def.Fields {
+			if f.Name == field {
+				fields = append(fields, f)
+			}
+		}
+		if len(fields) > 0 {
+			def.Fields = fields
+			filtered = append(filtered, def)
+		}
+	}
+	return filtered
+}
+
+// renderSchemaDocs renders definitions as markdown.
+func renderSchemaDocs(version string, defs []DefinitionDoc) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s@%s\n", gemaraModulePath, version)
+
+	for _, def := range defs {
+		b.WriteString("\n## " + def.Name)
+		if def.Layer > 0 {
+			fmt.Fprintf(&b, " (Layer %d)", def.Layer)
+		}
+		b.WriteString("\n\n")
+		if def.Doc != "" {
+			b.WriteString(def.Doc + "\n\n")
+		}
+
+		if len(def.Fields) == 0 {
+			fmt.Fprintf(&b, "Type: `%s`\n", def.Type)
+			if def.Constraint != "" {
+				fmt.Fprintf(&b, "\nConstraint: `%s`\n", def.Constraint)
+			}
+			continue
+		}
+
+		b.WriteString("| Field | Type | Required | Constraint | Default | Description |\n")
+		b.WriteString("|---|---|---|---|---|---|\n")
+		for _, f := range def.Fields {
+			required := "no"
+			if f.Required {
+				required = "yes"
+			}
+			fmt.Fprintf(&b, "| `%s` | `%s` | %s | %s | %s | %s |\n",
+				f.Name, tableCell(f.Type), required, codeCell(f.Constraint), codeCell(f.Default), tableCell(f.Doc))
+		}
+	}
+	return b.String()
+}
+
+// tableCell escapes text for use in a markdown table cell.
+func tableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// codeCell formats text as inline code in a markdown table cell.
+func codeCell(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + tableCell(s) + "`"
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"cuelang.org/go/mod/modconfig"
+	"cuelang.org/go/mod/modregistrytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
+)
+
+// newTestSchemaLoader serves the stand-in Gemara modules under testdata/registry from a
+// local registry, so schema tests run without network access.
+func newTestSchemaLoader(t *testing.T) *SchemaLoader {
+	t.Helper()
+	reg, err := modregistrytest.New(os.DirFS("testdata/registry"), "")
+	require.NoError(t, err, "should start local registry")
+	t.Cleanup(reg.Close)
+
+	return NewSchemaLoader(&modconfig.Config{
+		Env: []string{
+			"CUE_REGISTRY=" + reg.Host() + "+insecure",
+			"CUE_CACHE_DIR=" + t.TempDir(),
+		},
+	})
+}
+
+func TestGetSchemaDocs(t *testing.T) {
+	loader := newTestSchemaLoader(t)
+
+	tests := []struct {
+		name           string
+		input          InputGetSchemaDocs
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputGetSchemaDocs)
+	}{
+		{
+			name:  "markdown for all definitions",
+			input: InputGetSchemaDocs{},
+			validateOutput: func(t *testing.T, output OutputGetSchemaDocs) {
+				assert.Equal(t, "v0.2.0", output.Version, "latest should resolve to the highest published version")
+				assert.Regexp(t, "^sha256:[0-9a-f]{64}$", output.Digest, "the digest of the module version should be reported")
+				assert.Empty(t, output.Definitions, "markdown output should not include structured definitions")
+				assert.Contains(t, output.Documentation, "# github.com/gemaraproj/gemara@v0.2.0")
+				assert.Contains(t, output.Documentation, "## #ControlCatalog (Layer 2)")
+				assert.Contains(t, output.Documentation, "## #EvaluationLog (Layer 5)")
+				assert.Contains(t, output.Documentation, "| `objective` | `string` | yes |  |  | What the control is intended to achieve. |")
+				assert.Contains(t, output.Documentation, "Constraint: `\"Not Run\" | \"Passed\"")
+				assert.Contains(t, output.Documentation, "| `strength` | `int` | yes | `int & >=1 & <=10 \\| *1` | `1` |", "pipes in table cells should be escaped")
+			},
+		},
+		{
+			name:  "json for a single definition",
+			input: InputGetSchemaDocs{Definition: "Control", Format: "json", Version: "v0.1.0"},
+			validateOutput: func(t *testing.T, output OutputGetSchemaDocs) {
+				require.Len(t, output.Definitions, 1)
+				def := output.Definitions[0]
+				assert.Equal(t, "#Control", def.Name)
+				assert.Equal(t, 2, def.Layer)
+				assert.Equal(t, "Control is a safeguard or countermeasure with testable assessment requirements.", def.Doc)
+
+				fields := make(map[string]FieldDoc)
+				for _, f := range def.Fields {
+					fields[f.Name] = f
+				}
+				assert.Equal(t, FieldDoc{Name: "threat-mappings", Type: "[...#MultiMapping]"}, fields["threat-mappings"])
+				assert.True(t, fields["assessment-requirements"].Required)
+				assert.Equal(t, "[...#AssessmentRequirement]", fields["assessment-requirements"].Type)
+			},
+		},
+		{
+			name:  "constraints and defaults",
+			input: InputGetSchemaDocs{Definition: "#MappingEntry", Format: "json", Version: "v0.1.0"},
+			validateOutput: func(t *testing.T, output OutputGetSchemaDocs) {
+				require.Len(t, output.Definitions, 1)
+				var strength FieldDoc
+				for _, f := range output.Definitions[0].Fields {
+					if f.Name == "strength" {
+						strength = f
+					}
+				}
+				assert.Equal(t, "int", strength.Type)
+				assert.Equal(t, "int & >=0 & <=10 | *0", strength.Constraint)
+				assert.Equal(t, "0", strength.Default)
+			},
+		},
+		{
+			name:  "field filter across definitions",
+			input: InputGetSchemaDocs{Field: "applicability", Format: "json"},
+			validateOutput: func(t *testing.T, output OutputGetSchemaDocs) {
+				var names []string
+				for _, def := range output.Definitions {
+					names = append(names, def.Name)
+					require.Len(t, def.Fields, 1)
+					assert.Equal(t, "applicability", def.Fields[0].Name)
+				}
+				assert.ElementsMatch(t, []string{"#AssessmentRequirement", "#AssessmentLog", "#Scope"}, names)
+			},
+		},
+		{
+			name:        "unknown definition",
+			input:       InputGetSchemaDocs{Definition: "#Nope"},
+			wantErr:     true,
+			errContains: "definition #Nope not found",
+		},
+		{
+			name:        "unknown field",
+			input:       InputGetSchemaDocs{Definition: "#Control", Field: "nope"},
+			wantErr:     true,
+			errContains: `field "nope" not found`,
+		},
+		{
+			name:        "unsupported format",
+			input:       InputGetSchemaDocs{Format: "html"},
+			wantErr:     true,
+			errContains: "unsupported format",
+		},
+		{
+			name:        "unknown version",
+			input:       InputGetSchemaDocs{Version: "v9.9.9"},
+			wantErr:     true,
+			errContains: "failed to load module",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := GetSchemaDocs(context.Background(), nil, tt.input, loader)
+
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			if tt.validateOutput != nil {
+				tt.validateOutput(t, output)
+			}
+		})
+	}
+}
+
+func TestSchemaLoaderVerification(t *testing.T) {
+	ctx := context.Background()
+	schema, err := newTestSchemaLoader(t).Load(ctx, "v0.1.0", false)
+	require.NoError(t, err)
+	digest := schema.Digest
+
+	checksums := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s  gemara@v0.1.0\n", strings.TrimPrefix(digest, "sha256:"))
+	}))
+	t.Cleanup(checksums.Close)
+	client, err := fetcher.NewClient(fetcher.ClientOptions{Timeout: time.Second})
+	require.NoError(t, err)
+
+	pinned := SourceChecks{SHA256: map[string]string{SchemaModuleReference("v0.1.0"): digest}}
+	schema, err = newTestSchemaLoader(t).WithVerification(pinned, client).Load(ctx, "v0.1.0", false)
+	require.NoError(t, err)
+	assert.Equal(t, digest, schema.Digest, "the digest should not depend on the registry the module is loaded from")
+
+	_, err = newTestSchemaLoader(t).WithVerification(pinned, client).Load(ctx, "v0.2.0", false)
+	require.NoError(t, err, "versions without a pinned digest should load")
+
+	mismatched := SourceChecks{SHA256: map[string]string{SchemaModuleReference("v0.2.0"): digest}}
+	_, err = newTestSchemaLoader(t).WithVerification(mismatched, client).Load(ctx, "latest", false)
+	require.ErrorIs(t, err, fetcher.ErrIntegrity)
+	assert.Contains(t, err.Error(), "github.com/gemaraproj/gemara@v0.2.0")
+
+	listed := SourceChecks{ChecksumsURL: checksums.URL + "/SHA256SUMS"}
+	loader := newTestSchemaLoader(t).WithVerification(listed, client)
+	_, err = loader.Load(ctx, "v0.1.0", false)
+	require.NoError(t, err)
+	_, err = loader.Load(ctx, "v0.2.0", false)
+	require.ErrorIs(t, err, fetcher.ErrIntegrity, "versions missing from the checksum file should not load")
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"net/url"
+	"path"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
+)
+
+// Source describes where a document is fetched from and how its integrity is verified.
+type Source struct {
+	// URL is the location of the document, in any form accepted by fetcher.NewForURL.
+	URL string
+	// SHA256 is the expected digest of the document, as bare hex or "sha256:<hex>".
+	SHA256 string
+	// ChecksumsURL points to a sha256sum-formatted file listing the digest of the document
+	// under the base name of URL.
+	ChecksumsURL string
+	// SignatureURL points to an Ed25519 signature over the checksum file.
+	SignatureURL string
+	// PublicKey verifies the checksum file signature.
+	PublicKey ed25519.PublicKey
+}
+
+// Validate reports configuration errors in the source before it is used.
+func (s Source) Validate() error {
+	if s.URL == "" {
+		return fmt.Errorf("source URL is required")
+	}
+	if s.SHA256 != "" {
+		if _, err := fetcher.NewDigestVerifier(s.SHA256); err != nil {
+			return err
+		}
+	}
+	if s.SignatureURL != "" && s.ChecksumsURL == "" {
+		return fmt.Errorf("source %s: a signature requires a checksum file", s.URL)
+	}
+	if (s.SignatureURL == "") != (s.PublicKey == nil) {
+		return fmt.Errorf("source %s: a checksum signature and public key must be configured together", s.URL)
+	}
+	return nil
+}
+
+// verifiers builds the integrity checks declared by the source.
+func (s Source) verifiers(client *fetcher.Client) ([]fetcher.Verifier, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	var verifiers []fetcher.Verifier
+	if s.SHA256 != "" {
+		v, err := fetcher.NewDigestVerifier(s.SHA256)
+		if err != nil {
+			return nil, err
+		}
+		verifiers = append(verifiers, v)
+	}
+
+	if s.ChecksumsURL != "" {
+		name, err := checksumEntryName(s.URL)
+		if err != nil {
+			return nil, err
+		}
+		checksums, err := fetcher.NewForURL(s.ChecksumsURL, client)
+		if err != nil {
+			return nil, err
+		}
+		v := fetcher.NewChecksumFileVerifier(checksums, name)
+		if s.SignatureURL != "" {
+			signature, err := fetcher.NewForURL(s.SignatureURL, client)
+			if err != nil {
+				return nil, err
+			}
+			v = v.WithSignature(signature, s.PublicKey)
+		}
+		verifiers = append(verifiers, v)
+	}
+
+	return verifiers, nil
+}
+
+// fetcher returns a fetcher for the source that checks fetched data as the source declares.
+func (s Source) fetcher(client *fetcher.Client) (fetcher.Fetcher, error) {
+	verifiers, err := s.verifiers(client)
+	if err != nil {
+		return nil, err
+	}
+	f, err := fetcher.NewForURL(s.URL, client)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.NewVerifiedFetcher(f, verifiers...), nil
+}
+
+// SourceChecks declares how the documents of a kind of source, such as schema modules or
+// artifacts, are verified. Documents pinned by SHA256 must have that digest, and when a
+// checksum file is configured every document must be listed in it.
+type SourceChecks struct {
+	// SHA256 maps the location of a document to its expected digest, as bare hex or
+	// "sha256:<hex>".
+	SHA256 map[string]string
+	// ChecksumsURL points to a sha256sum-formatted file listing the digests of the
+	// documents under their base names.
+	ChecksumsURL string
+	// SignatureURL points to an Ed25519 signature over the checksum file.
+	SignatureURL string
+	// PublicKey verifies the checksum file signature.
+	PublicKey ed25519.PublicKey
+}
+
+// Validate reports configuration errors in the checks before they are used.
+func (c SourceChecks) Validate() error {
+	for location := range c.SHA256 {
+		if err := c.Source(location).Validate(); err != nil {
+			return err
+		}
+	}
+	if c.SignatureURL != "" && c.ChecksumsURL == "" {
+		return fmt.Errorf("a signature requires a checksum file")
+	}
+	if (c.SignatureURL == "") != (c.PublicKey == nil) {
+		return fmt.Errorf("a checksum signature and public key must be configured together")
+	}
+	return nil
+}
+
+// Source returns the source of the document at location with the checks that apply to it.
+func (c SourceChecks) Source(location string) Source {
+	return Source{
+		URL:          location,
+		SHA256:       c.SHA256[location],
+		ChecksumsURL: c.ChecksumsURL,
+		SignatureURL: c.SignatureURL,
+		PublicKey:    c.PublicKey,
+	}
+}
+
+// checksumEntryName returns the file name under which a source is listed in a checksum file.
+// For OCI sources this is the selected layer when one is named.
+func checksumEntryName(sourceURL string) (string, error) {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid source URL: %w", err)
+	}
+	if u.Fragment != "" {
+		return u.Fragment, nil
+	}
+	return path.Base(u.Path), nil
+}
@@ -0,0 +1,10 @@
+// Stand-in for an early release of the Gemara schema used by tests.
+package gemara
+
+// Metadata describes the origin and context of a Gemara artifact.
+#Metadata: {
+	// Unique identifier of the artifact.
+	id: string
+	title?: string
+	description: string
+}
@@ -0,0 +1,14 @@
+package gemara
+
+// ControlCatalog is a set of controls grouped into families.
+#ControlCatalog: {
+	metadata: #Metadata
+	controls?: [...#Control]
+}
+
+// Control is a safeguard or countermeasure.
+#Control: {
+	id:        string
+	title:     string
+	objective: string
+}
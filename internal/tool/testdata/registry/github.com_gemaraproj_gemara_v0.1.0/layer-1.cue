@@ -0,0 +1,21 @@
+package gemara
+
+// GuidanceDocument captures high-level guidance such as standards, regulations
+// and best practice frameworks.
+#GuidanceDocument: {
+	title:    string
+	metadata: #Metadata
+	"document-type": "Standard" | "Regulation" | "Best Practice" | "Framework"
+	families?: [...#Family]
+	guidelines?: [...#Guideline]
+}
+
+// Guideline is a single recommendation within a guidance document.
+#Guideline: {
+	id:        string
+	title:     string
+	objective: string
+	family?:   string
+	recommendations?: [...string]
+	"guideline-mappings"?: [...#MultiMapping]
+}
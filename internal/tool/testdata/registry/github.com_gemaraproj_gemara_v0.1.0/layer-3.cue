@@ -0,0 +1,34 @@
+package gemara
+
+// Policy selects and scopes controls from catalogs for an organization.
+#Policy: {
+	title:    string
+	metadata: #Metadata
+	scope?:   #Scope
+	imports:  #Imports
+}
+
+// Scope limits a policy to the applicability categories it covers.
+#Scope: {
+	applicability?: [...string]
+}
+
+// Imports lists the catalogs and guidance a policy draws from.
+#Imports: {
+	catalogs?: [...#CatalogImport]
+	guidance?: [...#GuidanceImport]
+}
+
+// CatalogImport references a control catalog and narrows the controls taken from it.
+#CatalogImport: {
+	// Identifier of the imported catalog's metadata.
+	"reference-id": string
+	// Control IDs to exclude from the policy.
+	exclusions?: [...string]
+}
+
+// GuidanceImport references a guidance document the policy is aligned with.
+#GuidanceImport: {
+	"reference-id": string
+	exclusions?: [...string]
+}
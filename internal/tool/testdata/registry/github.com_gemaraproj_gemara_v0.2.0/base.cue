@@ -0,0 +1,70 @@
+// Stand-in for the Gemara schema used by tests. It mirrors the shape of the
+// published module closely enough to exercise schema introspection offline.
+package gemara
+
+// Metadata describes the origin and context of a Gemara artifact.
+#Metadata: {
+	// Unique identifier of the artifact.
+	id: string
+	title?: string
+	// Summary of the artifact's purpose.
+	description: string
+	version?: string
+	"last-modified"?: #Datetime
+	author: #Actor
+	"mapping-references"?: [...#MappingReference]
+	"applicability-categories"?: [...#Category]
+	// Location of the lexicon defining the terms used by the artifact.
+	lexicon?: #URL
+}
+
+// Actor is a person, organization or tool responsible for an artifact.
+#Actor: {
+	id:   string
+	name: string
+	// The kind of actor.
+	type: "Human" | "Software" | "Software Assisted" | *"Human"
+}
+
+// Category groups requirements by the contexts in which they apply.
+#Category: {
+	id:          string
+	title:       string
+	description: string
+}
+
+// MappingReference identifies an external document referenced by mappings.
+#MappingReference: {
+	id:           string
+	title:        string
+	version?:     string
+	description?: string
+	url?:         #URL
+}
+
+// MultiMapping maps to entries within a single referenced document.
+#MultiMapping: {
+	// Identifier of the referenced document.
+	"reference-id": string
+	entries: [...#MappingEntry]
+	remarks?: string
+}
+
+// MappingEntry is a single mapped item with the strength of the relationship.
+#MappingEntry: {
+	"reference-id": string
+	// How strongly the source relates to the entry, from 1 (weak) to 10 (identical).
+	strength: int & >=1 & <=10 | *1
+	remarks?: string
+}
+
+// Family groups related items within a catalog.
+#Family: {
+	id:          string
+	title:       string
+	description: string
+}
+
+#Datetime: string & =~"^\\d{4}-\\d{2}-\\d{2}(T\\d{2}:\\d{2}:\\d{2}(\\.\\d+)?(Z|[+-]\\d{2}:\\d{2})?)?$"
+
+#URL: string & =~"^https?://"
@@ -0,0 +1,2 @@
+module: "github.com/gemaraproj/gemara@v0"
+language: version: "v0.15.0"
@@ -0,0 +1,56 @@
+package gemara
+
+// ControlCatalog is a set of technology-specific controls organized into families.
+#ControlCatalog: {
+	title:    string
+	metadata: #Metadata
+	families?: [...#Family]
+	controls?: [...#Control]
+}
+
+// Control is a safeguard or countermeasure with testable assessment requirements.
+#Control: {
+	id:    string
+	title: string
+	// What the control is intended to achieve.
+	objective: string
+	// Identifier of the family the control belongs to.
+	family: string
+	"threat-mappings"?: [...#MultiMapping]
+	"guideline-mappings"?: [...#MultiMapping]
+	"assessment-requirements": [...#AssessmentRequirement]
+}
+
+// AssessmentRequirement is a tightly scoped, verifiable condition of a control.
+#AssessmentRequirement: {
+	id:   string
+	text: string
+	// Applicability categories in which the requirement must be met.
+	applicability: [...string]
+	recommendation?: string
+}
+
+// ThreatCatalog describes threats to a technology and the capabilities they exploit.
+#ThreatCatalog: {
+	title:    string
+	metadata: #Metadata
+	capabilities?: [...#Feature]
+	threats?: [...#Threat]
+}
+
+// Feature is a capability of a technology that may be exploited.
+#Feature: {
+	id:          string
+	title:       string
+	description: string
+}
+
+// Threat is a potential for harm to a technology.
+#Threat: {
+	id:          string
+	title:       string
+	description: string
+	// Features of the technology the threat exploits.
+	capabilities: [...#MultiMapping]
+	"external-mappings"?: [...#MultiMapping]
+}
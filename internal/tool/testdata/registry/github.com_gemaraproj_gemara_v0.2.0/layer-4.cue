@@ -0,0 +1,10 @@
+package gemara
+
+// EvaluationPlan schedules the assessment of controls from a policy.
+#EvaluationPlan: {
+	title:    string
+	metadata: #Metadata
+	// Identifier of the policy the plan implements.
+	"policy-id": string
+	controls?: [...string]
+}
@@ -0,0 +1,33 @@
+package gemara
+
+// EvaluationLog records the results of evaluating controls against a target.
+#EvaluationLog: {
+	metadata: #Metadata
+	// Identifier of the control catalog that was evaluated.
+	"catalog-id"?: string
+	evaluations: [...#ControlEvaluation]
+}
+
+// ControlEvaluation is the outcome of evaluating a single control.
+#ControlEvaluation: {
+	name:         string
+	"control-id": string
+	result:       #Result
+	message:      string
+	"assessment-logs": [...#AssessmentLog]
+}
+
+// AssessmentLog is the outcome of assessing a single requirement.
+#AssessmentLog: {
+	"requirement-id": string
+	applicability?: [...string]
+	description: string
+	result:      #Result
+	message:     string
+	start:       #Datetime
+	end?:        #Datetime
+	recommendation?: string
+}
+
+// Result is the outcome of an evaluation or assessment.
+#Result: "Not Run" | "Passed" | "Failed" | "Needs Review" | "Not Applicable" | "Unknown" | "Skipped"
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MetadataAnalyzeThreatCoverage describes the AnalyzeThreatCoverage tool.
+var MetadataAnalyzeThreatCoverage = &mcp.Tool{
+	Name:         "analyze_threat_coverage",
+	Description:  "Find the threats of the threat catalogs in the client's workspace that no control mitigates, and the controls whose threat mappings reference threats absent from every loaded threat catalog, as JSON or a markdown report.",
+	InputSchema:  withEnum(schemaFor[InputAnalyzeThreatCoverage](), "format", formatJSON, formatMarkdown),
+	OutputSchema: schemaFor[OutputAnalyzeThreatCoverage](),
+	Annotations:  readOnlyAnnotations(false),
+}
+
+// InputAnalyzeThreatCoverage is the input for the AnalyzeThreatCoverage tool.
+type InputAnalyzeThreatCoverage struct {
+	ThreatCatalog string `json:"threat_catalog,omitempty" jsonschema:"Metadata ID of a threat catalog to report on (default: all threat catalogs of the workspace)"`
+	Format        string `json:"format,omitempty" jsonschema:"Output format (default: 'json')"`
+}
+
+// OutputAnalyzeThreatCoverage is the output of the AnalyzeThreatCoverage tool. It relates the
+// threats of threat catalogs to the controls that mitigate them.
+type OutputAnalyzeThreatCoverage struct {
+	ThreatCatalogs  []string `json:"threat_catalogs"`
+	ControlCatalogs []string `json:"control_catalogs"`
+	// Threats is the number of threats reported on, Mitigated those with a mitigating control.
+	Threats   int `json:"threats"`
+	Mitigated int `json:"mitigated"`
+	// Percent is the share of threats with a mitigating control.
+	Percent     float64          `json:"percent"`
+	Unmitigated []ThreatCoverage `json:"unmitigated"`
+	// Covered lists the mitigated threats with their controls in the JSON format.
+	Covered []ThreatCoverage `json:"covered,omitempty"`
+	// Dangling are threat mappings of controls to threats no loaded threat catalog defines.
+	Dangling []DanglingThreatMapping `json:"dangling"`
+	// Document holds the markdown report in that format.
+	Document string `json:"document,omitempty"`
+}
+
+// ThreatCoverage is a threat with the controls that mitigate it.
+type ThreatCoverage struct {
+	Catalog string `json:"catalog"`
+	ID      string `json:"id"`
+	Title   string `json:"title,omitempty"`
+	// Controls are the mitigating controls as 'catalog:control'.
+	Controls []string `json:"controls,omitempty"`
+}
+
+// DanglingThreatMapping is a threat mapping of a control that no loaded threat catalog resolves.
+type DanglingThreatMapping struct {
+	// Control is the mapping control as 'catalog:control'.
+	Control string `json:"control"`
+	Threat  string `json:"threat"`
+	// CatalogLoaded reports whether a threat catalog with the referenced ID is loaded, in
+	// which case it lacks the threat.
+	CatalogLoaded bool `json:"catalog_loaded"`
+}
+
+// AnalyzeThreatCoverage reports the threat coverage of the artifacts of the workspace index.
+func AnalyzeThreatCoverage(_ context.Context, _ *mcp.CallToolRequest, input InputAnalyzeThreatCoverage, index *WorkspaceIndex) (*mcp.CallToolResult, OutputAnalyzeThreatCoverage, error) {
+	format := input.Format
+	if format == "" {
+		format = formatJSON
+	}
+	if format != formatJSON && format != formatMarkdown {
+		return nil, OutputAnalyzeThreatCoverage{}, fmt.Errorf("unsupported format %q: use %q or %q", format, formatJSON, formatMarkdown)
+	}
+
+	report, err := ThreatCoverageOf(index.Artifacts(), input.ThreatCatalog)
+	if err != nil {
+		return nil, OutputAnalyzeThreatCoverage{}, err
+	}
+	if format == formatMarkdown {
+		report.Document = RenderThreatCoverage(report)
+		report.Covered = nil
+	}
+	return nil, report, nil
+}
+
+// ThreatCoverageOf relates the threats of the threat catalogs among artifacts to the
+// controls of the control catalogs, optionally for a single threat catalog.
+func ThreatCoverageOf(artifacts []IndexedArtifact, threatCatalog string) (OutputAnalyzeThreatCoverage, error) {
+	report := OutputAnalyzeThreatCoverage{
+		ThreatCatalogs:  []string{},
+		ControlCatalogs: []string{},
+		Unmitigated:     []ThreatCoverage{},
+		Dangling:        []DanglingThreatMapping{},
+	}
+	for _, artifact := range artifacts {
+		switch artifact.Definition {
+		case "#ThreatCatalog":
+			report.ThreatCatalogs = append(report.ThreatCatalogs, artifact.ID)
+		case "#ControlCatalog":
+			report.ControlCatalogs = append(report.ControlCatalogs, artifact.ID)
+		}
+	}
+	if len(report.ThreatCatalogs) == 0 {
+		return OutputAnalyzeThreatCoverage{}, fmt.Errorf("no threat catalogs are indexed: the workspace must contain at least one")
+	}
+	if threatCatalog != "" {
+		if !slices.Contains(report.ThreatCatalogs, threatCatalog) {
+			return OutputAnalyzeThreatCoverage{}, fmt.Errorf("threat catalog %s is not in the workspace index: found %s", threatCatalog, strings.Join(report.ThreatCatalogs, ", "))
+		}
+		report.ThreatCatalogs = []string{threatCatalog}
+	}
+
+	graph := BuildMappingGraph(artifacts)
+	nodes := make([]*MappingNode, 0, len(graph.nodes))
+	for _, node := range graph.nodes {
+		if node.Kind == nodeThreat {
+			nodes = append(nodes, node)
+		}
+	}
+	slices.SortFunc(nodes, func(a, b *MappingNode) int {
+		return cmp.Or(cmp.Compare(a.Document, b.Document), cmp.Compare(a.ID, b.ID))
+	})
+
+	for _, node := range nodes {
+		var controls []string
+		for _, edge := range graph.edges[node.Ref] {
+			if edge.Relation == relationMitigates && edge.To == node.Ref {
+				controls = append(controls, edge.From)
+			}
+		}
+
+		if !node.Loaded {
+			if threatCatalog != "" && node.Document != threatCatalog {
+				continue
+			}
+			for _, control := range controls {
+				report.Dangling = append(report.Dangling, DanglingThreatMapping{
+					Control:       control,
+					Threat:        node.Ref,
+					CatalogLoaded: slices.Contains(report.ThreatCatalogs, node.Document),
+				})
+			}
+			continue
+		}
+		if !slices.Contains(report.ThreatCatalogs, node.Document) {
+			continue
+		}
+
+		threat := ThreatCoverage{Catalog: node.Document, ID: node.ID, Title: node.Title, Controls: controls}
+		report.Threats++
+		if len(controls) == 0 {
+			report.Unmitigated = append(report.Unmitigated, threat)
+			continue
+		}
+		report.Mitigated++
+		report.Covered = append(report.Covered, threat)
+	}
+	if report.Threats > 0 {
+		report.Percent = math.Round(float64(report.Mitigated)/float64(report.Threats)*1000) / 10
+	}
+	return report, nil
+}
+
+// RenderThreatCoverage renders a threat coverage report as markdown.
+func RenderThreatCoverage(report OutputAnalyzeThreatCoverage) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Threat coverage of %s\n\n", strings.Join(report.ThreatCatalogs, ", "))
+	controlCatalogs := "no control catalogs"
+	if len(report.ControlCatalogs) > 0 {
+		controlCatalogs = strings.Join(report.ControlCatalogs, ", ")
+	}
+	fmt.Fprintf(&b, "%d of %d threats mitigated (%.1f%%) by the controls of %s.\n",
+		report.Mitigated, report.Threats, report.Percent, controlCatalogs)
+
+	b.WriteString("\n## Unmitigated threats\n\n")
+	if len(report.Unmitigated) == 0 {
+		b.WriteString("Every threat is mitigated by at least one control.\n")
+	}
+	for _, threat := range report.Unmitigated {
+		fmt.Fprintf(&b, "- **%s:%s**", threat.Catalog, threat.ID)
+		if threat.Title != "" {
+			b.WriteString(" " + threat.Title)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n## Mappings to unknown threats\n\n")
+	if len(report.Dangling) == 0 {
+		b.WriteString("Every threat mapping resolves to a loaded threat catalog.\n")
+	}
+	for _, mapping := range report.Dangling {
+		reason := "threat catalog not loaded"
+		if mapping.CatalogLoaded {
+			reason = "not in the threat catalog"
+		}
+		fmt.Fprintf(&b, "- **%s** maps to %s (%s)\n", mapping.Control, mapping.Threat, reason)
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeThreatCoverage(t *testing.T) {
+	index := newTestMappingIndex(t)
+
+	tests := []struct {
+		name           string
+		input          InputAnalyzeThreatCoverage
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputAnalyzeThreatCoverage)
+	}{
+		{
+			name:  "all threat catalogs",
+			input: InputAnalyzeThreatCoverage{},
+			validateOutput: func(t *testing.T, output OutputAnalyzeThreatCoverage) {
+				assert.Equal(t, []string{"CCC"}, output.ThreatCatalogs)
+				assert.Equal(t, []string{"FINOS-CCC"}, output.ControlCatalogs)
+				assert.Equal(t, 6, output.Threats)
+				assert.Equal(t, 5, output.Mitigated)
+				assert.Equal(t, 83.3, output.Percent)
+				assert.Equal(t, []ThreatCoverage{{Catalog: "CCC", ID: "CCC.TH05", Title: "Data is Exposed via a Public Endpoint"}}, output.Unmitigated)
+				require.Len(t, output.Covered, 5)
+				assert.Equal(t, ThreatCoverage{
+					Catalog:  "CCC",
+					ID:       "CCC.TH04",
+					Title:    "Data is Replicated to Untrusted or External Locations",
+					Controls: []string{"FINOS-CCC:CCC.C09", "FINOS-CCC:CCC.C10"},
+				}, output.Covered[2])
+				assert.Equal(t, []DanglingThreatMapping{{Control: "FINOS-CCC:CCC.C09", Threat: "CCC:CCC.TH09", CatalogLoaded: true}}, output.Dangling)
+				assert.Empty(t, output.Document)
+			},
+		},
+		{
+			name:  "markdown report",
+			input: InputAnalyzeThreatCoverage{ThreatCatalog: "CCC", Format: formatMarkdown},
+			validateOutput: func(t *testing.T, output OutputAnalyzeThreatCoverage) {
+				assert.Nil(t, output.Covered)
+				assert.True(t, strings.HasPrefix(output.Document, "# Threat coverage of CCC\n\n5 of 6 threats mitigated (83.3%) by the controls of FINOS-CCC.\n"), output.Document)
+				assert.Contains(t, output.Document, "## Unmitigated threats\n\n- **CCC:CCC.TH05** Data is Exposed via a Public Endpoint\n")
+				assert.Contains(t, output.Document, "## Mappings to unknown threats\n\n- **FINOS-CCC:CCC.C09** maps to CCC:CCC.TH09 (not in the threat catalog)\n")
+			},
+		},
+		{
+			name:        "unknown threat catalog",
+			input:       InputAnalyzeThreatCoverage{ThreatCatalog: "OS-THREATS"},
+			wantErr:     true,
+			errContains: "threat catalog OS-THREATS is not in the workspace index: found CCC",
+		},
+		{
+			name:        "unsupported format",
+			input:       InputAnalyzeThreatCoverage{Format: "csv"},
+			wantErr:     true,
+			errContains: `unsupported format "csv"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := AnalyzeThreatCoverage(context.Background(), nil, tt.input, index)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			tt.validateOutput(t, output)
+		})
+	}
+}
+
+func TestThreatCoverageOf(t *testing.T) {
+	artifacts := newTestMappingIndex(t).Artifacts()
+
+	var withoutThreats []IndexedArtifact
+	for _, artifact := range artifacts {
+		if artifact.Definition != "#ThreatCatalog" {
+			withoutThreats = append(withoutThreats, artifact)
+		}
+	}
+	_, err := ThreatCoverageOf(withoutThreats, "")
+	assert.ErrorContains(t, err, "no threat catalogs are indexed")
+
+	var withoutControls []IndexedArtifact
+	for _, artifact := range artifacts {
+		if artifact.Definition != "#ControlCatalog" {
+			withoutControls = append(withoutControls, artifact)
+		}
+	}
+	report, err := ThreatCoverageOf(withoutControls, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Mitigated)
+	assert.Len(t, report.Unmitigated, 6)
+	assert.Empty(t, report.Dangling)
+	assert.Contains(t, RenderThreatCoverage(report), "0 of 6 threats mitigated (0.0%) by the controls of no control catalogs.\n")
+}
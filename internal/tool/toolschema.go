@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// schemaFor derives the JSON Schema of a tool's input or output from its Go type.
+// Property descriptions come from jsonschema struct tags, and fields without
+// omitempty are required.
+func schemaFor[T any]() *jsonschema.Schema {
+	schema, err := jsonschema.For[T](nil)
+	if err != nil {
+		panic(fmt.Sprintf("failed to derive JSON Schema for %T: %v", *new(T), err))
+	}
+	return schema
+}
+
+// withEnum restricts a property of an object schema to the given values.
+func withEnum(schema *jsonschema.Schema, property string, values ...string) *jsonschema.Schema {
+	prop, ok := schema.Properties[property]
+	if !ok {
+		panic(fmt.Sprintf("schema has no property %q", property))
+	}
+	for _, v := range values {
+		prop.Enum = append(prop.Enum, v)
+	}
+	return schema
+}
+
+// readOnlyAnnotations describes a tool that never modifies its environment. Tools that
+// load artifacts from arbitrary locations are open world; tools limited to the Gemara
+// module and lexicon are not.
+func readOnlyAnnotations(openWorld bool) *mcp.ToolAnnotations {
+	return &mcp.ToolAnnotations{
+		ReadOnlyHint:   true,
+		IdempotentHint: true,
+		OpenWorldHint:  &openWorld,
+	}
+}
@@ -3,46 +3,31 @@
 package tool
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"strings"
 
 	"cuelang.org/go/cue"
-	"cuelang.org/go/cue/cuecontext"
-	"cuelang.org/go/cue/load"
 	"cuelang.org/go/encoding/yaml"
-	"cuelang.org/go/mod/modconfig"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-const (
-	gemaraModulePath = "github.com/gemaraproj/gemara@latest"
-)
-
 // MetadataValidateGemaraArtifact describes the ValidateGemaraArtifact tool.
 var MetadataValidateGemaraArtifact = &mcp.Tool{
-	Name:        "validate_gemara_artifact",
-	Description: "Validate a Gemara artifact YAML content against the Gemara CUE schema using the CUE registry module.",
-	InputSchema: map[string]interface{}{
-		"type":     "object",
-		"required": []string{"artifact_content", "definition"},
-		"properties": map[string]interface{}{
-			"artifact_content": map[string]interface{}{
-				"type":        "string",
-				"description": "YAML content of the Gemara artifact to validate",
-			},
-			"definition": map[string]interface{}{
-				"type":        "string",
-				"description": "CUE definition name to validate against (e.g., '#ControlCatalog', '#GuidanceDocument', '#Policy', '#EvaluationLog')",
-			},
-		},
-	},
+	Name:         "validate_gemara_artifact",
+	Description:  "Validate a Gemara artifact YAML content against the Gemara CUE schema using the CUE registry module.",
+	InputSchema:  schemaFor[InputValidateGemaraArtifact](),
+	OutputSchema: schemaFor[OutputValidateGemaraArtifact](),
+	Annotations:  readOnlyAnnotations(true),
 }
 
 // InputValidateGemaraArtifact is the input for the ValidateGemaraArtifact tool.
 type InputValidateGemaraArtifact struct {
-	ArtifactContent string `json:"artifact_content"`
-	Definition      string `json:"definition"`
+	ArtifactContent string `json:"artifact_content,omitempty" jsonschema:"YAML content of the Gemara artifact to validate"`
+	ArtifactSource  string `json:"artifact_source,omitempty" jsonschema:"Location to load the artifact from instead of artifact_content (e.g., 'oci://ghcr.io/org/catalogs:v1#ccc.yaml', 'oci-layout://./layout:v1', 'https://...')"`
+	Definition      string `json:"definition" jsonschema:"CUE definition name to validate against (e.g., '#ControlCatalog', '#GuidanceDocument', '#Policy', '#EvaluationLog'); use list_definitions for all available definitions"`
+	Version         string `json:"version,omitempty" jsonschema:"Version of the Gemara module to validate against (default: 'latest')"`
 }
 
 // OutputValidateGemaraArtifact is the output for the ValidateGemaraArtifact tool.
@@ -50,10 +35,14 @@ type OutputValidateGemaraArtifact struct {
 	Valid   bool     `json:"valid"`
 	Errors  []string `json:"errors,omitempty"`
 	Message string   `json:"message"`
+	Source  string   `json:"source,omitempty"`
+	// Version is the schema version the artifact was validated against.
+	Version string `json:"version"`
 }
 
-// ValidateGemaraArtifact validates a Gemara artifact using the CUE Go SDK with the registry module.
-func ValidateGemaraArtifact(ctx context.Context, _ *mcp.CallToolRequest, input InputValidateGemaraArtifact) (*mcp.CallToolResult, OutputValidateGemaraArtifact, error) {
+// ValidateGemaraArtifact validates a Gemara artifact using the CUE Go SDK with the registry module
+// loaded by the specified schema loader.
+func ValidateGemaraArtifact(ctx context.Context, _ *mcp.CallToolRequest, input InputValidateGemaraArtifact, loader *SchemaLoader) (*mcp.CallToolResult, OutputValidateGemaraArtifact, error) {
 	// Validate inputs
 	if input.ArtifactContent == "" {
 		return nil, OutputValidateGemaraArtifact{}, fmt.Errorf("artifact_content is required")
@@ -68,45 +57,152 @@ func ValidateGemaraArtifact(ctx context.Context, _ *mcp.CallToolRequest, input I
 		definition = "#" + definition
 	}
 
-	reg, err := modconfig.NewRegistry(nil)
+	schema, err := loader.Load(ctx, input.Version, false)
 	if err != nil {
-		return nil, OutputValidateGemaraArtifact{}, fmt.Errorf("failed to create CUE registry: %w", err)
+		return nil, OutputValidateGemaraArtifact{}, err
 	}
 
-	// Pass the module path as an argument to load it from the registry
-	buildInstances := load.Instances([]string{gemaraModulePath}, &load.Config{
-		Registry: reg,
-	})
+	output, err := validateArtifact(ctx, schema, definition, "artifact", input.ArtifactContent)
+	if err != nil {
+		return nil, OutputValidateGemaraArtifact{}, err
+	}
+	return nil, output, nil
+}
+
+// MetadataValidateGemaraArtifacts describes the ValidateGemaraArtifacts tool.
+var MetadataValidateGemaraArtifacts = &mcp.Tool{
+	Name:         "validate_gemara_artifacts",
+	Description:  "Validate several Gemara artifacts against the Gemara CUE schema in one call, loading the schema once and reporting progress per artifact.",
+	InputSchema:  schemaFor[InputValidateGemaraArtifacts](),
+	OutputSchema: schemaFor[OutputValidateGemaraArtifacts](),
+	Annotations:  readOnlyAnnotations(true),
+}
 
-	if len(buildInstances) == 0 {
-		return nil, OutputValidateGemaraArtifact{}, fmt.Errorf("failed to load module: no instances returned")
+// InputValidateGemaraArtifacts is the input for the ValidateGemaraArtifacts tool.
+type InputValidateGemaraArtifacts struct {
+	Artifacts  []ArtifactToValidate `json:"artifacts" jsonschema:"Artifacts to validate"`
+	Definition string               `json:"definition,omitempty" jsonschema:"CUE definition name to validate artifacts against that do not name their own (e.g., '#ControlCatalog')"`
+	Version    string               `json:"version,omitempty" jsonschema:"Version of the Gemara module to validate against (default: 'latest')"`
+}
+
+// ArtifactToValidate is an artifact in a batch validation.
+type ArtifactToValidate struct {
+	Name       string `json:"name,omitempty" jsonschema:"Name of the artifact in results, progress and log messages (default: its source or position)"`
+	Content    string `json:"content,omitempty" jsonschema:"YAML content of the artifact"`
+	Source     string `json:"source,omitempty" jsonschema:"Location to load the artifact from instead of content, as for validate_gemara_artifact"`
+	Definition string `json:"definition,omitempty" jsonschema:"CUE definition name to validate the artifact against (default: the batch definition)"`
+}
+
+// OutputValidateGemaraArtifacts is the output for the ValidateGemaraArtifacts tool.
+type OutputValidateGemaraArtifacts struct {
+	// Valid reports whether every artifact is valid.
+	Valid   bool                 `json:"valid"`
+	Results []ArtifactValidation `json:"results"`
+	Message string               `json:"message"`
+	// Version is the schema version the artifacts were validated against.
+	Version string `json:"version"`
+}
+
+// ArtifactValidation is the result of validating one artifact of a batch.
+type ArtifactValidation struct {
+	Name       string   `json:"name"`
+	Definition string   `json:"definition"`
+	Valid      bool     `json:"valid"`
+	Errors     []string `json:"errors,omitempty"`
+	Message    string   `json:"message"`
+	Source     string   `json:"source,omitempty"`
+}
+
+// ValidateGemaraArtifacts validates several Gemara artifacts against one version of the schema,
+// reporting a progress step per artifact. The content of artifacts given by source must be
+// loaded by the caller; Source is only reported.
+func ValidateGemaraArtifacts(ctx context.Context, _ *mcp.CallToolRequest, input InputValidateGemaraArtifacts, loader *SchemaLoader) (*mcp.CallToolResult, OutputValidateGemaraArtifacts, error) {
+	if len(input.Artifacts) == 0 {
+		return nil, OutputValidateGemaraArtifacts{}, fmt.Errorf("artifacts is required")
+	}
+	definitions := make([]string, len(input.Artifacts))
+	for i, artifact := range input.Artifacts {
+		if artifact.Content == "" {
+			return nil, OutputValidateGemaraArtifacts{}, fmt.Errorf("artifacts[%d]: content is required", i)
+		}
+		definition := cmp.Or(artifact.Definition, input.Definition)
+		if definition == "" {
+			return nil, OutputValidateGemaraArtifacts{}, fmt.Errorf("artifacts[%d]: definition is required", i)
+		}
+		if !strings.HasPrefix(definition, "#") {
+			definition = "#" + definition
+		}
+		definitions[i] = definition
+	}
+
+	schema, err := loader.Load(ctx, input.Version, false)
+	if err != nil {
+		return nil, OutputValidateGemaraArtifacts{}, err
 	}
 
-	if err := buildInstances[0].Err; err != nil {
-		return nil, OutputValidateGemaraArtifact{}, fmt.Errorf("failed to load module: %w", err)
+	reporterFrom(ctx).expect(len(input.Artifacts))
+	output := OutputValidateGemaraArtifacts{
+		Valid:   true,
+		Version: schema.Version,
+		Results: make([]ArtifactValidation, 0, len(input.Artifacts)),
+	}
+	invalid := 0
+	for i, artifact := range input.Artifacts {
+		name := cmp.Or(artifact.Name, artifact.Source, fmt.Sprintf("artifact %d", i+1))
+		result, err := validateArtifact(ctx, schema, definitions[i], name, artifact.Content)
+		if err != nil {
+			return nil, OutputValidateGemaraArtifacts{}, fmt.Errorf("%s: %w", name, err)
+		}
+		if !result.Valid {
+			output.Valid = false
+			invalid++
+		}
+		output.Results = append(output.Results, ArtifactValidation{
+			Name:       name,
+			Definition: definitions[i],
+			Valid:      result.Valid,
+			Errors:     result.Errors,
+			Message:    result.Message,
+			Source:     artifact.Source,
+		})
 	}
 
-	cueCtx := cuecontext.New()
-	schema := cueCtx.BuildInstance(buildInstances[0])
-	if err := schema.Err(); err != nil {
-		return nil, OutputValidateGemaraArtifact{}, fmt.Errorf("failed to build schema: %w", err)
+	if output.Valid {
+		output.Message = fmt.Sprintf("All %d artifacts are valid", len(input.Artifacts))
+	} else {
+		output.Message = fmt.Sprintf("%d of %d artifacts are invalid", invalid, len(input.Artifacts))
 	}
+	return nil, output, nil
+}
+
+// validateArtifact validates artifact content against a definition of the schema, naming
+// the artifact in progress and log messages. Artifacts that do not conform are reported in
+// the output; errors are reserved for unknown definitions and cancellation.
+func validateArtifact(ctx context.Context, schema *Schema, definition, name, content string) (OutputValidateGemaraArtifact, error) {
+	cueCtx := schema.Value.Context()
 
 	entrypointPath := cue.ParsePath(definition)
-	entrypoint := schema.LookupPath(entrypointPath)
+	entrypoint := schema.Value.LookupPath(entrypointPath)
 	if !entrypoint.Exists() {
-		return nil, OutputValidateGemaraArtifact{}, fmt.Errorf("definition %s not found in schema", definition)
+		return OutputValidateGemaraArtifact{}, fmt.Errorf("definition %s not found in schema", definition)
+	}
+
+	r := reporterFrom(ctx)
+	if err := ctx.Err(); err != nil {
+		return OutputValidateGemaraArtifact{}, err
 	}
+	r.step(ctx, "validating %s against %s of %s@%s", name, definition, gemaraModulePath, schema.Version)
 
-	yamlFile, err := yaml.Extract("artifact.yaml", input.ArtifactContent)
+	yamlFile, err := yaml.Extract("artifact.yaml", content)
 	if err != nil {
 		// Invalid YAML should result in validation failure, not a function error
 		output := OutputValidateGemaraArtifact{
 			Valid:   false,
+			Version: schema.Version,
 			Errors:  []string{fmt.Sprintf("Failed to parse YAML: %v", err)},
 			Message: fmt.Sprintf("Validation failed: invalid YAML: %v", err),
 		}
-		return nil, output, nil
+		return output, nil
 	}
 
 	data := cueCtx.BuildFile(yamlFile)
@@ -114,10 +210,11 @@ func ValidateGemaraArtifact(ctx context.Context, _ *mcp.CallToolRequest, input I
 		// Data build errors should result in validation failure
 		output := OutputValidateGemaraArtifact{
 			Valid:   false,
+			Version: schema.Version,
 			Errors:  []string{fmt.Sprintf("Failed to build data instance: %v", err)},
 			Message: fmt.Sprintf("Validation failed: %v", err),
 		}
-		return nil, output, nil
+		return output, nil
 	}
 
 	unified := entrypoint.Unify(data)
@@ -136,17 +233,21 @@ func ValidateGemaraArtifact(ctx context.Context, _ *mcp.CallToolRequest, input I
 
 		output := OutputValidateGemaraArtifact{
 			Valid:   false,
+			Version: schema.Version,
 			Errors:  errors,
 			Message: fmt.Sprintf("Validation failed: %v", err),
 		}
-		return nil, output, nil
+		r.log(ctx, logWarning, "%s is not a valid %s: %d errors", name, definition, len(errors))
+		return output, nil
 	}
 
+	r.log(ctx, logInfo, "%s is a valid %s", name, definition)
 	output := OutputValidateGemaraArtifact{
 		Valid:   true,
+		Version: schema.Version,
 		Errors:  []string{},
 		Message: "Artifact is valid",
 	}
 
-	return nil, output, nil
+	return output, nil
 }
@@ -5,13 +5,18 @@ package tool
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/gemaraproj/gemara-mcp/internal/tool/fetcher"
 )
 
 func TestValidateGemaraArtifact(t *testing.T) {
@@ -141,7 +146,7 @@ metadata:
 				},
 			}
 
-			_, output, err := ValidateGemaraArtifact(ctx, req, tt.input)
+			_, output, err := ValidateGemaraArtifact(ctx, req, tt.input, NewSchemaLoader(nil))
 
 			if tt.wantErr {
 				require.Error(t, err, "should return error")
@@ -166,3 +171,182 @@ metadata:
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestValidateGemaraArtifactFromSource(t *testing.T) {
+	client, err := fetcher.NewClient(fetcher.ClientOptions{Timeout: time.Second})
+	require.NoError(t, err)
+	advisory := NewAdvisoryMode(fetcher.NewCache(time.Hour), client)
+
+	tests := []struct {
+		name        string
+		input       InputValidateGemaraArtifact
+		errContains string
+	}{
+		{
+			name: "content and source are mutually exclusive",
+			input: InputValidateGemaraArtifact{
+				ArtifactContent: "title: test",
+				ArtifactSource:  "oci-layout://./layout:v1",
+				Definition:      "#ControlCatalog",
+			},
+			errContains: "mutually exclusive",
+		},
+		{
+			name: "unsupported source",
+			input: InputValidateGemaraArtifact{
+				ArtifactSource: "ftp://example.com/ccc.yaml",
+				Definition:     "#ControlCatalog",
+			},
+			errContains: "unsupported source scheme",
+		},
+		{
+			name: "unreadable source",
+			input: InputValidateGemaraArtifact{
+				ArtifactSource: "oci-layout://" + filepath.Join(t.TempDir(), "missing") + ":v1",
+				Definition:     "#ControlCatalog",
+			},
+			errContains: "failed to load artifact",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := advisory.validateGemaraArtifact(context.Background(), nil, tt.input)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestValidateGemaraArtifactVerifiedSource(t *testing.T) {
+	catalog := readTestCatalog(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(catalog))
+	}))
+	t.Cleanup(server.Close)
+	source := server.URL + "/ccc.yaml"
+	input := InputValidateGemaraArtifact{ArtifactSource: source, Definition: "#ControlCatalog"}
+
+	mode := newTestAdvisoryMode(t, WithArtifactChecks(SourceChecks{SHA256: map[string]string{source: fetcher.Digest([]byte(catalog))}}))
+	_, output, err := mode.validateGemaraArtifact(context.Background(), nil, input)
+	require.NoError(t, err)
+	assert.Equal(t, source, output.Source)
+
+	mode = newTestAdvisoryMode(t, WithArtifactChecks(SourceChecks{SHA256: map[string]string{source: fetcher.Digest([]byte("other"))}}))
+	_, _, err = mode.validateGemaraArtifact(context.Background(), nil, input)
+	require.ErrorIs(t, err, fetcher.ErrIntegrity, "artifacts that do not match their pinned digest should be refused")
+}
+
+func TestValidateGemaraArtifacts(t *testing.T) {
+	catalog := readTestCatalog(t)
+	loader := newTestSchemaLoader(t)
+
+	tests := []struct {
+		name           string
+		input          InputValidateGemaraArtifacts
+		wantErr        bool
+		errContains    string
+		validateOutput func(t *testing.T, output OutputValidateGemaraArtifacts)
+	}{
+		{
+			name:        "missing artifacts",
+			input:       InputValidateGemaraArtifacts{Definition: "#ControlCatalog"},
+			wantErr:     true,
+			errContains: "artifacts is required",
+		},
+		{
+			name: "missing content",
+			input: InputValidateGemaraArtifacts{
+				Artifacts:  []ArtifactToValidate{{Content: catalog}, {Name: "empty.yaml"}},
+				Definition: "#ControlCatalog",
+			},
+			wantErr:     true,
+			errContains: "artifacts[1]: content is required",
+		},
+		{
+			name: "missing definition",
+			input: InputValidateGemaraArtifacts{
+				Artifacts: []ArtifactToValidate{{Content: catalog}},
+			},
+			wantErr:     true,
+			errContains: "artifacts[0]: definition is required",
+		},
+		{
+			name: "unknown definition",
+			input: InputValidateGemaraArtifacts{
+				Artifacts: []ArtifactToValidate{{Name: "ccc.yaml", Content: catalog, Definition: "NoSuchDefinition"}},
+			},
+			wantErr:     true,
+			errContains: "ccc.yaml: definition #NoSuchDefinition not found",
+		},
+		{
+			name: "valid and invalid artifacts",
+			input: InputValidateGemaraArtifacts{
+				Artifacts: []ArtifactToValidate{
+					{Name: "ccc.yaml", Content: catalog},
+					{Content: "title: Broken"},
+					{Content: threatCatalogV010, Definition: "ThreatCatalog"},
+				},
+				Definition: "#ControlCatalog",
+				Version:    "v0.1.0",
+			},
+			validateOutput: func(t *testing.T, output OutputValidateGemaraArtifacts) {
+				assert.False(t, output.Valid, "a batch with an invalid artifact should be invalid")
+				assert.Equal(t, "v0.1.0", output.Version)
+				assert.Equal(t, "1 of 3 artifacts are invalid", output.Message)
+				require.Len(t, output.Results, 3)
+				assert.Equal(t, "ccc.yaml", output.Results[0].Name)
+				assert.True(t, output.Results[0].Valid)
+				assert.Equal(t, "artifact 2", output.Results[1].Name)
+				assert.False(t, output.Results[1].Valid)
+				assert.NotEmpty(t, output.Results[1].Errors)
+				assert.Equal(t, "#ThreatCatalog", output.Results[2].Definition)
+				assert.True(t, output.Results[2].Valid)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := ValidateGemaraArtifacts(context.Background(), nil, tt.input, loader)
+			if tt.wantErr {
+				require.Error(t, err, "should return error")
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err, "should not return error")
+			if tt.validateOutput != nil {
+				tt.validateOutput(t, output)
+			}
+		})
+	}
+}
+
+func TestValidateGemaraArtifactsFromSource(t *testing.T) {
+	catalog := readTestCatalog(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(catalog))
+	}))
+	t.Cleanup(server.Close)
+	source := server.URL + "/ccc.yaml"
+	mode := newTestAdvisoryMode(t)
+
+	_, output, err := mode.validateGemaraArtifacts(context.Background(), nil, InputValidateGemaraArtifacts{
+		Artifacts:  []ArtifactToValidate{{Source: source}, {Name: "inline.yaml", Content: catalog}},
+		Definition: "#ControlCatalog",
+	})
+	require.NoError(t, err)
+	assert.True(t, output.Valid)
+	require.Len(t, output.Results, 2)
+	assert.Equal(t, source, output.Results[0].Name)
+	assert.Equal(t, source, output.Results[0].Source)
+	assert.Empty(t, output.Results[1].Source)
+
+	_, _, err = mode.validateGemaraArtifacts(context.Background(), nil, InputValidateGemaraArtifacts{
+		Artifacts:  []ArtifactToValidate{{Source: source, Content: catalog}},
+		Definition: "#ControlCatalog",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "artifacts[0]: content and source are mutually exclusive")
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MetadataListSchemaVersions describes the ListSchemaVersions tool.
+var MetadataListSchemaVersions = &mcp.Tool{
+	Name:         "list_schema_versions",
+	Description:  "List the published versions of the Gemara CUE module available from the CUE registry.",
+	InputSchema:  schemaFor[InputListSchemaVersions](),
+	OutputSchema: schemaFor[OutputListSchemaVersions](),
+	Annotations:  readOnlyAnnotations(false),
+}
+
+// InputListSchemaVersions is the input for the ListSchemaVersions tool.
+type InputListSchemaVersions struct{}
+
+// OutputListSchemaVersions is the output for the ListSchemaVersions tool.
+type OutputListSchemaVersions struct {
+	Module string `json:"module"`
+	Latest string `json:"latest"`
+	// Versions are ordered from newest to oldest.
+	Versions []string `json:"versions"`
+}
+
+// ListSchemaVersions lists the versions of the Gemara module published to the registry
+// of the specified schema loader.
+func ListSchemaVersions(ctx context.Context, _ *mcp.CallToolRequest, _ InputListSchemaVersions, loader *SchemaLoader) (*mcp.CallToolResult, OutputListSchemaVersions, error) {
+	versions, err := loader.Versions(ctx)
+	if err != nil {
+		return nil, OutputListSchemaVersions{}, err
+	}
+	slices.Reverse(versions)
+
+	return nil, OutputListSchemaVersions{
+		Module:   gemaraModulePath,
+		Latest:   versions[0],
+		Versions: versions,
+	}, nil
+}
@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"cuelang.org/go/mod/modconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSchemaVersions(t *testing.T) {
+	_, output, err := ListSchemaVersions(context.Background(), nil, InputListSchemaVersions{}, newTestSchemaLoader(t))
+	require.NoError(t, err, "should not return error")
+
+	assert.Equal(t, "github.com/gemaraproj/gemara", output.Module)
+	assert.Equal(t, "v0.2.0", output.Latest)
+	assert.Equal(t, []string{"v0.2.0", "v0.1.0", "v0.0.1"}, output.Versions, "versions should be ordered newest first")
+}
+
+func TestListSchemaVersionsUnreachableRegistry(t *testing.T) {
+	loader := NewSchemaLoader(&modconfig.Config{
+		Env: []string{
+			"CUE_REGISTRY=127.0.0.1:1+insecure",
+			"CUE_CACHE_DIR=" + t.TempDir(),
+		},
+	})
+
+	_, _, err := ListSchemaVersions(context.Background(), nil, InputListSchemaVersions{}, loader)
+	require.Error(t, err, "should return error")
+	assert.Contains(t, err.Error(), "failed to list versions of github.com/gemaraproj/gemara")
+}
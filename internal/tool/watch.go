@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultWorkspacePollInterval is how often the files of indexed workspaces are checked for changes.
+const defaultWorkspacePollInterval = 2 * time.Second
+
+// workspaceWatcher publishes the artifacts of a workspace index as resources of a server.
+// While a client is connected, it indexes the client's roots alongside those of the other
+// clients and rescans them when the client reports changed roots. While any client is
+// connected, a single poller checks the files of all roots, adding, replacing and removing
+// resources and notifying subscribers as artifacts change on disk. When a client
+// disconnects, the artifacts only its roots contained are removed.
+type workspaceWatcher struct {
+	index    *WorkspaceIndex
+	interval time.Duration
+
+	mu     sync.Mutex
+	server *mcp.Server
+	// rootsChanged signals the watch loop of each session that its roots changed.
+	rootsChanged map[*mcp.ServerSession]chan struct{}
+	// stopPolling stops the poller, which runs while rootsChanged has sessions.
+	stopPolling context.CancelFunc
+}
+
+func newWorkspaceWatcher(index *WorkspaceIndex, interval time.Duration) *workspaceWatcher {
+	return &workspaceWatcher{
+		index:        index,
+		interval:     interval,
+		rootsChanged: make(map[*mcp.ServerSession]chan struct{}),
+	}
+}
+
+// register adds the artifact resource templates to server and publishes artifacts to it.
+func (w *workspaceWatcher) register(server *mcp.Server) {
+	w.mu.Lock()
+	w.server = server
+	w.mu.Unlock()
+
+	server.AddResourceTemplate(ResourceWorkspaceArtifact, w.read)
+	server.AddResourceTemplate(ResourceWorkspaceControl, w.read)
+}
+
+// read wraps ReadWorkspaceArtifact with access to the workspace index.
+func (w *workspaceWatcher) read(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	return ReadWorkspaceArtifact(ctx, req, w.index)
+}
+
+// initialized starts watching the workspace of a client once its session is initialized.
+// Clients that do not support roots have no workspace to watch.
+func (w *workspaceWatcher) initialized(_ context.Context, req *mcp.InitializedRequest) {
+	session := req.Session
+	if params := session.InitializeParams(); params == nil || params.Capabilities == nil || params.Capabilities.RootsV2 == nil {
+		return
+	}
+
+	changed := make(chan struct{}, 1)
+	w.mu.Lock()
+	w.rootsChanged[session] = changed
+	if w.stopPolling == nil {
+		ctx, stop := context.WithCancel(context.Background())
+		w.stopPolling = stop
+		go w.poll(ctx)
+	}
+	w.mu.Unlock()
+
+	// Requests to the client cannot be made from a notification handler, which would block
+	// the session until they return, so the workspace is indexed in the background.
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), reporterKey{}, &reporter{session: session}))
+	go func() {
+		_ = session.Wait()
+		cancel()
+		w.mu.Lock()
+		delete(w.rootsChanged, session)
+		if len(w.rootsChanged) == 0 && w.stopPolling != nil {
+			w.stopPolling()
+			w.stopPolling = nil
+		}
+		w.mu.Unlock()
+
+		changes, err := w.index.Drop(context.Background(), session)
+		if err == nil {
+			w.publish(context.Background(), changes)
+		}
+	}()
+	go w.watch(ctx, session, changed)
+}
+
+// rootsListChanged rescans the workspace of a client whose roots changed.
+func (w *workspaceWatcher) rootsListChanged(_ context.Context, req *mcp.RootsListChangedRequest) {
+	w.mu.Lock()
+	changed := w.rootsChanged[req.Session]
+	w.mu.Unlock()
+	if changed == nil {
+		return
+	}
+	select {
+	case changed <- struct{}{}:
+	default:
+	}
+}
+
+// watch indexes the roots of session and rescans them when they change until ctx is done.
+func (w *workspaceWatcher) watch(ctx context.Context, session *mcp.ServerSession, rootsChanged <-chan struct{}) {
+	w.scan(ctx, session)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-rootsChanged:
+			w.scan(ctx, session)
+		}
+	}
+}
+
+// poll rescans the files of the index that changed on disk at every interval until ctx is done.
+func (w *workspaceWatcher) poll(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changes, err := w.index.Refresh(ctx)
+			if err != nil {
+				w.log(ctx, logWarning, "failed to refresh workspace artifacts: %v", err)
+				continue
+			}
+			w.publish(ctx, changes)
+		}
+	}
+}
+
+// scan indexes the current roots of session. Clients without local roots have no
+// workspace to index.
+func (w *workspaceWatcher) scan(ctx context.Context, session *mcp.ServerSession) {
+	roots, err := workspaceRoots(ctx, session)
+	if err != nil {
+		return
+	}
+	changes, err := w.index.ScanSession(ctx, session, roots)
+	if err != nil {
+		reporterFrom(ctx).log(ctx, logWarning, "failed to index workspace artifacts: %v", err)
+		return
+	}
+	w.publish(ctx, changes)
+}
+
+// publish updates the artifact resources of the server after an index change and notifies
+// subscribers of updated and removed artifacts and of the controls they declared before or
+// declare after the change.
+func (w *workspaceWatcher) publish(ctx context.Context, changes IndexChanges) {
+	if changes.Empty() {
+		return
+	}
+	w.mu.Lock()
+	server := w.server
+	w.mu.Unlock()
+	if server == nil {
+		return
+	}
+
+	var updated []string
+	for _, id := range slices.Concat(changes.Added, changes.Updated) {
+		artifact, ok := w.index.Artifact(id)
+		if !ok {
+			continue
+		}
+		server.AddResource(&mcp.Resource{
+			URI:         artifactURI(id),
+			Name:        id,
+			Title:       artifact.Title,
+			Description: fmt.Sprintf("%s at %s", artifact.Definition, artifact.Path),
+			MIMEType:    artifactMIMEType(artifact.Path),
+		}, w.read)
+	}
+	for _, id := range changes.Updated {
+		updated = append(updated, artifactURI(id))
+		controls := slices.Clone(changes.PreviousControlIDs[id])
+		if artifact, ok := w.index.Artifact(id); ok {
+			controls = slices.Concat(controls, artifact.ControlIDs)
+		}
+		slices.Sort(controls)
+		for _, controlID := range slices.Compact(controls) {
+			updated = append(updated, controlURI(id, controlID))
+		}
+	}
+	var removed []string
+	for _, id := range changes.Removed {
+		removed = append(removed, artifactURI(id))
+	}
+	server.RemoveResources(removed...)
+	for _, id := range changes.Removed {
+		for _, controlID := range changes.PreviousControlIDs[id] {
+			removed = append(removed, controlURI(id, controlID))
+		}
+	}
+
+	for _, uri := range slices.Concat(updated, removed) {
+		_ = server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+	}
+	w.log(ctx, logInfo, "workspace artifacts changed: %d added, %d updated, %d removed",
+		len(changes.Added), len(changes.Updated), len(changes.Removed))
+}
+
+// log sends a log message to every watched session, as the artifact resources are shared by all.
+func (w *workspaceWatcher) log(ctx context.Context, level mcp.LoggingLevel, format string, args ...any) {
+	w.mu.Lock()
+	sessions := slices.Collect(maps.Keys(w.rootsChanged))
+	w.mu.Unlock()
+	for _, session := range sessions {
+		(&reporter{session: session}).log(ctx, level, format, args...)
+	}
+}
+
+// subscribe accepts subscriptions to any resource; the server tracks subscribers and
+// publish notifies them of changed artifacts and controls.
+func (w *workspaceWatcher) subscribe(context.Context, *mcp.SubscribeRequest) error {
+	return nil
+}
+
+func (w *workspaceWatcher) unsubscribe(context.Context, *mcp.UnsubscribeRequest) error {
+	return nil
+}
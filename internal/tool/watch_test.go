@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resourceEvents records the resource notifications a client receives.
+type resourceEvents struct {
+	mu      sync.Mutex
+	updated []string
+}
+
+func (e *resourceEvents) clientOptions() *mcp.ClientOptions {
+	return &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.updated = append(e.updated, req.Params.URI)
+		},
+	}
+}
+
+func (e *resourceEvents) updatedURIs() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.updated...)
+}
+
+// resourceURIs lists the URIs of the resources of session.
+func resourceURIs(t *testing.T, session *mcp.ClientSession) []string {
+	t.Helper()
+	result, err := session.ListResources(context.Background(), nil)
+	require.NoError(t, err)
+	var uris []string
+	for _, resource := range result.Resources {
+		uris = append(uris, resource.URI)
+	}
+	return uris
+}
+
+func TestWorkspaceResources(t *testing.T) {
+	dir := newTestWorkspace(t)
+	root := &mcp.Root{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String()}
+	ctx := context.Background()
+
+	var events resourceEvents
+	mode := newTestAdvisoryMode(t, WithWorkspacePollInterval(10*time.Millisecond))
+	session := newTestClientSession(t, mode, events.clientOptions(), root)
+
+	require.Eventually(t, func() bool { return len(resourceURIs(t, session)) == 3 }, 5*time.Second, 10*time.Millisecond,
+		"workspace artifacts should be published once the session is initialized")
+	assert.ElementsMatch(t, []string{
+		"gemara://artifact/FINOS-CCC",
+		"gemara://artifact/OS-THREATS",
+		"gemara://artifact/nightly-2026-01-01",
+	}, resourceURIs(t, session))
+
+	artifact, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "gemara://artifact/nightly-2026-01-01"})
+	require.NoError(t, err)
+	require.Len(t, artifact.Contents, 1)
+	assert.Equal(t, "application/json", artifact.Contents[0].MIMEType)
+	assert.Equal(t, evaluationLogJSON, artifact.Contents[0].Text)
+
+	control, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "gemara://artifact/FINOS-CCC/controls/CCC.C01"})
+	require.NoError(t, err)
+	require.Len(t, control.Contents, 1)
+	assert.Equal(t, "application/yaml", control.Contents[0].MIMEType)
+	assert.Contains(t, control.Contents[0].Text, "title: Prevent Unencrypted Requests")
+	assert.NotContains(t, control.Contents[0].Text, "CCC.C06")
+
+	_, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "gemara://artifact/FINOS-CCC/controls/CCC.C99"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Resource not found")
+
+	for _, uri := range []string{"gemara://artifact/OS-THREATS", "gemara://artifact/nightly-2026-01-01"} {
+		require.NoError(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}))
+	}
+	threats := filepath.Join(dir, "threats", "object-storage.yml")
+	require.NoError(t, os.WriteFile(threats, []byte(strings.Replace(threatCatalogV010, "Data Exfiltration", "Bulk Data Exfiltration", 1)), 0o600))
+	require.NoError(t, os.Remove(filepath.Join(dir, "evaluations", "nightly.json")))
+
+	require.Eventually(t, func() bool { return len(events.updatedURIs()) == 2 }, 5*time.Second, 10*time.Millisecond,
+		"subscribers should be notified of changed files")
+	assert.ElementsMatch(t, []string{"gemara://artifact/OS-THREATS", "gemara://artifact/nightly-2026-01-01"}, events.updatedURIs())
+	assert.ElementsMatch(t, []string{"gemara://artifact/FINOS-CCC", "gemara://artifact/OS-THREATS"}, resourceURIs(t, session))
+
+	updated, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "gemara://artifact/OS-THREATS"})
+	require.NoError(t, err)
+	assert.Contains(t, updated.Contents[0].Text, "Bulk Data Exfiltration")
+}
+
+func TestWorkspaceResourcesControls(t *testing.T) {
+	dir := newTestWorkspace(t)
+	root := &mcp.Root{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String()}
+	ctx := context.Background()
+
+	var events resourceEvents
+	mode := newTestAdvisoryMode(t, WithWorkspacePollInterval(10*time.Millisecond))
+	session := newTestClientSession(t, mode, events.clientOptions(), root)
+	require.Eventually(t, func() bool { return len(resourceURIs(t, session)) == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	for _, uri := range []string{"gemara://artifact/FINOS-CCC/controls/CCC.C01", "gemara://artifact/FINOS-CCC/controls/CCC.C06"} {
+		require.NoError(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}))
+	}
+
+	// Renaming CCC.C06 deletes it from the catalog.
+	catalog := filepath.Join(dir, "catalogs", "ccc.yaml")
+	content, err := os.ReadFile(catalog)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(catalog, []byte(strings.Replace(string(content), "- id: CCC.C06\n", "- id: CCC.C07\n", 1)), 0o600))
+	require.Eventually(t, func() bool { return len(events.updatedURIs()) == 2 }, 5*time.Second, 10*time.Millisecond,
+		"subscribers to deleted and remaining controls should be notified")
+	assert.ElementsMatch(t, []string{
+		"gemara://artifact/FINOS-CCC/controls/CCC.C01",
+		"gemara://artifact/FINOS-CCC/controls/CCC.C06",
+	}, events.updatedURIs())
+
+	require.NoError(t, os.Remove(catalog))
+	require.Eventually(t, func() bool { return len(events.updatedURIs()) == 3 }, 5*time.Second, 10*time.Millisecond,
+		"subscribers to the controls of a removed artifact should be notified")
+	assert.Equal(t, "gemara://artifact/FINOS-CCC/controls/CCC.C01", events.updatedURIs()[2])
+}
+
+func TestWorkspaceResourcesRootsChanged(t *testing.T) {
+	dir := newTestWorkspace(t)
+	ctx := context.Background()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, nil)
+	mode := newTestAdvisoryMode(t, WithWorkspacePollInterval(time.Hour))
+	server := mcp.NewServer(&mcp.Implementation{Name: "gemara-mcp", Version: "test"}, mode.ServerOptions())
+	mode.Register(server)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	assert.Never(t, func() bool { return len(resourceURIs(t, session)) > 0 }, 100*time.Millisecond, 10*time.Millisecond,
+		"no artifacts should be published without roots")
+
+	client.AddRoots(&mcp.Root{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, "catalogs"))}).String()})
+	require.Eventually(t, func() bool { return len(resourceURIs(t, session)) == 1 }, 5*time.Second, 10*time.Millisecond,
+		"artifacts under new roots should be published")
+	assert.Equal(t, []string{"gemara://artifact/FINOS-CCC"}, resourceURIs(t, session))
+}
+
+func TestWorkspaceResourcesSessions(t *testing.T) {
+	dir := newTestWorkspace(t)
+	ctx := context.Background()
+
+	mode := newTestAdvisoryMode(t, WithWorkspacePollInterval(10*time.Millisecond))
+	server := mcp.NewServer(&mcp.Implementation{Name: "gemara-mcp", Version: "test"}, mode.ServerOptions())
+	mode.Register(server)
+	connect := func(root string) *mcp.ClientSession {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = serverSession.Close() })
+		client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, nil)
+		client.AddRoots(&mcp.Root{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, root))}).String()})
+		session, err := client.Connect(ctx, clientTransport, nil)
+		require.NoError(t, err)
+		return session
+	}
+
+	catalogs := connect("catalogs")
+	require.Eventually(t, func() bool { return len(resourceURIs(t, catalogs)) == 1 }, 5*time.Second, 10*time.Millisecond)
+	mode.watcher.mu.Lock()
+	poller := mode.watcher.stopPolling
+	mode.watcher.mu.Unlock()
+	require.NotNil(t, poller, "polling should start with the first session")
+	threats := connect("threats")
+	require.Eventually(t, func() bool { return len(resourceURIs(t, catalogs)) == 2 }, 5*time.Second, 10*time.Millisecond,
+		"the artifacts of each session's roots should be published")
+	assert.Never(t, func() bool { return len(resourceURIs(t, threats)) != 2 }, 100*time.Millisecond, 10*time.Millisecond,
+		"polling sessions should not replace each other's artifacts")
+
+	require.NoError(t, threats.Close())
+	require.Eventually(t, func() bool { return len(resourceURIs(t, catalogs)) == 1 }, 5*time.Second, 10*time.Millisecond,
+		"the artifacts of a closed session should be removed")
+	assert.Equal(t, []string{"gemara://artifact/FINOS-CCC"}, resourceURIs(t, catalogs))
+	require.NoError(t, catalogs.Close())
+	require.Eventually(t, func() bool {
+		mode.watcher.mu.Lock()
+		defer mode.watcher.mu.Unlock()
+		return mode.watcher.stopPolling == nil
+	}, 5*time.Second, 10*time.Millisecond, "polling should stop once no session is connected")
+}
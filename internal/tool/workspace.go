@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"github.com/goccy/go-yaml"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxArtifactSize is the size above which workspace files are not considered artifacts.
+const maxArtifactSize = 5 << 20
+
+// skippedDirs are directories that never hold workspace artifacts.
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// MetadataListWorkspaceArtifacts describes the ListWorkspaceArtifacts tool.
+var MetadataListWorkspaceArtifacts = &mcp.Tool{
+	Name:         "list_workspace_artifacts",
+	Description:  "Scan the client's workspace roots for YAML and JSON files, detect which Gemara definition each artifact implements, and report its path, type, metadata ID, title and validity.",
+	InputSchema:  schemaFor[InputListWorkspaceArtifacts](),
+	OutputSchema: schemaFor[OutputListWorkspaceArtifacts](),
+	Annotations:  readOnlyAnnotations(false),
+}
+
+// InputListWorkspaceArtifacts is the input for the ListWorkspaceArtifacts tool.
+type InputListWorkspaceArtifacts struct {
+	Version    string `json:"version,omitempty" jsonschema:"Version of the Gemara module to detect and validate artifacts with (default: 'latest')"`
+	Definition string `json:"definition,omitempty" jsonschema:"Only list artifacts of this definition (e.g., '#ControlCatalog')"`
+}
+
+// OutputListWorkspaceArtifacts is the output for the ListWorkspaceArtifacts tool.
+type OutputListWorkspaceArtifacts struct {
+	Version string `json:"version"`
+	// Roots are the workspace directories that were scanned.
+	Roots     []string            `json:"roots"`
+	Artifacts []WorkspaceArtifact `json:"artifacts"`
+}
+
+// WorkspaceArtifact is a Gemara artifact found in the workspace.
+type WorkspaceArtifact struct {
+	Path string `json:"path"`
+	// Definition is the detected root definition of the artifact.
+	Definition string   `json:"definition"`
+	ID         string   `json:"id,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Valid      bool     `json:"valid"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// ListWorkspaceArtifacts scans the roots of the requesting client for artifacts, detecting
+// and validating them with the module loaded by the specified schema loader.
+func ListWorkspaceArtifacts(ctx context.Context, req *mcp.CallToolRequest, input InputListWorkspaceArtifacts, loader *SchemaLoader) (*mcp.CallToolResult, OutputListWorkspaceArtifacts, error) {
+	if req == nil || req.Session == nil {
+		return nil, OutputListWorkspaceArtifacts{}, fmt.Errorf("workspace roots are only available to MCP clients")
+	}
+	roots, err := workspaceRoots(ctx, req.Session)
+	if err != nil {
+		return nil, OutputListWorkspaceArtifacts{}, err
+	}
+
+	schema, err := loader.Load(ctx, input.Version, false)
+	if err != nil {
+		return nil, OutputListWorkspaceArtifacts{}, err
+	}
+	artifacts, err := ScanWorkspace(ctx, schema, roots)
+	if err != nil {
+		return nil, OutputListWorkspaceArtifacts{}, err
+	}
+
+	if input.Definition != "" {
+		definition := normalizeDefinitionName(input.Definition)
+		artifacts = slices.DeleteFunc(artifacts, func(a WorkspaceArtifact) bool {
+			return a.Definition != definition
+		})
+	}
+
+	return nil, OutputListWorkspaceArtifacts{
+		Version:   schema.Version,
+		Roots:     roots,
+		Artifacts: artifacts,
+	}, nil
+}
+
+// workspaceRoots returns the local directories among the roots the client exposes.
+func workspaceRoots(ctx context.Context, session *mcp.ServerSession) ([]string, error) {
+	result, err := session.ListRoots(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspace roots: %w", err)
+	}
+
+	var roots []string
+	for _, root := range result.Roots {
+		u, err := url.Parse(root.URI)
+		if err != nil || u.Scheme != "file" {
+			continue
+		}
+		roots = append(roots, filepath.FromSlash(u.Path))
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("the client exposes no file:// workspace roots")
+	}
+	return roots, nil
+}
+
+// ScanWorkspace detects and validates the Gemara artifacts under the given directories.
+// Hidden directories and dependency directories are skipped, as are files that are not
+// YAML or JSON mappings with a metadata field matching a definition of the schema.
+// Artifacts are ordered by path.
+func ScanWorkspace(ctx context.Context, schema *Schema, dirs []string) ([]WorkspaceArtifact, error) {
+	defs, err := artifactDefinitions(schema.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, dir := range dirs {
+		files, err := artifactFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, files...)
+	}
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+
+	reporterFrom(ctx).expect(len(paths))
+
+	artifacts := []WorkspaceArtifact{}
+	for _, path := range paths {
+		artifact, _, ok, err := scanArtifactFile(ctx, schema, defs, path)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	return artifacts, nil
+}
+
+// scanArtifactFile detects and validates the artifact of a workspace file, returning it
+// with the file content. Files that cannot be read or are not artifacts are reported and
+// skipped.
+func scanArtifactFile(ctx context.Context, schema *Schema, defs []artifactDefinition, path string) (WorkspaceArtifact, []byte, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return WorkspaceArtifact{}, nil, false, err
+	}
+	r := reporterFrom(ctx)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		r.log(ctx, logWarning, "skipping %s: %v", path, err)
+		return WorkspaceArtifact{}, nil, false, nil
+	}
+
+	artifact, ok := detectArtifact(content, defs)
+	if !ok {
+		r.step(ctx, "skipping %s: not a Gemara artifact", path)
+		return WorkspaceArtifact{}, nil, false, nil
+	}
+	artifact.Path = path
+
+	validation, err := validateArtifact(ctx, schema, artifact.Definition, path, string(content))
+	if err != nil {
+		return WorkspaceArtifact{}, nil, false, err
+	}
+	artifact.Valid = validation.Valid
+	artifact.Errors = validation.Errors
+	return artifact, content, true, nil
+}
+
+// artifactFiles lists the YAML and JSON files under dir.
+func artifactFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && (strings.HasPrefix(d.Name(), ".") || skippedDirs[d.Name()]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxArtifactSize {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// artifactDefinition is a root definition that artifacts can implement.
+type artifactDefinition struct {
+	name     string
+	fields   map[string]bool
+	required []string
+}
+
+// artifactDefinitions returns the definitions of the schema with a metadata field,
+// which are the definitions whole artifacts implement.
+func artifactDefinitions(schema cue.Value) ([]artifactDefinition, error) {
+	it, err := schema.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schema definitions: %w", err)
+	}
+
+	var defs []artifactDefinition
+	for it.Next() {
+		if !it.Selector().IsDefinition() {
+			continue
+		}
+		fields := structFields(it.Value())
+		if _, ok := fields.values["metadata"]; !ok {
+			continue
+		}
+		def := artifactDefinition{name: it.Selector().String(), fields: make(map[string]bool)}
+		for _, name := range fields.names {
+			def.fields[name] = true
+			if fields.values[name].doc.Required {
+				def.required = append(def.required, name)
+			}
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// detectArtifact identifies the definition content implements from its top-level fields.
+// The definition with the most matching fields wins, preferring definitions whose required
+// fields are all present. Content that matches several definitions equally well, such as a
+// lone metadata block, is not taken for any of them.
+func detectArtifact(content []byte, defs []artifactDefinition) (WorkspaceArtifact, bool) {
+	var doc struct {
+		Title    string `yaml:"title"`
+		Metadata struct {
+			ID    string `yaml:"id"`
+			Title string `yaml:"title"`
+		} `yaml:"metadata"`
+	}
+	var fields map[string]any
+	if err := yaml.Unmarshal(content, &fields); err != nil || fields["metadata"] == nil {
+		return WorkspaceArtifact{}, false
+	}
+	// Malformed metadata leaves the ID and title empty; validation reports it.
+	_ = yaml.Unmarshal(content, &doc)
+
+	var (
+		best      *detection
+		ambiguous bool
+	)
+	for _, def := range defs {
+		d := detection{definition: def.name}
+		for name := range fields {
+			if def.fields[name] {
+				d.matched++
+			}
+		}
+		for _, name := range def.required {
+			if _, ok := fields[name]; !ok {
+				d.missing++
+			}
+		}
+		switch {
+		case best == nil || d.beats(*best):
+			best, ambiguous = &d, false
+		case !best.beats(d):
+			ambiguous = true
+		}
+	}
+	if best == nil || ambiguous {
+		return WorkspaceArtifact{}, false
+	}
+
+	title := doc.Title
+	if title == "" {
+		title = doc.Metadata.Title
+	}
+	return WorkspaceArtifact{Definition: best.definition, ID: doc.Metadata.ID, Title: title}, true
+}
+
+// detection is how well an artifact's top-level fields match a definition.
+type detection struct {
+	definition string
+	// matched counts the fields the definition declares, missing its absent required fields.
+	matched, missing int
+}
+
+// beats reports whether d is a better match than other: definitions with all required
+// fields present come first, then those with more matching and fewer missing fields.
+func (d detection) beats(other detection) bool {
+	if complete, otherComplete := d.missing == 0, other.missing == 0; complete != otherComplete {
+		return complete
+	}
+	if d.matched != other.matched {
+		return d.matched > other.matched
+	}
+	return d.missing < other.missing
+}
+
+// normalizeDefinitionName adds the leading '#' of a definition name if it is missing.
+func normalizeDefinitionName(definition string) string {
+	if !strings.HasPrefix(definition, "#") {
+		return "#" + definition
+	}
+	return definition
+}
@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// evaluationLogJSON is valid against v0.1.0 and v0.2.0 of the stand-in schema.
+const evaluationLogJSON = `{
+  "metadata": {"id": "nightly-2026-01-01", "description": "Nightly evaluation", "author": {"id": "ci", "name": "CI"}},
+  "catalog-id": "FINOS-CCC",
+  "evaluations": [{
+    "name": "Encryption in transit",
+    "control-id": "CCC.C01",
+    "result": "Passed",
+    "message": "All endpoints require TLS 1.2",
+    "assessment-logs": [{
+      "requirement-id": "CCC.C01.TR01",
+      "description": "TLS handshake on exposed ports",
+      "result": "Passed",
+      "message": "ok",
+      "start": "2026-01-01T00:00:00Z"
+    }]
+  }]
+}`
+
+// newTestWorkspace creates a workspace with artifacts of several definitions alongside
+// files that must be skipped.
+func newTestWorkspace(t *testing.T) string {
+	t.Helper()
+	catalog, err := os.ReadFile(filepath.Join("testdata", "good-ccc.yaml"))
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"catalogs/ccc.yaml":            string(catalog),
+		"threats/object-storage.yml":   threatCatalogV010,
+		"evaluations/nightly.json":     evaluationLogJSON,
+		"notes.yaml":                   "owner: security-team\n",
+		"metadata-only.yaml":           "metadata:\n  id: lonely\n",
+		"broken.yaml":                  "metadata: [\n",
+		"README.md":                    "# Workspace\n",
+		".github/workflows/ci.yaml":    string(catalog),
+		"node_modules/pkg/catalog.yml": string(catalog),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return dir
+}
+
+func TestScanWorkspace(t *testing.T) {
+	dir := newTestWorkspace(t)
+	schema, err := newTestSchemaLoader(t).Load(context.Background(), "v0.2.0", false)
+	require.NoError(t, err)
+
+	artifacts, err := ScanWorkspace(context.Background(), schema, []string{dir, filepath.Join(dir, "catalogs")})
+	require.NoError(t, err)
+	require.Len(t, artifacts, 3, "hidden, dependency and non-artifact files should be skipped, duplicates listed once")
+
+	assert.Equal(t, WorkspaceArtifact{
+		Path:       filepath.Join(dir, "catalogs", "ccc.yaml"),
+		Definition: "#ControlCatalog",
+		ID:         "FINOS-CCC",
+		Title:      "FINOS Cloud Control Catalog",
+		Valid:      true,
+		Errors:     []string{},
+	}, artifacts[0])
+
+	assert.Equal(t, filepath.Join(dir, "evaluations", "nightly.json"), artifacts[1].Path)
+	assert.Equal(t, "#EvaluationLog", artifacts[1].Definition)
+	assert.Equal(t, "nightly-2026-01-01", artifacts[1].ID)
+	assert.True(t, artifacts[1].Valid, "JSON artifacts should be validated: %v", artifacts[1].Errors)
+
+	assert.Equal(t, filepath.Join(dir, "threats", "object-storage.yml"), artifacts[2].Path)
+	assert.Equal(t, "#ThreatCatalog", artifacts[2].Definition)
+	assert.Equal(t, "Object Storage Threats", artifacts[2].Title)
+	assert.False(t, artifacts[2].Valid, "v0.1.0 threat catalog should not validate against v0.2.0")
+	assert.NotEmpty(t, artifacts[2].Errors)
+}
+
+func TestScanWorkspaceErrors(t *testing.T) {
+	schema, err := newTestSchemaLoader(t).Load(context.Background(), "v0.2.0", false)
+	require.NoError(t, err)
+
+	_, err = ScanWorkspace(context.Background(), schema, []string{filepath.Join(t.TempDir(), "missing")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to scan")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ScanWorkspace(ctx, schema, []string{newTestWorkspace(t)})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDetectArtifact(t *testing.T) {
+	schema, err := newTestSchemaLoader(t).Load(context.Background(), "v0.2.0", false)
+	require.NoError(t, err)
+	defs, err := artifactDefinitions(schema.Value)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		content        string
+		wantDefinition string
+		wantTitle      string
+	}{
+		{
+			name:           "required fields decide between candidates",
+			content:        "metadata: {id: plan}\ntitle: Plan\npolicy-id: P1\ncontrols: [CCC.C01]\n",
+			wantDefinition: "#EvaluationPlan",
+			wantTitle:      "Plan",
+		},
+		{
+			name:           "incomplete artifact is still detected",
+			content:        "metadata: {id: guidance, title: Cloud Guidance}\nguidelines: []\n",
+			wantDefinition: "#GuidanceDocument",
+			wantTitle:      "Cloud Guidance",
+		},
+		{
+			name:    "only shared fields",
+			content: "metadata: {id: x}\ntitle: Something\n",
+		},
+		{
+			name:    "no metadata",
+			content: "title: Catalog\ncontrols: []\n",
+		},
+		{
+			name:    "not a mapping",
+			content: "- metadata: {}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifact, ok := detectArtifact([]byte(tt.content), defs)
+			if tt.wantDefinition == "" {
+				assert.False(t, ok, "should not be detected as %s", artifact.Definition)
+				return
+			}
+			require.True(t, ok, "should be detected")
+			assert.Equal(t, tt.wantDefinition, artifact.Definition)
+			assert.Equal(t, tt.wantTitle, artifact.Title)
+		})
+	}
+}
+
+func TestListWorkspaceArtifacts(t *testing.T) {
+	dir := newTestWorkspace(t)
+	root := &mcp.Root{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String()}
+	ctx := context.Background()
+
+	// The workspace index loads the schema once the session is initialized, so load it
+	// up front for the tool's progress to be the same whichever loads it first.
+	mode := newTestAdvisoryMode(t)
+	_, err := mode.schemas.Load(ctx, "", false)
+	require.NoError(t, err)
+
+	var received notifications
+	session := newTestClientSession(t, mode, received.clientOptions(), root, &mcp.Root{URI: "https://example.com/remote"})
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Meta:      mcp.Meta{"progressToken": "scan"},
+		Name:      "list_workspace_artifacts",
+		Arguments: map[string]any{"definition": "ControlCatalog"},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "%v", result.Content)
+
+	// Every candidate file is a step of the batch, after the latest version is resolved.
+	require.NoError(t, session.Ping(ctx, nil))
+	assert.Eventually(t, func() bool {
+		progress, _ := received.messages()
+		return len(progress) == 7
+	}, time.Second, 10*time.Millisecond)
+	received.mu.Lock()
+	last := received.progress[len(received.progress)-1]
+	received.mu.Unlock()
+	assert.Equal(t, float64(7), last.Progress)
+	assert.Equal(t, float64(7), last.Total, "batch progress should announce its total")
+
+	output := result.StructuredContent.(map[string]any)
+	assert.Equal(t, "v0.2.0", output["version"])
+	assert.Equal(t, []any{dir}, output["roots"], "non-file roots should be ignored")
+	artifacts := output["artifacts"].([]any)
+	require.Len(t, artifacts, 1)
+	assert.Equal(t, "FINOS-CCC", artifacts[0].(map[string]any)["id"])
+
+	t.Run("client without file roots", func(t *testing.T) {
+		session := newTestSession(t, newTestAdvisoryMode(t))
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "list_workspace_artifacts"})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "no file:// workspace roots")
+	})
+}